@@ -0,0 +1,44 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/stretchr/testify/require"
+)
+
+// TestXBuild_Reproducible runs xbuild twice against the same commit and
+// asserts every platform's .sha256sum sidecar is byte-identical across both
+// runs, the same property verifyReproducible checks for the single-platform
+// build. Requires a Dagger engine, so it's gated behind -tags=integration
+// like the rest of this mixin's integration suite.
+func TestXBuild_Reproducible(t *testing.T) {
+	ctx := context.Background()
+	client, err := dagger.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	outputA := t.TempDir()
+	outputB := t.TempDir()
+
+	require.NoError(t, xbuild(ctx, client, "test", outputA))
+	require.NoError(t, xbuild(ctx, client, "test", outputB))
+
+	matches, err := filepath.Glob(filepath.Join(outputA, "*.sha256sum"))
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+
+	for _, sidecarA := range matches {
+		name := filepath.Base(sidecarA)
+		contentA, err := os.ReadFile(sidecarA) // #nosec G304 -- path comes from Glob over this test's own temp directory
+		require.NoError(t, err)
+		contentB, err := os.ReadFile(filepath.Join(outputB, name)) // #nosec G304 -- path comes from Glob over this test's own temp directory
+		require.NoError(t, err)
+		require.Equal(t, string(contentA), string(contentB), "checksum for %s differed between consecutive xbuild runs", name)
+	}
+}
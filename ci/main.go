@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"flag" // Import flag package
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings" // Ensure strings is imported
 
 	"dagger.io/dagger"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
 )
 
 // Define constants for Go version and image
@@ -19,10 +25,147 @@ const (
 	// goreleaserVersion constant removed
 )
 
+// buildMetadata carries the commit information every reproducible build
+// stamps into its binary and uses as SOURCE_DATE_EPOCH, so that building the
+// same commit twice (even in unrelated containers) produces identical bytes.
+type buildMetadata struct {
+	commit string
+	epoch  string
+}
+
+// resolveBuildMetadata execs git inside src (which must have the project
+// source mounted and git installed) to resolve the current commit SHA and its
+// committer timestamp.
+func resolveBuildMetadata(ctx context.Context, src *dagger.Container) (buildMetadata, error) {
+	sha, err := src.WithExec([]string{"git", "log", "-1", "--format=%H"}).Stdout(ctx)
+	if err != nil {
+		return buildMetadata{}, fmt.Errorf("failed to resolve commit sha: %w", err)
+	}
+	epoch, err := src.WithExec([]string{"git", "log", "-1", "--format=%ct"}).Stdout(ctx)
+	if err != nil {
+		return buildMetadata{}, fmt.Errorf("failed to resolve commit timestamp: %w", err)
+	}
+	return buildMetadata{commit: strings.TrimSpace(sha), epoch: strings.TrimSpace(epoch)}, nil
+}
+
+// withReproducibleBuildEnv installs git (needed by resolveBuildMetadata),
+// resolves the commit metadata, and sets SOURCE_DATE_EPOCH inside ctr so that
+// every tool in the build (not just `go build`) observes the same fixed
+// timestamp.
+func withReproducibleBuildEnv(ctx context.Context, ctr *dagger.Container) (*dagger.Container, buildMetadata, error) {
+	ctr = ctr.
+		WithExec([]string{"apt-get", "update"}).
+		WithExec([]string{"apt-get", "install", "-y", "git"})
+	meta, err := resolveBuildMetadata(ctx, ctr)
+	if err != nil {
+		return nil, buildMetadata{}, err
+	}
+	return ctr.WithEnvVariable("SOURCE_DATE_EPOCH", meta.epoch), meta, nil
+}
+
+// reproducibleGoBuildArgs returns the go build invocation shared by every
+// build path in this package: stripped symbols, no build ID, no VCS stamping
+// (we stamp explicitly instead), and trimmed paths so the output doesn't
+// embed the Dagger container's working directory.
+func reproducibleGoBuildArgs(outputPath, pkgPath, version string, meta buildMetadata) []string {
+	ldflags := fmt.Sprintf("-s -w -buildid= -X main.version=%s -X main.commit=%s -X main.date=%s",
+		version, meta.commit, meta.epoch)
+	return []string{
+		"go", "build",
+		"-trimpath",
+		"-buildvcs=false",
+		"-ldflags", ldflags,
+		"-o", outputPath,
+		pkgPath,
+	}
+}
+
+// Skeletor exposes this package's pipeline steps as typed functions so other
+// Dagger pipelines can import and chain them (e.g. `dagger call build
+// --platform=linux/arm64 export --path=./out`) once this module is registered
+// via `dagger.json`. It wraps the same helpers the classic `go run ./ci
+// <command>` CLI below uses, so both entry points stay in sync.
+type Skeletor struct{}
+
+// Test runs the linters and unit test suite against the given client.
+func (s *Skeletor) Test(ctx context.Context, client *dagger.Client) error {
+	return test(ctx, client)
+}
+
+// Build cross-compiles the mixin for platform and returns the directory
+// containing the resulting archive so callers can chain further steps on it.
+func (s *Skeletor) Build(ctx context.Context, client *dagger.Client, platform dagger.Platform) (*dagger.Directory, error) {
+	parts := strings.SplitN(string(platform), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid platform %q, expected format os/arch", platform)
+	}
+	outputDir := "build-output"
+	if err := build(ctx, client, parts[0], parts[1], outputDir); err != nil {
+		return nil, err
+	}
+	return client.Host().Directory(outputDir), nil
+}
+
+// Release runs the full release pipeline (goreleaser, SLSA provenance, image
+// publishing) using githubToken for authentication.
+func (s *Skeletor) Release(ctx context.Context, client *dagger.Client, githubToken *dagger.Secret, provenanceMode string) error {
+	token, err := githubToken.Plaintext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve GitHub token secret: %w", err)
+	}
+	return release(ctx, client, token, provenanceMode)
+}
+
+// Validate generates sample mixins at varying compliance levels and runs the
+// standard build/test/lint/scan checks against each of them across the
+// targets declared in targets.yaml. targetsOverride accepts the same
+// "os/arch:distro@version,..." shorthand as -targets; pass "" to use
+// targets.yaml (or its built-in default).
+func (s *Skeletor) Validate(ctx context.Context, client *dagger.Client, targetsOverride string) error {
+	return ValidateGeneratedMixin(ctx, client, targetsOverride)
+}
+
+// ValidateMatrix cross-compiles the mixin for every supported target platform
+// and then runs the standard validation pass, catching build failures that
+// are specific to a non-host GOOS/GOARCH before Validate's single-platform
+// checks would ever see them.
+func (s *Skeletor) ValidateMatrix(ctx context.Context, client *dagger.Client) error {
+	if err := buildMatrix(ctx, client, "dev", "build-output", true); err != nil {
+		return fmt.Errorf("matrix build failed: %w", err)
+	}
+	return s.Validate(ctx, client, "")
+}
+
+// commands maps each subcommand name to its handler, mirroring the dispatcher
+// pattern used by go-ethereum's build/ci.go. Each handler owns its own flag set
+// so commands can be invoked independently in CI to fan out per-platform jobs.
+var commands = map[string]func(ctx context.Context, client *dagger.Client, args []string) error{
+	"ci":                  cmdCI,
+	"test":                cmdTest,
+	"lint":                cmdLint,
+	"xbuild":              cmdXBuild,
+	"install":             cmdInstall,
+	"archive":             cmdArchive,
+	"debsrc":              cmdDebsrc,
+	"nsis":                cmdNsis,
+	"purge":               cmdPurge,
+	"release":             cmdRelease,
+	"validate":            cmdValidate,
+	"verify-reproducible": cmdVerifyReproducible,
+}
+
 func main() {
-	// Define flags
-	task := flag.String("task", "ci", "Task to run: ci, release, or validate") // Updated description
-	flag.Parse()
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Printf("Error: unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
 
 	ctx := context.Background()
 
@@ -34,62 +177,547 @@ func main() {
 	}
 	defer client.Close()
 
-	// Execute the requested task
-	switch *task {
-	case "ci":
-		fmt.Println("Running CI tasks (test & build)...")
-		if err := runCI(ctx, client); err != nil {
-			fmt.Printf("CI task failed: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("CI tasks completed successfully!")
-	case "release":
-		fmt.Println("Running Release task...")
-		// Get GITHUB_TOKEN from environment
-		githubToken := os.Getenv("GITHUB_TOKEN")
-		if githubToken == "" {
-			fmt.Println("Error: GITHUB_TOKEN environment variable is required for release")
-			os.Exit(1)
-		}
-		if err := release(ctx, client, githubToken); err != nil {
-			fmt.Printf("Release task failed: %v\n", err)
-			os.Exit(1)
+	if err := command(ctx, client, os.Args[2:]); err != nil {
+		fmt.Printf("%s failed: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: go run ./ci <command> [flags]")
+	fmt.Println("Commands:")
+	fmt.Println("  install   cross-compile the mixin for a target platform")
+	fmt.Println("  test      run linters and unit tests")
+	fmt.Println("  lint      run linters only")
+	fmt.Println("  xbuild    reproducibly cross-compile the mixin for every platform against a digest-pinned image")
+	fmt.Println("  archive   package a built binary into a signed zip/tar bundle")
+	fmt.Println("  debsrc    build a Debian source package")
+	fmt.Println("  nsis      build a Windows installer via NSIS")
+	fmt.Println("  purge     remove Dagger cache volumes")
+	fmt.Println("  ci        run the standard test+build pipeline")
+	fmt.Println("  release   run the full release pipeline (goreleaser, provenance, images)")
+	fmt.Println("  validate  generate sample mixins and validate them across targets.yaml's matrix")
+	fmt.Println("  verify-reproducible  build the mixin twice in isolated containers and diff the results")
+}
+
+// cmdCI adapts runCI to the command dispatcher signature.
+func cmdCI(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("ci", flag.ExitOnError)
+	full := fs.Bool("full", false, "build the full cross-compilation matrix, including the less common release-only targets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Println("Running CI tasks (test & build)...")
+	if err := runCI(ctx, client, *full); err != nil {
+		return err
+	}
+	fmt.Println("CI tasks completed successfully!")
+	return nil
+}
+
+// cmdTest adapts test to the command dispatcher signature.
+func cmdTest(ctx context.Context, client *dagger.Client, args []string) error {
+	return test(ctx, client)
+}
+
+// cmdLint runs linters and vulnerability checks independently of the unit tests.
+func cmdLint(ctx context.Context, client *dagger.Client, args []string) error {
+	fmt.Println("--> Running lint...")
+	src := projectSource(client)
+	golang := client.Container().From(goImage).
+		WithMountedCache("/go/pkg/mod", client.CacheVolume("go-mod")).
+		WithMountedCache("/go/build-cache", client.CacheVolume("go-build")).
+		WithEnvVariable("GOMODCACHE", "/go/pkg/mod").
+		WithEnvVariable("GOCACHE", "/go/build-cache").
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src).
+		WithExec([]string{"go", "install", "github.com/golangci/golangci-lint/cmd/golangci-lint@v1.61.0"})
+
+	_, err := golang.WithExec([]string{"golangci-lint", "run", "./..."}).Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("golangci-lint failed: %w", err)
+	}
+	return nil
+}
+
+// cmdInstall cross-compiles the mixin for a single target platform, mirroring
+// go-ethereum's "install" command.
+func cmdInstall(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	arch := fs.String("arch", "amd64", "Target GOARCH")
+	cc := fs.String("cc", "", "C compiler to use for CGO (optional)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	packages := fs.Args()
+	if len(packages) == 0 {
+		packages = []string{"./cmd/" + mixinName}
+	}
+
+	src := projectSource(client)
+	golang := client.Container().From(goImage).
+		WithMountedCache("/go/pkg/mod", client.CacheVolume("go-mod")).
+		WithMountedCache("/go/build-cache", client.CacheVolume("go-build")).
+		WithEnvVariable("GOMODCACHE", "/go/pkg/mod").
+		WithEnvVariable("GOCACHE", "/go/build-cache").
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src).
+		WithEnvVariable("GOARCH", *arch)
+
+	if *cc != "" {
+		golang = golang.WithEnvVariable("CC", *cc).WithEnvVariable("CGO_ENABLED", "1")
+	} else {
+		golang = golang.WithEnvVariable("CGO_ENABLED", "0")
+	}
+
+	buildArgs := append([]string{"go", "build", "-o", "bin/" + mixinName}, packages...)
+	_, err := golang.WithExec(buildArgs).Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("install failed: %w", err)
+	}
+	fmt.Printf("--> Installed %s for GOARCH=%s\n", strings.Join(packages, ", "), *arch)
+	return nil
+}
+
+// cmdArchive packages a built binary into a signed zip or tar bundle per OS.
+func cmdArchive(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ExitOnError)
+	archiveType := fs.String("type", "tar", "Archive type: zip or tar")
+	signer := fs.String("signer", "", "Environment variable holding the PGP signing key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *archiveType != "zip" && *archiveType != "tar" {
+		return fmt.Errorf("invalid -type %q, must be zip or tar", *archiveType)
+	}
+
+	src := projectSource(client)
+	builder := client.Container().From(goImage).
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src)
+
+	archiveName := fmt.Sprintf("%s.%s", mixinName, *archiveType)
+	if *archiveType == "zip" {
+		builder = builder.WithExec([]string{"apt-get", "update"}).
+			WithExec([]string{"apt-get", "install", "-y", "zip"}).
+			WithExec([]string{"zip", "-r", archiveName, "bin/"})
+	} else {
+		builder = builder.WithExec([]string{"tar", "-czf", archiveName, "bin/"})
+	}
+
+	if *signer != "" {
+		signingKey := os.Getenv(*signer)
+		if signingKey == "" {
+			return fmt.Errorf("signer environment variable %q is not set", *signer)
 		}
-		fmt.Println("Release task completed successfully!")
-	case "validate": // Add validate case
-		fmt.Println("Running Validate Generated Mixin task...")
-		if err := ValidateGeneratedMixin(ctx, client); err != nil {
-			fmt.Printf("Validation task failed: %v\n", err)
-			os.Exit(1)
+		keySecret := client.SetSecret("archive-signing-key", signingKey)
+		builder = builder.
+			WithSecretVariable("ARCHIVE_SIGNING_KEY", keySecret).
+			WithExec([]string{"sh", "-c", "echo \"$ARCHIVE_SIGNING_KEY\" | gpg --batch --import"}).
+			WithExec([]string{"gpg", "--batch", "--yes", "--detach-sign", "--armor", archiveName})
+	}
+
+	if _, err := builder.File(archiveName).Export(ctx, archiveName); err != nil {
+		return fmt.Errorf("failed to export archive %s: %w", archiveName, err)
+	}
+	fmt.Printf("--> Archived %s\n", archiveName)
+	return nil
+}
+
+// cmdDebsrc builds a Debian source package inside a debian:bookworm container
+// and optionally uploads it via dput.
+func cmdDebsrc(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("debsrc", flag.ExitOnError)
+	upload := fs.String("upload", "", "dput target to upload the source package to (optional)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src := projectSource(client)
+	builder := client.Container().From("debian:bookworm").
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src).
+		WithExec([]string{"apt-get", "update"}).
+		WithExec([]string{"apt-get", "install", "-y", "devscripts", "debhelper", "dput"}).
+		WithExec([]string{"debuild", "-S", "-us", "-uc"})
+
+	if _, err := builder.Sync(ctx); err != nil {
+		return fmt.Errorf("debuild failed: %w", err)
+	}
+
+	if *upload != "" {
+		if _, err := builder.WithExec([]string{"dput", *upload, "../" + mixinName + "*.changes"}).Sync(ctx); err != nil {
+			return fmt.Errorf("dput upload to %s failed: %w", *upload, err)
 		}
-		fmt.Println("Validation task completed successfully!")
+	}
+	fmt.Println("--> Debian source package built")
+	return nil
+}
+
+// cmdNsis assembles a Windows installer via makensis, run inside a Wine container
+// since NSIS itself only targets Windows.
+func cmdNsis(ctx context.Context, client *dagger.Client, args []string) error {
+	src := projectSource(client)
+	builder := client.Container().From("cdrx/nsis:latest").
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src).
+		WithExec([]string{"makensis", "build/nsis/installer.nsi"})
+
+	if _, err := builder.Sync(ctx); err != nil {
+		return fmt.Errorf("makensis failed: %w", err)
+	}
+	fmt.Println("--> Windows installer built")
+	return nil
+}
+
+// cmdPurge empties the Dagger cache volumes used by the other commands, useful
+// for forcing a clean rebuild. The Dagger SDK has no direct "delete volume"
+// call, so we mount each volume into a throwaway container and clear its contents.
+func cmdPurge(ctx context.Context, client *dagger.Client, args []string) error {
+	volumes := []string{"go-mod", "go-build", "go-mod-builder", "go-build-builder", "go-mod-validator", "go-build-validator",
+		"go-mod-xbuild", "go-build-xbuild", "go-mod-repro-a", "go-build-repro-a", "go-mod-repro-b", "go-build-repro-b"}
+	for _, p := range append(append([]platform{}, defaultMatrix...), fullMatrixExtra...) {
+		volumes = append(volumes, "go-mod-matrix-"+p.label(), "go-build-matrix-"+p.label())
+	}
+	purger := client.Container().From(goImage)
+	for _, name := range volumes {
+		mountPoint := "/cache/" + name
+		purger = purger.
+			WithMountedCache(mountPoint, client.CacheVolume(name)).
+			WithExec([]string{"sh", "-c", "rm -rf " + mountPoint + "/*"})
+	}
+	if _, err := purger.Sync(ctx); err != nil {
+		return fmt.Errorf("failed to purge cache volumes: %w", err)
+	}
+	fmt.Println("--> Cache volumes purged")
+	return nil
+}
+
+// cmdRelease adapts release to the command dispatcher signature.
+func cmdRelease(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	provenanceMode := fs.String("provenance-mode", "keyless", "SLSA provenance signing mode: none, unsigned, keyless, keyed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch *provenanceMode {
+	case "none", "unsigned", "keyless", "keyed":
+		// valid
 	default:
-		fmt.Printf("Error: Unknown task '%s'. Valid tasks are 'ci', 'release', or 'validate'.\n", *task) // Updated error message
-		os.Exit(1)
+		return fmt.Errorf("invalid -provenance-mode %q, must be one of: none, unsigned, keyless, keyed", *provenanceMode)
+	}
+
+	fmt.Println("Running Release task...")
+	githubToken := os.Getenv("GITHUB_TOKEN")
+	if githubToken == "" {
+		return fmt.Errorf("GITHUB_TOKEN environment variable is required for release")
+	}
+	if err := release(ctx, client, githubToken, *provenanceMode); err != nil {
+		return err
+	}
+	fmt.Println("Release task completed successfully!")
+	return nil
+}
+
+// cmdValidate adapts ValidateGeneratedMixin to the command dispatcher signature.
+func cmdValidate(ctx context.Context, client *dagger.Client, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	targets := fs.String("targets", "", `Override targets.yaml, e.g. "linux/amd64:debian@12,linux/arm64:alpine@3.19"`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Println("Running Validate Generated Mixin task...")
+	if err := ValidateGeneratedMixin(ctx, client, *targets); err != nil {
+		return err
+	}
+	fmt.Println("Validation task completed successfully!")
+	return nil
+}
+
+// cmdVerifyReproducible adapts verifyReproducible to the command dispatcher signature.
+func cmdVerifyReproducible(ctx context.Context, client *dagger.Client, args []string) error {
+	fmt.Println("--> Verifying reproducible build...")
+	if err := verifyReproducible(ctx, client); err != nil {
+		return err
+	}
+	fmt.Println("--> Build is reproducible: two independent builds produced identical bytes.")
+	return nil
+}
+
+// rebuildManifest records everything a third party needs to reproduce this
+// build byte-for-byte: the exact base image, the tool versions baked into
+// the container, and the fixed timestamp every build stamps into its output.
+type rebuildManifest struct {
+	GoVersion       string            `json:"goVersion"`
+	BaseImageDigest string            `json:"baseImageDigest"`
+	Commit          string            `json:"commit"`
+	SourceDateEpoch string            `json:"sourceDateEpoch"`
+	Tools           map[string]string `json:"tools"`
+}
+
+// verifyReproducible builds the mixin binary twice in independent containers
+// backed by different cache volumes, diffoscopes the two outputs, and fails
+// if they differ by a single byte. It also writes rebuild.json alongside the
+// verification so third parties have everything needed to reproduce the
+// build themselves.
+func verifyReproducible(ctx context.Context, client *dagger.Client) error {
+	src := projectSource(client)
+
+	buildOnce := func(cacheSuffix string) (*dagger.File, buildMetadata, error) {
+		golang := client.Container().From(goImage).
+			WithMountedCache("/go/pkg/mod", client.CacheVolume("go-mod-repro-"+cacheSuffix)).
+			WithMountedCache("/go/build-cache", client.CacheVolume("go-build-repro-"+cacheSuffix)).
+			WithEnvVariable("GOMODCACHE", "/go/pkg/mod").
+			WithEnvVariable("GOCACHE", "/go/build-cache").
+			WithWorkdir("/src").
+			WithMountedDirectory("/src", src).
+			WithEnvVariable("GOOS", "linux").
+			WithEnvVariable("GOARCH", "amd64").
+			WithEnvVariable("CGO_ENABLED", "0")
+
+		golang, meta, err := withReproducibleBuildEnv(ctx, golang)
+		if err != nil {
+			return nil, buildMetadata{}, err
+		}
+		outputPath := "/src/build-output/" + mixinName
+		golang = golang.WithExec(reproducibleGoBuildArgs(outputPath, "./cmd/"+mixinName, "repro", meta))
+		return golang.File(outputPath), meta, nil
+	}
+
+	binaryA, meta, err := buildOnce("a")
+	if err != nil {
+		return fmt.Errorf("first reproducibility build failed: %w", err)
+	}
+	binaryB, _, err := buildOnce("b")
+	if err != nil {
+		return fmt.Errorf("second reproducibility build failed: %w", err)
+	}
+
+	diffoscopeVersion := "0.27.0"
+	diff := client.Container().From(goImage).
+		WithExec([]string{"apt-get", "update"}).
+		WithExec([]string{"apt-get", "install", "-y", "diffoscope=" + diffoscopeVersion + "*"}).
+		WithFile("/tmp/build-a", binaryA).
+		WithFile("/tmp/build-b", binaryB)
+
+	if _, err := diff.WithExec([]string{"diffoscope", "/tmp/build-a", "/tmp/build-b"}).Sync(ctx); err != nil {
+		return fmt.Errorf("builds are not reproducible: %w", err)
+	}
+
+	baseImageDigest, err := client.Container().From(goImage).ImageRef(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve base image digest: %w", err)
+	}
+
+	manifest := rebuildManifest{
+		GoVersion:       goVersion,
+		BaseImageDigest: baseImageDigest,
+		Commit:          meta.commit,
+		SourceDateEpoch: meta.epoch,
+		Tools: map[string]string{
+			"diffoscope": diffoscopeVersion,
+		},
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rebuild manifest: %w", err)
+	}
+	if err := os.WriteFile("rebuild.json", data, 0600); err != nil {
+		return fmt.Errorf("failed to write rebuild.json: %w", err)
 	}
+
+	return nil
 }
 
-// runCI executes the standard CI steps (test, build)
-func runCI(ctx context.Context, client *dagger.Client) error {
+// runCI executes the standard CI steps (test, build). When full is true the
+// build step exercises the complete cross-compilation matrix (fullMatrixExtra
+// included) instead of just the platforms built on every PR.
+func runCI(ctx context.Context, client *dagger.Client, full bool) error {
 	fmt.Println("--> Running tests...")
 	if err := test(ctx, client); err != nil {
 		return fmt.Errorf("testing failed: %w", err)
 	}
 	fmt.Println("--> Tests passed!")
 
-	fmt.Println("--> Building artifacts (example: linux/amd64)...")
-	// Example: Build for linux/amd64 for verification during CI
+	fmt.Println("--> Building cross-compilation matrix...")
 	artifactsDir := "build-output"
-	if err := build(ctx, client, "linux", "amd64", artifactsDir); err != nil {
+	if err := buildMatrix(ctx, client, "dev", artifactsDir, full); err != nil {
 		return fmt.Errorf("build failed: %w", err)
 	}
 	fmt.Printf("--> Build artifacts generated in ./%s\n", artifactsDir)
 	return nil
 }
 
+// targetDistribution is one Linux distribution a target is validated
+// against, mirroring Cloud Native Buildpacks' Distribution entry.
+type targetDistribution struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+// target describes one GOOS/GOARCH/GOARM platform ValidateGeneratedMixin
+// validates the generated mixins against, paired with the Linux
+// distributions (and thus base images) to run that platform's checks on.
+// The schema mirrors buildpacks' Distribution/Target model.
+type target struct {
+	OS            string               `yaml:"os"`
+	Arch          string               `yaml:"arch"`
+	Variant       string               `yaml:"variant,omitempty"`
+	Distributions []targetDistribution `yaml:"distributions"`
+}
+
+// goarm extracts the GOARM value from a "vN" variant (e.g. "v7" -> "7"); it
+// returns "" for targets where GOARM doesn't apply.
+func (t target) goarm() string {
+	if t.Arch == "arm" && strings.HasPrefix(t.Variant, "v") {
+		return strings.TrimPrefix(t.Variant, "v")
+	}
+	return ""
+}
+
+// label renders the target/distribution pair in the same shorthand accepted
+// by -targets, e.g. "linux/arm/v7:ubuntu@22.04".
+func (t target) label(dist targetDistribution) string {
+	platform := t.OS + "/" + t.Arch
+	if t.Variant != "" {
+		platform += "/" + t.Variant
+	}
+	return fmt.Sprintf("%s:%s@%s", platform, dist.Name, dist.Version)
+}
+
+// targetsFile is the top-level shape of targets.yaml.
+type targetsFile struct {
+	Targets []target `yaml:"targets"`
+}
+
+// defaultTargets is used when neither targets.yaml nor -targets is supplied.
+var defaultTargets = []target{
+	{OS: "linux", Arch: "amd64", Distributions: []targetDistribution{{Name: "debian", Version: "12"}}},
+}
+
+// loadTargets reads targets.yaml from repo root, falling back to
+// defaultTargets if it doesn't exist.
+func loadTargets(path string) ([]target, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultTargets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var tf targetsFile
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(tf.Targets) == 0 {
+		return defaultTargets, nil
+	}
+	return tf.Targets, nil
+}
+
+// parseTargetsFlag parses the -targets override shorthand:
+// "os/arch[/variant]:distro@version[,os/arch[/variant]:distro@version...]",
+// e.g. "linux/amd64:debian@12,linux/arm64:alpine@3.19".
+func parseTargetsFlag(s string) ([]target, error) {
+	var targets []target
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		platformPart, distroPart, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid target %q: expected os/arch[/variant]:distro@version", entry)
+		}
+		fields := strings.Split(platformPart, "/")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid target %q: expected os/arch[/variant]", entry)
+		}
+		t := target{OS: fields[0], Arch: fields[1]}
+		if len(fields) == 3 {
+			t.Variant = fields[2]
+		}
+		distroName, distroVersion, ok := strings.Cut(distroPart, "@")
+		if !ok {
+			return nil, fmt.Errorf("invalid target %q: expected distro@version", entry)
+		}
+		t.Distributions = []targetDistribution{{Name: distroName, Version: distroVersion}}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// resolveTargets honors the -targets override when set, otherwise loads
+// targets.yaml (or defaultTargets if that file doesn't exist).
+func resolveTargets(targetsOverride string) ([]target, error) {
+	if targetsOverride != "" {
+		return parseTargetsFlag(targetsOverride)
+	}
+	return loadTargets("targets.yaml")
+}
+
+// baseImageFor picks the golang base image closest to the requested
+// distribution. The official golang images only ship debian (bookworm/
+// bullseye) and alpine variants, so debian-family distros (debian, ubuntu)
+// map to the debian image and anything else falls back to the default image.
+func baseImageFor(dist targetDistribution) string {
+	switch dist.Name {
+	case "alpine":
+		return fmt.Sprintf("golang:%s-alpine%s", goVersion, dist.Version)
+	case "debian", "ubuntu":
+		return fmt.Sprintf("golang:%s-bookworm", goVersion)
+	default:
+		return goImage
+	}
+}
+
+// installValidationTools installs the packages and Go-based linters
+// validateMixin needs, using the distribution's native package manager.
+func installValidationTools(ctr *dagger.Container, dist targetDistribution) *dagger.Container {
+	golangciVersion := "v1.61.0"
+	gosecVersion := "v2.21.4"
+	govulncheckVersion := "v1.1.3"
+
+	if dist.Name == "alpine" {
+		ctr = ctr.WithExec([]string{"apk", "add", "--no-cache", "git", "build-base"})
+	} else {
+		ctr = ctr.
+			WithExec([]string{"apt-get", "update"}).
+			WithExec([]string{"apt-get", "install", "-y", "git", "build-essential"})
+	}
+
+	return ctr.
+		WithExec([]string{"go", "install", "github.com/golangci/golangci-lint/cmd/golangci-lint@" + golangciVersion}).
+		WithExec([]string{"go", "install", "github.com/securego/gosec/v2/cmd/gosec@" + gosecVersion}).
+		WithExec([]string{"go", "install", "golang.org/x/vuln/cmd/govulncheck@" + govulncheckVersion})
+}
+
+// validationResult records one target/mixin validation outcome for
+// validation-report.json, so PRs can post a matrix status comment.
+type validationResult struct {
+	Target string `json:"target"`
+	Mixin  string `json:"mixin"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// writeValidationReport marshals results to path as indented JSON.
+func writeValidationReport(path string, results []validationResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
 // ValidateGeneratedMixin builds the generator and uses it to create a sample mixin,
 // then runs validation checks (build, test, lint, scan) on the generated code.
-func ValidateGeneratedMixin(ctx context.Context, client *dagger.Client) error {
+func ValidateGeneratedMixin(ctx context.Context, client *dagger.Client, targetsOverride string) error {
 	fmt.Println("--> Building generator binary...")
 	src := projectSource(client)
 
@@ -104,13 +732,12 @@ func ValidateGeneratedMixin(ctx context.Context, client *dagger.Client) error {
 		WithEnvVariable("CGO_ENABLED", "0")
 
 	// Build the generator
+	builder, meta, err := withReproducibleBuildEnv(ctx, builder)
+	if err != nil {
+		return err
+	}
 	generatorPath := "/src/bin/skeletor" // Path inside container
-	builder = builder.WithExec([]string{
-		"go", "build",
-		"-ldflags", "-s -w",
-		"-o", generatorPath,
-		"./cmd/skeletor",
-	})
+	builder = builder.WithExec(reproducibleGoBuildArgs(generatorPath, "./cmd/skeletor", "validate", meta))
 
 	// Container for running validation on the generated mixin
 	// Needs Go, git, golangci-lint, gosec, govulncheck
@@ -193,15 +820,55 @@ func ValidateGeneratedMixin(ctx context.Context, client *dagger.Client) error {
 		{"security-mixin", securityMixinPath},
 	}
 
-	for _, mixin := range mixinPaths {
-		fmt.Printf("--> Validating %s...\n", mixin.name)
-		if err := validateMixin(ctx, validator, mixin.path, mixin.name); err != nil {
-			return fmt.Errorf("validation failed for %s: %w", mixin.name, err)
+	targets, err := resolveTargets(targetsOverride)
+	if err != nil {
+		return fmt.Errorf("failed to resolve validation targets: %w", err)
+	}
+
+	var results []validationResult
+	for _, t := range targets {
+		for _, dist := range t.Distributions {
+			label := t.label(dist)
+			for _, mixin := range mixinPaths {
+				fmt.Printf("--> Validating %s on %s...\n", mixin.name, label)
+
+				targetContainer := client.Container().From(baseImageFor(dist)).
+					WithMountedCache("/go/pkg/mod", client.CacheVolume("go-mod-validator")).
+					WithMountedCache("/go/build-cache", client.CacheVolume("go-build-validator")).
+					WithEnvVariable("GOMODCACHE", "/go/pkg/mod").
+					WithEnvVariable("GOCACHE", "/go/build-cache").
+					WithEnvVariable("GOOS", t.OS).
+					WithEnvVariable("GOARCH", t.Arch)
+				if goarm := t.goarm(); goarm != "" {
+					targetContainer = targetContainer.WithEnvVariable("GOARM", goarm)
+				}
+				targetContainer = installValidationTools(targetContainer, dist)
+				targetContainer = targetContainer.WithDirectory(mixin.path, validator.Directory(mixin.path))
+
+				result := validationResult{Target: label, Mixin: mixin.name}
+				if err := validateMixin(ctx, targetContainer, mixin.path, mixin.name); err != nil {
+					result.Error = err.Error()
+					fmt.Printf("--> %s on %s FAILED: %v\n", mixin.name, label, err)
+				} else {
+					result.Passed = true
+					fmt.Printf("--> %s on %s validation successful!\n", mixin.name, label)
+				}
+				results = append(results, result)
+			}
 		}
-		fmt.Printf("--> %s validation successful!\n", mixin.name)
 	}
 
-	fmt.Println("--> All generated mixin validations successful!")
+	if err := writeValidationReport("validation-report.json", results); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if !r.Passed {
+			return fmt.Errorf("validation failed for %s on %s: %s", r.Mixin, r.Target, r.Error)
+		}
+	}
+
+	fmt.Println("--> All generated mixin validations successful across the target matrix!")
 	return nil
 }
 
@@ -238,20 +905,13 @@ func validateMixin(ctx context.Context, validator *dagger.Container, mixinPath,
 		return fmt.Errorf("golangci-lint failed: %w", err)
 	}
 
-	// Gosec (warning only for now)
-	fmt.Printf("  --> Running gosec ./... for %s...\n", mixinName)
-	_, err = mixinValidator.WithExec([]string{"gosec", "./..."}).Sync(ctx)
-	if err != nil {
-		fmt.Printf("Warning: gosec found issues in %s: %v\n", mixinName, err)
-		// Don't fail on gosec issues for now
-	}
-
-	// Govulncheck (warning only for now)
+	// Gosec findings are now covered by golangci-lint's gosec linter (see
+	// .golangci.yml) above, so this pass is just govulncheck for known CVEs
+	// static analysis doesn't catch.
 	fmt.Printf("  --> Running govulncheck ./... for %s...\n", mixinName)
 	_, err = mixinValidator.WithExec([]string{"govulncheck", "./..."}).Sync(ctx)
 	if err != nil {
-		fmt.Printf("Warning: govulncheck found issues in %s: %v\n", mixinName, err)
-		// Don't fail on vulnerability issues for now
+		return fmt.Errorf("govulncheck failed: %w", err)
 	}
 
 	// Verify enterprise feature files exist (for enterprise and security mixins)
@@ -345,26 +1005,155 @@ func build(ctx context.Context, client *dagger.Client, goos, goarch, outputDir s
 		WithEnvVariable("GOARCH", goarch).
 		WithEnvVariable("CGO_ENABLED", "0") // Ensure static builds
 
+	golang, meta, err := withReproducibleBuildEnv(ctx, golang)
+	if err != nil {
+		return err
+	}
+
 	// Build the binary using go build (or mage build if preferred)
 	// Using go build directly for simplicity here
 	outputPath := filepath.Join("/src", outputDir, fmt.Sprintf("%s-%s-%s", mixinName, goos, goarch))
-	golang = golang.WithExec([]string{
-		"go", "build",
-		"-ldflags", "-s -w", // Strip symbols and debug info
-		"-o", outputPath,
-		"./cmd/" + mixinName, // Path to main package
-	})
+	golang = golang.WithExec(reproducibleGoBuildArgs(outputPath, "./cmd/"+mixinName, "dev", meta))
 
 	// Extract the built binary
 	output := client.Directory().WithDirectory(outputDir, golang.Directory(filepath.Join("/src", outputDir)))
-	_, err := output.Export(ctx, ".") // Export to host filesystem under ./build-output
-	if err != nil {
+	if _, err := output.Export(ctx, "."); err != nil { // Export to host filesystem under ./build-output
 		return fmt.Errorf("failed to export build artifact: %w", err)
 	}
 
 	return nil
 }
 
+// platform pairs the GOOS/GOARCH the rest of the matrix code works with; GOARM
+// carries the ARM variant (e.g. "7") when relevant, and is empty otherwise.
+type platform struct {
+	goos, goarch, goarm string
+}
+
+func (p platform) archiveSuffix() string {
+	if p.goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+func (p platform) label() string {
+	if p.goarm != "" {
+		return fmt.Sprintf("%s_%s_%sv%s", p.goos, p.goarch, p.goarch, p.goarm)
+	}
+	return fmt.Sprintf("%s_%s", p.goos, p.goarch)
+}
+
+// defaultMatrix covers the platforms built on every PR/CI run.
+var defaultMatrix = []platform{
+	{goos: "linux", goarch: "amd64"},
+	{goos: "linux", goarch: "arm64"},
+	{goos: "darwin", goarch: "amd64"},
+	{goos: "darwin", goarch: "arm64"},
+	{goos: "windows", goarch: "amd64"},
+	{goos: "windows", goarch: "arm64"},
+}
+
+// fullMatrixExtra adds the less common targets shipped alongside tagged releases.
+var fullMatrixExtra = []platform{
+	{goos: "linux", goarch: "arm", goarm: "7"},
+	{goos: "linux", goarch: "ppc64le"},
+	{goos: "linux", goarch: "s390x"},
+}
+
+// buildMatrix cross-compiles the mixin binary for every platform in the
+// matrix concurrently (bounded by runtime.NumCPU()), packages each into
+// mixinName_version_os_arch.tar.gz (or .zip for windows), and writes a single
+// checksums.txt alongside the archives in outputDir. A failure in any
+// platform build cancels the rest via errgroup.
+func buildMatrix(ctx context.Context, client *dagger.Client, version, outputDir string, full bool) error {
+	matrix := append([]platform{}, defaultMatrix...)
+	if full {
+		matrix = append(matrix, fullMatrixExtra...)
+	}
+
+	src := projectSource(client)
+
+	// Resolve the commit metadata once; every platform in the matrix stamps
+	// the same version/commit/date so the archives are reproducible and
+	// comparable regardless of which platform finished first.
+	metaCtr := client.Container().From(goImage).
+		WithWorkdir("/src").
+		WithMountedDirectory("/src", src).
+		WithExec([]string{"apt-get", "update"}).
+		WithExec([]string{"apt-get", "install", "-y", "git"})
+	meta, err := resolveBuildMetadata(ctx, metaCtr)
+	if err != nil {
+		return err
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(runtime.NumCPU())
+
+	checksums := make([]string, len(matrix))
+	for i, p := range matrix {
+		i, p := i, p
+		group.Go(func() error {
+			archiveName := fmt.Sprintf("%s_%s_%s.%s", mixinName, version, p.label(), p.archiveSuffix())
+			binaryName := mixinName
+			if p.goos == "windows" {
+				binaryName += ".exe"
+			}
+			binaryPath := filepath.Join("/src/dist", p.label(), binaryName)
+
+			golang := client.Container().From(goImage).
+				WithMountedCache("/go/pkg/mod", client.CacheVolume("go-mod-matrix-"+p.label())).
+				WithMountedCache("/go/build-cache", client.CacheVolume("go-build-matrix-"+p.label())).
+				WithEnvVariable("GOMODCACHE", "/go/pkg/mod").
+				WithEnvVariable("GOCACHE", "/go/build-cache").
+				WithWorkdir("/src").
+				WithMountedDirectory("/src", src).
+				WithEnvVariable("GOOS", p.goos).
+				WithEnvVariable("GOARCH", p.goarch).
+				WithEnvVariable("CGO_ENABLED", "0").
+				WithEnvVariable("SOURCE_DATE_EPOCH", meta.epoch)
+			if p.goarm != "" {
+				golang = golang.WithEnvVariable("GOARM", p.goarm)
+			}
+
+			golang = golang.WithExec(reproducibleGoBuildArgs(binaryPath, "./cmd/"+mixinName, version, meta))
+
+			archiveDir := filepath.Dir(binaryPath)
+			if p.archiveSuffix() == "zip" {
+				golang = golang.WithExec([]string{"apt-get", "update"}).
+					WithExec([]string{"apt-get", "install", "-y", "zip"}).
+					WithExec([]string{"sh", "-c", fmt.Sprintf("cd %s && zip -j %s %s", archiveDir, archiveName, binaryName)})
+			} else {
+				golang = golang.WithExec([]string{"sh", "-c", fmt.Sprintf("cd %s && tar -czf %s %s", archiveDir, archiveName, binaryName)})
+			}
+
+			archiveContainerPath := filepath.Join(archiveDir, archiveName)
+			sha, err := golang.WithExec([]string{"sh", "-c", "sha256sum " + archiveContainerPath + " | awk '{print $1}'"}).Stdout(groupCtx)
+			if err != nil {
+				return fmt.Errorf("failed to checksum %s: %w", archiveName, err)
+			}
+			checksums[i] = strings.TrimSpace(sha) + "  " + archiveName
+
+			if _, err := golang.File(archiveContainerPath).Export(groupCtx, filepath.Join(outputDir, archiveName)); err != nil {
+				return fmt.Errorf("failed to export archive for %s: %w", p.label(), err)
+			}
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	checksumsPath := filepath.Join(outputDir, "checksums.txt")
+	if err := os.WriteFile(checksumsPath, []byte(strings.Join(checksums, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", checksumsPath, err)
+	}
+
+	fmt.Printf("--> Built and packaged %d platform archives in %s\n", len(matrix), outputDir)
+	return nil
+}
+
 // projectSource returns the host project directory mounted in the container
 func projectSource(client *dagger.Client) *dagger.Directory {
 	// Get reference to host directory
@@ -374,8 +1163,154 @@ func projectSource(client *dagger.Client) *dagger.Directory {
 	return src
 }
 
+// inTotoStatement is the subset of the in-toto v1 Statement layer we populate
+// for SLSA provenance (https://slsa.dev/provenance/v1).
+type inTotoStatement struct {
+	Type          string           `json:"_type"`
+	Subject       []inTotoSubject  `json:"subject"`
+	PredicateType string           `json:"predicateType"`
+	Predicate     slsaProvenanceV1 `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type slsaProvenanceV1 struct {
+	BuildDefinition slsaBuildDefinition `json:"buildDefinition"`
+}
+
+type slsaBuildDefinition struct {
+	BuildType            string            `json:"buildType"`
+	ExternalParameters   map[string]string `json:"externalParameters"`
+	ResolvedDependencies []slsaResolvedDep `json:"resolvedDependencies"`
+}
+
+type slsaResolvedDep struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// parseChecksumsFile parses a goreleaser-produced checksums.txt ("<sha256>  <name>" per line)
+// into a name -> sha256 map.
+func parseChecksumsFile(content string) map[string]string {
+	digests := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		digests[fields[1]] = fields[0]
+	}
+	return digests
+}
+
+// buildProvenanceStatement assembles an in-toto Statement carrying SLSA v1.0 provenance
+// for the artifacts listed in checksums.txt.
+func buildProvenanceStatement(digests map[string]string) inTotoStatement {
+	subjects := make([]inTotoSubject, 0, len(digests))
+	for name, sha := range digests {
+		subjects = append(subjects, inTotoSubject{
+			Name:   name,
+			Digest: map[string]string{"sha256": sha},
+		})
+	}
+
+	externalParams := map[string]string{
+		"gitRef":       os.Getenv("GITHUB_REF"),
+		"commitSHA":    os.Getenv("GITHUB_SHA"),
+		"workflowName": os.Getenv("GITHUB_WORKFLOW"),
+	}
+
+	return inTotoStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       subjects,
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate: slsaProvenanceV1{
+			BuildDefinition: slsaBuildDefinition{
+				BuildType:          "https://github.com/mchorfa/porter-skeletor/dagger@v1",
+				ExternalParameters: externalParams,
+				ResolvedDependencies: []slsaResolvedDep{
+					{URI: "git+https://github.com/mchorfa/porter-skeletor@" + os.Getenv("GITHUB_SHA")},
+				},
+			},
+		},
+	}
+}
+
+// generateProvenance walks the artifacts recorded in dist/checksums.txt, builds one SLSA v1.0
+// provenance statement whose subjects list every artifact, signs each artifact according to
+// provenanceMode, and exports the resulting .intoto.jsonl bundles next to the other release assets.
+func generateProvenance(ctx context.Context, releaserExec *dagger.Container, provenanceMode string, githubTokenSecret *dagger.Secret) error {
+	checksumsContent, err := releaserExec.File("dist/checksums.txt").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read dist/checksums.txt: %w", err)
+	}
+
+	digests := parseChecksumsFile(checksumsContent)
+	if len(digests) == 0 {
+		return fmt.Errorf("no artifacts found in dist/checksums.txt")
+	}
+
+	statement := buildProvenanceStatement(digests)
+	statementJSON, err := json.Marshal(statement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal provenance statement: %w", err)
+	}
+
+	predicatePath := "/tmp/provenance-predicate.json"
+	signer := releaserExec.WithNewFile(predicatePath, string(statementJSON))
+
+	if provenanceMode == "unsigned" {
+		_, err := signer.File(predicatePath).Export(ctx, "dist/provenance.intoto.jsonl")
+		if err != nil {
+			return fmt.Errorf("failed to export unsigned provenance: %w", err)
+		}
+		fmt.Println("  --> Wrote unsigned provenance to dist/provenance.intoto.jsonl")
+		return nil
+	}
+
+	signer = signer.WithSecretVariable("GITHUB_TOKEN", githubTokenSecret)
+	if provenanceMode == "keyless" {
+		signer = signer.WithEnvVariable("COSIGN_EXPERIMENTAL", "1")
+	}
+
+	for name := range digests {
+		bundlePath := fmt.Sprintf("/tmp/%s.intoto.jsonl", name)
+		ext := filepath.Ext(name)
+		switch ext {
+		case ".tar", ".gz", ".zip", ".tgz":
+			signer = signer.WithExec([]string{
+				"cosign", "sign-blob", "--bundle", bundlePath,
+				"--yes",
+				"dist/" + name,
+			})
+		default:
+			// Container image digests are attested separately once pushed; release
+			// archives (matched above) are signed with sign-blob, and every other blob
+			// gets the predicate attested directly against the artifact.
+			signer = signer.WithExec([]string{
+				"cosign", "attest-blob", "--type", "slsaprovenance1",
+				"--predicate", predicatePath,
+				"--bundle", bundlePath,
+				"--yes",
+				"dist/" + name,
+			})
+		}
+
+		if _, err := signer.File(bundlePath).Export(ctx, "dist/"+name+".intoto.jsonl"); err != nil {
+			return fmt.Errorf("failed to export provenance bundle for %s: %w", name, err)
+		}
+	}
+
+	fmt.Printf("  --> Signed and exported provenance bundles for %d artifacts (mode=%s)\n", len(digests), provenanceMode)
+	return nil
+}
+
 // release runs goreleaser within a Dagger container
-func release(ctx context.Context, client *dagger.Client, githubToken string) error {
+func release(ctx context.Context, client *dagger.Client, githubToken string, provenanceMode string) error {
 	fmt.Println("--> Preparing GoReleaser container...")
 	src := projectSource(client)
 
@@ -421,6 +1356,30 @@ func release(ctx context.Context, client *dagger.Client, githubToken string) err
 	}
 	fmt.Println("--> GoReleaser finished successfully (binaries, checksums, SBOMs, release assets).")
 
+	// --- Cross-compilation matrix ---
+	// goreleaser already publishes the tagged release assets; buildMatrix
+	// additionally exports the full platform matrix (including fullMatrixExtra)
+	// to ci/build-output/ so it's available to the rest of this pipeline (e.g.
+	// container image builds below) without re-deriving it from the GitHub
+	// release.
+	releaseVersion := os.Getenv("GITHUB_REF_NAME")
+	if releaseVersion == "" {
+		releaseVersion = "dev"
+	}
+	if err := buildMatrix(ctx, client, releaseVersion, "build-output", true); err != nil {
+		return fmt.Errorf("matrix build failed: %w", err)
+	}
+
+	// --- SLSA Provenance Generation ---
+	if provenanceMode == "none" {
+		fmt.Println("--> Skipping SLSA provenance generation (-provenance-mode=none).")
+	} else {
+		fmt.Println("--> Generating SLSA v1.0 provenance for release artifacts...")
+		if err := generateProvenance(ctx, releaserExec, provenanceMode, githubTokenSecret); err != nil {
+			return fmt.Errorf("SLSA provenance generation failed: %w", err)
+		}
+	}
+
 	// --- Explicit Docker Build, Push, Attest ---
 	fmt.Println("--> Building and pushing Docker images...")
 
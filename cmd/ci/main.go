@@ -0,0 +1,25 @@
+// Command ci is a thin backward-compatible wrapper around the Dagger module
+// declared in ../../ci. Before that package became a typed Dagger module
+// (see dagger.json), it was invoked directly as `go run ./ci <command>`;
+// this wrapper keeps that invocation working by forwarding to the module's
+// classic CLI entrypoint unchanged.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+func main() {
+	args := append([]string{"run", "./ci"}, os.Args[1:]...)
+	// #nosec G204 -- args are forwarded verbatim from this process's own argv
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "cmd/ci: %v\n", err)
+		os.Exit(1)
+	}
+}
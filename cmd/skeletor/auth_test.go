@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAuthConfig_RejectsABACWithFileAdapter(t *testing.T) {
+	err := ValidateAuthConfig(&AuthConfig{Model: AuthModelABAC, Adapter: AuthAdapterFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ABAC")
+}
+
+func TestValidateAuthConfig_AcceptsABACWithMemoryAdapter(t *testing.T) {
+	require.NoError(t, ValidateAuthConfig(&AuthConfig{Model: AuthModelABAC, Adapter: AuthAdapterMemory}))
+}
+
+func TestValidateAuthConfig_RejectsUnknownModelAndAdapter(t *testing.T) {
+	err := ValidateAuthConfig(&AuthConfig{Model: "made-up", Adapter: AuthAdapterFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown model")
+
+	err = ValidateAuthConfig(&AuthConfig{Model: AuthModelRBAC, Adapter: "made-up"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown adapter")
+}
+
+func TestValidateAuthConfig_RejectsDomainSeedWithoutDomainModel(t *testing.T) {
+	err := ValidateAuthConfig(&AuthConfig{
+		Model:      AuthModelRBAC,
+		Adapter:    AuthAdapterFile,
+		PolicySeed: []PolicySeedEntry{{Sub: "alice", Obj: "mixin", Act: "install", Domain: "prod"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "domain")
+}
+
+func TestValidateAuthConfig_NilConfig(t *testing.T) {
+	require.Error(t, ValidateAuthConfig(nil))
+}
+
+func TestParsePolicySeed_RBAC(t *testing.T) {
+	seed, err := parsePolicySeed(AuthModelRBAC, []string{"alice, mixin, install", "bob,mixin,build"})
+	require.NoError(t, err)
+	require.Len(t, seed, 2)
+	assert.Equal(t, PolicySeedEntry{Sub: "alice", Obj: "mixin", Act: "install"}, seed[0])
+	assert.Equal(t, PolicySeedEntry{Sub: "bob", Obj: "mixin", Act: "build"}, seed[1])
+}
+
+func TestParsePolicySeed_RBACWithDomains(t *testing.T) {
+	seed, err := parsePolicySeed(AuthModelRBACWithDomains, []string{"alice,prod,mixin,install"})
+	require.NoError(t, err)
+	require.Len(t, seed, 1)
+	assert.Equal(t, PolicySeedEntry{Sub: "alice", Domain: "prod", Obj: "mixin", Act: "install"}, seed[0])
+}
+
+func TestParsePolicySeed_WrongFieldCount(t *testing.T) {
+	_, err := parsePolicySeed(AuthModelRBAC, []string{"alice,mixin"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --auth-policy-seed entry")
+}
+
+func TestGenerateAuthScaffold_RBACFileAdapter(t *testing.T) {
+	cfg := &AuthConfig{
+		Model:      AuthModelRBAC,
+		Adapter:    AuthAdapterFile,
+		PolicySeed: []PolicySeedEntry{{Sub: "alice", Obj: "mixin", Act: "install"}},
+	}
+	outputDir := t.TempDir()
+
+	require.NoError(t, GenerateAuthScaffold(cfg, outputDir))
+
+	model, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "model.conf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(model), "r = sub, obj, act")
+	assert.Contains(t, string(model), "g(r.sub, p.sub)")
+
+	policy, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "policy.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "p, alice, mixin, install\n", string(policy))
+
+	enforcer, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "enforcer.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(enforcer), "package auth")
+	assert.Contains(t, string(enforcer), "casbin.NewEnforcer(modelPath, policyPath)")
+	assert.Contains(t, string(enforcer), "func Middleware(")
+	assert.False(t, strings.Contains(string(enforcer), "persist.Adapter"))
+}
+
+func TestGenerateAuthScaffold_RBACWithDomainsPolicyCSV(t *testing.T) {
+	cfg := &AuthConfig{
+		Model:      AuthModelRBACWithDomains,
+		Adapter:    AuthAdapterFile,
+		PolicySeed: []PolicySeedEntry{{Sub: "alice", Domain: "prod", Obj: "mixin", Act: "install"}},
+	}
+	outputDir := t.TempDir()
+
+	require.NoError(t, GenerateAuthScaffold(cfg, outputDir))
+
+	policy, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "policy.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "p, alice, prod, mixin, install\n", string(policy))
+
+	enforcer, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "enforcer.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(enforcer), "func Middleware(enforcer *casbin.Enforcer, lookupUser UserLookup) func(dom, obj, act string) error")
+}
+
+func TestGenerateAuthScaffold_GormAdapterUsesPersistAdapter(t *testing.T) {
+	cfg := &AuthConfig{Model: AuthModelRBAC, Adapter: AuthAdapterGorm}
+	outputDir := t.TempDir()
+
+	require.NoError(t, GenerateAuthScaffold(cfg, outputDir))
+
+	enforcer, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "enforcer.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(enforcer), "github.com/casbin/casbin/v2/persist")
+	assert.Contains(t, string(enforcer), "func NewEnforcer(modelPath string, adapter persist.Adapter)")
+}
+
+func TestGenerateAuthScaffold_MemoryAdapterSeedsWithoutPersistence(t *testing.T) {
+	cfg := &AuthConfig{Model: AuthModelRBAC, Adapter: AuthAdapterMemory}
+	outputDir := t.TempDir()
+
+	require.NoError(t, GenerateAuthScaffold(cfg, outputDir))
+
+	enforcer, err := os.ReadFile(filepath.Join(outputDir, "pkg", "auth", "enforcer.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(enforcer), "seedFromPolicyCSV")
+	assert.Contains(t, string(enforcer), `"encoding/csv"`)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildCacheCommand returns the `skeletor cache` command group for
+// inspecting and managing the ~/.skeletor/cache/templates cache that
+// --template-url resolves into (see templatecache.go). This is distinct
+// from `skeletor sources`, which manages the separate ~/.skeletor/sources
+// cache --template-source resolves into.
+func buildCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cached --template-url templates",
+	}
+	cmd.AddCommand(buildCacheListCommand())
+	cmd.AddCommand(buildCachePruneCommand())
+	return cmd
+}
+
+func buildCacheListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List cached --template-url entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifests, err := listTemplateURLCacheEntries()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "URL\tREF\tCOMMIT\tTREE DIGEST\tFETCHED AT")
+			for _, manifest := range manifests {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					manifest.URL, manifest.Ref, shortCommitSHA(manifest.CommitSHA), manifest.TreeDigest,
+					manifest.FetchedAt.Format("2006-01-02T15:04:05Z07:00"))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func buildCachePruneCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Remove all cached --template-url entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pruneTemplateURLCache(); err != nil {
+				return err
+			}
+			fmt.Println("Template cache pruned.")
+			return nil
+		},
+	}
+}
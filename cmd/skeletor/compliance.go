@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Control is one entry in a ControlCatalog -- a single requirement from a
+// compliance framework, e.g. SOC2 CC6.1 "Logical access security measures".
+type Control struct {
+	ID          string   `yaml:"id" json:"id"`
+	Title       string   `yaml:"title" json:"title"`
+	Family      string   `yaml:"family" json:"family"`
+	Description string   `yaml:"description" json:"description"`
+	Severity    string   `yaml:"severity" json:"severity"`
+	Refs        []string `yaml:"refs,omitempty" json:"refs,omitempty"`
+}
+
+// ControlCatalog is a framework's full set of controls, keyed by ID.
+type ControlCatalog map[string]Control
+
+// builtinControlCatalogs seeds a handful of representative controls per
+// framework -- enough to drive controls.yaml generation and narratives out
+// of the box. Real audits should extend these via a Custom catalog (see
+// LoadCustomControlCatalog) rather than relying on this list being
+// exhaustive.
+var builtinControlCatalogs = map[string]ControlCatalog{
+	"soc2": {
+		"CC6.1": {ID: "CC6.1", Title: "Logical access security", Family: "Common Criteria", Severity: "high",
+			Description: "The entity implements logical access security software, infrastructure, and architectures over protected information assets."},
+		"CC7.2": {ID: "CC7.2", Title: "Security incident monitoring", Family: "Common Criteria", Severity: "high",
+			Description: "The entity monitors system components for anomalies indicative of malicious acts, natural disasters, and errors."},
+	},
+	"hipaa": {
+		"164.312(a)(1)": {ID: "164.312(a)(1)", Title: "Access control", Family: "Technical Safeguards", Severity: "high",
+			Description: "Implement technical policies and procedures that allow access only to those persons granted access rights."},
+		"164.312(b)": {ID: "164.312(b)", Title: "Audit controls", Family: "Technical Safeguards", Severity: "medium",
+			Description: "Implement hardware, software, and procedural mechanisms that record and examine activity in systems containing ePHI."},
+	},
+	"pci_dss": {
+		"6.3": {ID: "6.3", Title: "Secure software development", Family: "Develop and Maintain Secure Systems", Severity: "high",
+			Description: "Develop internal and external software applications securely."},
+		"11.3": {ID: "11.3", Title: "Vulnerability scanning", Family: "Maintain a Vulnerability Management Program", Severity: "high",
+			Description: "External and internal vulnerability scans are run at least once every three months."},
+	},
+	"gdpr": {
+		"Art.32": {ID: "Art.32", Title: "Security of processing", Family: "Security", Severity: "high",
+			Description: "Implement appropriate technical and organizational measures to ensure a level of security appropriate to the risk."},
+		"Art.30": {ID: "Art.30", Title: "Records of processing activities", Family: "Accountability", Severity: "medium",
+			Description: "Maintain a record of processing activities under its responsibility."},
+	},
+}
+
+// LoadCustomControlCatalog reads a user-authored control catalog (a YAML
+// list of Control) from path, for frameworks builtinControlCatalogs doesn't
+// cover.
+func LoadCustomControlCatalog(path string) (ControlCatalog, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path comes from trusted template.json / CLI flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom control catalog %s: %w", path, err)
+	}
+
+	var controls []Control
+	if err := yaml.Unmarshal(raw, &controls); err != nil {
+		return nil, fmt.Errorf("failed to parse custom control catalog %s: %w", path, err)
+	}
+
+	catalog := make(ControlCatalog, len(controls))
+	for _, control := range controls {
+		catalog[control.ID] = control
+	}
+	return catalog, nil
+}
+
+// ControlMapping links one control to the generated files and hooks that
+// are meant to satisfy it, for compliance/controls.yaml.
+type ControlMapping struct {
+	Framework      string   `yaml:"framework" json:"framework"`
+	Control        Control  `yaml:"control" json:"control"`
+	GeneratedFiles []string `yaml:"generated_files,omitempty" json:"generated_files,omitempty"`
+	Hooks          []string `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+}
+
+// ComplianceManifest is the root of compliance/controls.yaml: every control
+// referenced by an enabled framework's PolicyConfig.Rules, mapped to the
+// evidence expected to satisfy it.
+type ComplianceManifest struct {
+	Mappings []ControlMapping `yaml:"mappings" json:"mappings"`
+}
+
+// enabledFrameworks returns the compliance frameworks config.FeatureToggles
+// turns on, in a stable order.
+func enabledFrameworks(config *TemplateConfig) []string {
+	if config == nil || config.FeatureToggles == nil || config.FeatureToggles.Compliance == nil {
+		return nil
+	}
+	c := config.FeatureToggles.Compliance
+	var frameworks []string
+	if c.SOC2 {
+		frameworks = append(frameworks, "soc2")
+	}
+	if c.HIPAA {
+		frameworks = append(frameworks, "hipaa")
+	}
+	if c.PCIDSS {
+		frameworks = append(frameworks, "pci_dss")
+	}
+	if c.GDPR {
+		frameworks = append(frameworks, "gdpr")
+	}
+	for name, enabled := range c.Custom {
+		if enabled {
+			frameworks = append(frameworks, name)
+		}
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+// BuildComplianceManifest walks config's enabled frameworks and resolves
+// each PolicyConfig's control ID references (in Rules) against
+// builtinControlCatalogs, plus any customCatalogs a caller loaded via
+// LoadCustomControlCatalog. A rule that names an unknown control ID is
+// skipped rather than erroring, since PolicyConfig.Rules predates this
+// catalog and may still carry free-form rule names.
+func BuildComplianceManifest(config *TemplateConfig, customCatalogs map[string]ControlCatalog) ComplianceManifest {
+	manifest := ComplianceManifest{}
+	if config == nil || config.FeatureToggles == nil || config.FeatureToggles.Compliance == nil {
+		return manifest
+	}
+
+	for _, framework := range enabledFrameworks(config) {
+		catalog := builtinControlCatalogs[framework]
+		custom := customCatalogs[framework]
+		policy, hasPolicy := config.FeatureToggles.Compliance.Policies[framework]
+		if !hasPolicy || (catalog == nil && custom == nil) {
+			continue
+		}
+		for _, ruleID := range policy.Rules {
+			control, ok := custom[ruleID]
+			if !ok {
+				control, ok = catalog[ruleID]
+			}
+			if !ok {
+				continue
+			}
+			manifest.Mappings = append(manifest.Mappings, ControlMapping{
+				Framework: framework,
+				Control:   control,
+			})
+		}
+	}
+	return manifest
+}
+
+// WriteComplianceManifest marshals manifest as YAML to
+// <outputDir>/compliance/controls.yaml.
+func WriteComplianceManifest(manifest ComplianceManifest, outputDir string) error {
+	if len(manifest.Mappings) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(outputDir, "compliance")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create compliance directory: %w", err)
+	}
+
+	encoded, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compliance manifest: %w", err)
+	}
+
+	path := filepath.Join(dir, "controls.yaml")
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RenderComplianceNarratives renders every templates/compliance/<framework>/*.md.tmpl
+// found in tmplFS for each enabled framework to
+// <outputDir>/compliance/<framework>/. A framework with no narrative
+// templates is silently skipped, since not every template author ships them.
+func RenderComplianceNarratives(tmplFS fs.FS, templateRoot string, config *TemplateConfig, outputDir string, data map[string]interface{}) error {
+	for _, framework := range enabledFrameworks(config) {
+		narrativeDir := path.Join(templateRoot, "templates", "compliance", framework)
+		entries, err := fs.ReadDir(tmplFS, narrativeDir)
+		if err != nil {
+			continue // No narratives shipped for this framework
+		}
+
+		destDir := filepath.Join(outputDir, "compliance", framework)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			srcPath := path.Join(narrativeDir, entry.Name())
+			raw, err := fs.ReadFile(tmplFS, srcPath)
+			if err != nil {
+				return fmt.Errorf("failed to read narrative template %s: %w", srcPath, err)
+			}
+
+			tmpl, err := template.New(entry.Name()).Parse(string(raw))
+			if err != nil {
+				return fmt.Errorf("failed to parse narrative template %s: %w", srcPath, err)
+			}
+
+			if err := os.MkdirAll(destDir, 0o750); err != nil {
+				return fmt.Errorf("failed to create narrative directory %s: %w", destDir, err)
+			}
+
+			destName := entry.Name()
+			if filepath.Ext(destName) == ".tmpl" {
+				destName = destName[:len(destName)-len(".tmpl")]
+			}
+			destPath := filepath.Join(destDir, destName)
+
+			out, err := os.Create(destPath) // #nosec G304 -- destPath is derived from the template's own narrative filenames
+			if err != nil {
+				return fmt.Errorf("failed to create narrative file %s: %w", destPath, err)
+			}
+			renderErr := tmpl.Execute(out, data)
+			closeErr := out.Close()
+			if renderErr != nil {
+				return fmt.Errorf("failed to render narrative template %s: %w", srcPath, renderErr)
+			}
+			if closeErr != nil {
+				return fmt.Errorf("failed to finalize narrative file %s: %w", destPath, closeErr)
+			}
+		}
+	}
+	return nil
+}
+
+// CollectEvidence writes <outputDir>/evidence/index.yaml linking each
+// control in manifest to the post_gen hook results (SBOM generation, scan
+// reports, provenance signing, ...) that produced evidence for it, matched
+// by hook command substring against the control's Refs.
+func CollectEvidence(manifest ComplianceManifest, hookResults []HookResult, outputDir string) error {
+	if len(manifest.Mappings) == 0 {
+		return nil
+	}
+
+	type evidenceEntry struct {
+		Framework string   `yaml:"framework"`
+		ControlID string   `yaml:"control_id"`
+		Artifacts []string `yaml:"artifacts,omitempty"`
+	}
+	var index []evidenceEntry
+
+	for _, mapping := range manifest.Mappings {
+		entry := evidenceEntry{Framework: mapping.Framework, ControlID: mapping.Control.ID}
+		for _, ref := range mapping.Control.Refs {
+			for _, result := range hookResults {
+				if result.Status == "ok" && ref != "" && strings.Contains(result.Command, ref) {
+					entry.Artifacts = append(entry.Artifacts, result.Command)
+				}
+			}
+		}
+		index = append(index, entry)
+	}
+
+	dir := filepath.Join(outputDir, "evidence")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+
+	encoded, err := yaml.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence index: %w", err)
+	}
+
+	path := filepath.Join(dir, "index.yaml")
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
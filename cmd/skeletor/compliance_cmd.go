@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// buildComplianceCommand returns the `skeletor compliance` command group.
+func buildComplianceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compliance",
+		Short: "Compliance-as-code commands for generated mixins",
+	}
+	cmd.AddCommand(buildComplianceReportCommand())
+	return cmd
+}
+
+// buildComplianceReportCommand reads a generated project's
+// compliance/controls.yaml and produces an HTML gap-analysis report: which
+// controls have matching evidence in evidence/index.yaml and which don't.
+func buildComplianceReportCommand() *cobra.Command {
+	var (
+		projectDir string
+		outputPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate an auditor-ready gap analysis from controls.yaml and evidence/index.yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := loadComplianceManifest(filepath.Join(projectDir, "compliance", "controls.yaml"))
+			if err != nil {
+				return err
+			}
+			evidenceByControl, err := loadEvidenceIndex(filepath.Join(projectDir, "evidence", "index.yaml"))
+			if err != nil {
+				return err
+			}
+
+			report := buildGapAnalysis(manifest, evidenceByControl)
+			html, err := renderGapAnalysisHTML(report)
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(outputPath, []byte(html), 0o600); err != nil {
+				return fmt.Errorf("failed to write compliance report to %s: %w", outputPath, err)
+			}
+			fmt.Printf("Compliance report written to %s\n", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectDir, "project-dir", ".", "Generated mixin directory containing compliance/controls.yaml")
+	cmd.Flags().StringVar(&outputPath, "output", "compliance-report.html", "Path to write the HTML gap analysis report")
+	return cmd
+}
+
+func loadComplianceManifest(path string) (ComplianceManifest, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is built from a --project-dir flag under operator control
+	if err != nil {
+		return ComplianceManifest{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var manifest ComplianceManifest
+	if err := yaml.Unmarshal(raw, &manifest); err != nil {
+		return ComplianceManifest{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// evidenceIndexEntry mirrors CollectEvidence's index.yaml entry shape.
+type evidenceIndexEntry struct {
+	Framework string   `yaml:"framework"`
+	ControlID string   `yaml:"control_id"`
+	Artifacts []string `yaml:"artifacts,omitempty"`
+}
+
+// loadEvidenceIndex reads evidence/index.yaml, returning an empty map (not
+// an error) if the file doesn't exist -- a project may not have run hooks
+// that produce evidence yet.
+func loadEvidenceIndex(path string) (map[string][]string, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is built from a --project-dir flag under operator control
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var entries []evidenceIndexEntry
+	if err := yaml.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	byControl := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		byControl[entry.Framework+"/"+entry.ControlID] = entry.Artifacts
+	}
+	return byControl, nil
+}
+
+// gapAnalysisRow is one control's report line: satisfied if it has at
+// least one evidence artifact.
+type gapAnalysisRow struct {
+	Framework string
+	Control   Control
+	Evidence  []string
+	Satisfied bool
+}
+
+type gapAnalysisReport struct {
+	Rows        []gapAnalysisRow
+	TotalCount  int
+	GapCount    int
+	GeneratedBy string
+}
+
+func buildGapAnalysis(manifest ComplianceManifest, evidenceByControl map[string][]string) gapAnalysisReport {
+	report := gapAnalysisReport{GeneratedBy: "skeletor compliance report"}
+	for _, mapping := range manifest.Mappings {
+		artifacts := evidenceByControl[mapping.Framework+"/"+mapping.Control.ID]
+		row := gapAnalysisRow{
+			Framework: mapping.Framework,
+			Control:   mapping.Control,
+			Evidence:  artifacts,
+			Satisfied: len(artifacts) > 0,
+		}
+		report.Rows = append(report.Rows, row)
+		report.TotalCount++
+		if !row.Satisfied {
+			report.GapCount++
+		}
+	}
+	return report
+}
+
+const gapAnalysisTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Compliance Gap Analysis</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.5rem; text-align: left; vertical-align: top; }
+.gap { background-color: #fdecea; }
+.satisfied { background-color: #eafaf1; }
+</style>
+</head>
+<body>
+<h1>Compliance Gap Analysis</h1>
+<p>{{ .TotalCount }} controls tracked, {{ .GapCount }} without evidence.</p>
+<table>
+<tr><th>Framework</th><th>Control</th><th>Title</th><th>Severity</th><th>Evidence</th></tr>
+{{ range .Rows }}
+<tr class="{{ if .Satisfied }}satisfied{{ else }}gap{{ end }}">
+<td>{{ .Framework }}</td>
+<td>{{ .Control.ID }}</td>
+<td>{{ .Control.Title }}</td>
+<td>{{ .Control.Severity }}</td>
+<td>{{ if .Evidence }}{{ range .Evidence }}{{ . }}<br>{{ end }}{{ else }}none{{ end }}</td>
+</tr>
+{{ end }}
+</table>
+</body>
+</html>
+`
+
+func renderGapAnalysisHTML(report gapAnalysisReport) (string, error) {
+	tmpl, err := template.New("gap-analysis").Parse(gapAnalysisTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse gap analysis template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("failed to render gap analysis report: %w", err)
+	}
+	return buf.String(), nil
+}
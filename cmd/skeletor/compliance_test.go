@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testComplianceConfig() *TemplateConfig {
+	return &TemplateConfig{
+		FeatureToggles: &FeatureToggles{
+			Compliance: &ComplianceFeatures{
+				Enabled: true,
+				SOC2:    true,
+				Policies: map[string]PolicyConfig{
+					"soc2": {Enabled: true, Rules: []string{"CC6.1", "unknown-rule"}},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildComplianceManifest_ResolvesKnownControls(t *testing.T) {
+	manifest := BuildComplianceManifest(testComplianceConfig(), nil)
+	require.Len(t, manifest.Mappings, 1)
+	assert.Equal(t, "soc2", manifest.Mappings[0].Framework)
+	assert.Equal(t, "CC6.1", manifest.Mappings[0].Control.ID)
+}
+
+func TestBuildComplianceManifest_NilFeatureToggles(t *testing.T) {
+	manifest := BuildComplianceManifest(&TemplateConfig{}, nil)
+	assert.Empty(t, manifest.Mappings)
+}
+
+func TestBuildComplianceManifest_CustomCatalogOverride(t *testing.T) {
+	config := testComplianceConfig()
+	custom := ControlCatalog{
+		"CC6.1": {ID: "CC6.1", Title: "Overridden title", Severity: "critical"},
+	}
+	manifest := BuildComplianceManifest(config, map[string]ControlCatalog{"soc2": custom})
+	require.Len(t, manifest.Mappings, 1)
+	assert.Equal(t, "Overridden title", manifest.Mappings[0].Control.Title)
+}
+
+func TestWriteComplianceManifest(t *testing.T) {
+	manifest := BuildComplianceManifest(testComplianceConfig(), nil)
+	outputDir := t.TempDir()
+
+	require.NoError(t, WriteComplianceManifest(manifest, outputDir))
+
+	path := filepath.Join(outputDir, "compliance", "controls.yaml")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "CC6.1")
+}
+
+func TestWriteComplianceManifest_EmptyManifestWritesNothing(t *testing.T) {
+	outputDir := t.TempDir()
+	require.NoError(t, WriteComplianceManifest(ComplianceManifest{}, outputDir))
+
+	_, err := os.Stat(filepath.Join(outputDir, "compliance"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCollectEvidence_MatchesHookCommandToRef(t *testing.T) {
+	manifest := ComplianceManifest{
+		Mappings: []ControlMapping{
+			{Framework: "soc2", Control: Control{ID: "CC6.1", Refs: []string{"syft"}}},
+		},
+	}
+	hookResults := []HookResult{
+		{Command: "syft packages dir:. -o spdx-json", Status: "ok"},
+		{Command: "go build ./...", Status: "ok"},
+	}
+	outputDir := t.TempDir()
+
+	require.NoError(t, CollectEvidence(manifest, hookResults, outputDir))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "evidence", "index.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "syft packages dir")
+}
+
+func TestLoadCustomControlCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "controls.yaml")
+	content := "- id: CUSTOM-1\n  title: Custom control\n  severity: medium\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	catalog, err := LoadCustomControlCatalog(path)
+	require.NoError(t, err)
+	require.Contains(t, catalog, "CUSTOM-1")
+	assert.Equal(t, "Custom control", catalog["CUSTOM-1"].Title)
+}
+
+func TestBuildGapAnalysis(t *testing.T) {
+	manifest := ComplianceManifest{
+		Mappings: []ControlMapping{
+			{Framework: "soc2", Control: Control{ID: "CC6.1"}},
+			{Framework: "soc2", Control: Control{ID: "CC7.2"}},
+		},
+	}
+	evidence := map[string][]string{"soc2/CC6.1": {"syft output"}}
+
+	report := buildGapAnalysis(manifest, evidence)
+	assert.Equal(t, 2, report.TotalCount)
+	assert.Equal(t, 1, report.GapCount)
+}
+
+func TestRenderGapAnalysisHTML(t *testing.T) {
+	report := buildGapAnalysis(ComplianceManifest{
+		Mappings: []ControlMapping{{Framework: "soc2", Control: Control{ID: "CC6.1", Title: "Logical access"}}},
+	}, map[string][]string{})
+
+	html, err := renderGapAnalysisHTML(report)
+	require.NoError(t, err)
+	assert.Contains(t, html, "CC6.1")
+	assert.Contains(t, html, "Logical access")
+}
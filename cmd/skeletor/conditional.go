@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ConditionalPath is one value in TemplateConfig.ConditionalPaths. It
+// unmarshals from either a bare string -- the legacy form, a Go
+// text/template that evaluates to the relative source path to use for this
+// destination, or to empty to skip it -- or a structured object,
+// {"when": "<DSL expression>"}, whose boolean result (via EvaluateCondition)
+// gates the destination: a single file, or, when the map key names a
+// directory, its whole subtree.
+type ConditionalPath struct {
+	// Template is the legacy template-string form. Empty when When is set.
+	Template string
+	// When is the structured DSL form. Empty when Template is set.
+	When string
+}
+
+// UnmarshalJSON accepts a bare string (legacy) or {"when": "..."} (structured).
+func (c *ConditionalPath) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		c.Template = legacy
+		c.When = ""
+		return nil
+	}
+
+	var structured struct {
+		When string `json:"when"`
+	}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return fmt.Errorf("conditional path entry must be a string or an object with \"when\": %w", err)
+	}
+	if structured.When == "" {
+		return fmt.Errorf("conditional path object entry must set a non-empty \"when\"")
+	}
+	c.Template = ""
+	c.When = structured.When
+	return nil
+}
+
+// conditionEnv is the context a ConditionalPath DSL expression is evaluated
+// against: the feature toggles backing feature(), and the template
+// variables backing var.Name comparisons.
+type conditionEnv struct {
+	toggles *FeatureToggles
+	vars    map[string]interface{}
+}
+
+// EvaluateCondition parses and evaluates expr, the ConditionalPaths DSL:
+// feature("category.feature"), any(...), all(...), not(...) and
+// var.Name == "value" / var.Name != "value" comparisons, combined with
+// && / || and parentheses. feature() is backed by toggles.IsFeatureEnabled,
+// so it honors the same enabled/disabled logic as everywhere else toggles
+// are consulted. It is the engine behind ConditionalPath.When and the
+// {{#if feature "..."}} content preprocessor.
+func EvaluateCondition(expr string, toggles *FeatureToggles, vars map[string]interface{}) (bool, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	parser := &conditionParser{tokens: tokens, env: conditionEnv{toggles: toggles, vars: vars}}
+	result, err := parser.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	if !parser.atEnd() {
+		return false, fmt.Errorf("invalid condition %q: unexpected trailing input %q", expr, parser.peek().text)
+	}
+	return result, nil
+}
+
+// splitFeatureRef splits a feature() argument of the form "category.feature"
+// into its two parts.
+func splitFeatureRef(ref string) (category, feature string, ok bool) {
+	idx := strings.IndexByte(ref, '.')
+	if idx <= 0 || idx == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:idx], ref[idx+1:], true
+}
+
+// --- tokenizer ---
+
+type conditionTokenKind int
+
+const (
+	tokEOF conditionTokenKind = iota
+	tokLParen
+	tokRParen
+	tokComma
+	tokDot
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokString
+	tokIdent
+)
+
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+// tokenizeCondition lexes a ConditionalPaths DSL expression.
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, conditionToken{tokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, conditionToken{tokRParen, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, conditionToken{tokComma, ","})
+			i++
+		case c == '.':
+			tokens = append(tokens, conditionToken{tokDot, "."})
+			i++
+		case c == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			tokens = append(tokens, conditionToken{tokAnd, "&&"})
+			i += 2
+		case c == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			tokens = append(tokens, conditionToken{tokOr, "||"})
+			i += 2
+		case c == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, conditionToken{tokEq, "=="})
+			i += 2
+		case c == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, conditionToken{tokNeq, "!="})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, conditionToken{tokNot, "!"})
+			i++
+		case c == '"' || c == '\'':
+			quote := c
+			j := i + 1
+			for j < len(runes) && runes[j] != quote {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal starting at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{tokString, string(runes[i+1 : j])})
+			i = j + 1
+		case isConditionIdentStart(c):
+			j := i + 1
+			for j < len(runes) && isConditionIdentPart(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, conditionToken{tokIdent, string(runes[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isConditionIdentStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isConditionIdentPart(c rune) bool {
+	return isConditionIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// --- recursive-descent parser ---
+
+// conditionParser implements the grammar:
+//
+//	or      := and ( "||" and )*
+//	and     := unary ( "&&" unary )*
+//	unary   := "!" unary | primary
+//	primary := "(" or ")" | IDENT "(" args ")" | "true" | "false" | varCmp
+//	varCmp  := "var" "." IDENT ( "==" | "!=" ) ( STRING | IDENT )
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	env    conditionEnv
+}
+
+func (p *conditionParser) peek() conditionToken {
+	if p.pos >= len(p.tokens) {
+		return conditionToken{kind: tokEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() conditionToken {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *conditionParser) expect(kind conditionTokenKind) error {
+	if p.peek().kind != kind {
+		return fmt.Errorf("unexpected token %q", p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *conditionParser) atEnd() bool {
+	return p.peek().kind == tokEOF
+}
+
+func (p *conditionParser) parseOr() (bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (bool, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		v, err := p.parseUnary()
+		return !v, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (bool, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokLParen:
+		p.next()
+		v, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return false, err
+		}
+		return v, nil
+	case tokIdent:
+		p.next()
+		switch {
+		case p.peek().kind == tokLParen:
+			return p.parseCall(tok.text)
+		case tok.text == "true":
+			return true, nil
+		case tok.text == "false":
+			return false, nil
+		case tok.text == "var":
+			return p.parseVarComparison()
+		default:
+			return false, fmt.Errorf("unexpected identifier %q", tok.text)
+		}
+	default:
+		return false, fmt.Errorf("unexpected token %q", tok.text)
+	}
+}
+
+// parseCall parses the argument list of a feature()/not()/any()/all() call;
+// the opening "(" is the next token.
+func (p *conditionParser) parseCall(name string) (bool, error) {
+	if err := p.expect(tokLParen); err != nil {
+		return false, err
+	}
+	switch name {
+	case "feature":
+		if p.peek().kind != tokString {
+			return false, fmt.Errorf("feature() requires a string argument")
+		}
+		arg := p.next().text
+		if err := p.expect(tokRParen); err != nil {
+			return false, err
+		}
+		category, feature, ok := splitFeatureRef(arg)
+		if !ok {
+			return false, fmt.Errorf("feature() argument must be \"category.feature\", got %q", arg)
+		}
+		return p.env.toggles.IsFeatureEnabled(category, feature), nil
+	case "not":
+		v, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return false, err
+		}
+		return !v, nil
+	case "any", "all":
+		var values []bool
+		for {
+			v, err := p.parseOr()
+			if err != nil {
+				return false, err
+			}
+			values = append(values, v)
+			if p.peek().kind == tokComma {
+				p.next()
+				continue
+			}
+			break
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return false, err
+		}
+		if len(values) == 0 {
+			return false, fmt.Errorf("%s() requires at least one argument", name)
+		}
+		result := name == "all"
+		for _, v := range values {
+			if name == "any" {
+				result = result || v
+			} else {
+				result = result && v
+			}
+		}
+		return result, nil
+	default:
+		return false, fmt.Errorf("unknown function %q", name)
+	}
+}
+
+// parseVarComparison parses the rest of "var.Name == <value>" /
+// "var.Name != <value>"; the leading "var" identifier has already been
+// consumed.
+func (p *conditionParser) parseVarComparison() (bool, error) {
+	if err := p.expect(tokDot); err != nil {
+		return false, err
+	}
+	if p.peek().kind != tokIdent {
+		return false, fmt.Errorf("expected a variable name after \"var.\"")
+	}
+	name := p.next().text
+
+	op := p.peek()
+	if op.kind != tokEq && op.kind != tokNeq {
+		return false, fmt.Errorf("expected \"==\" or \"!=\" after var.%s", name)
+	}
+	p.next()
+
+	rhs := p.peek()
+	if rhs.kind != tokString && rhs.kind != tokIdent {
+		return false, fmt.Errorf("expected a string or identifier after comparison operator")
+	}
+	p.next()
+
+	lhs := fmt.Sprintf("%v", p.env.vars[name])
+	equal := lhs == rhs.text
+	if op.kind == tokNeq {
+		return !equal, nil
+	}
+	return equal, nil
+}
+
+// featureBlockPattern matches a single, non-nested
+// {{#if feature "category.feature"}}...{{#else}}...{{/if}} block in raw
+// template content: group 1 is the feature reference, group 2 the if-body,
+// group 4 the optional else-body.
+var featureBlockPattern = regexp.MustCompile(`(?s)\{\{#if feature "([^"]+)"\}\}(.*?)(\{\{#else\}\}(.*?))?\{\{/if\}\}`)
+
+// preprocessFeatureBlocks expands {{#if feature "..."}}...{{/if}} blocks in
+// content against toggles, before the result is parsed and executed as a Go
+// template. This lets template authors gate a chunk of file content on a
+// feature toggle directly, instead of threading a `.Features.category.feature`
+// field path through every template's data. Blocks do not nest.
+func preprocessFeatureBlocks(content string, toggles *FeatureToggles) string {
+	return featureBlockPattern.ReplaceAllStringFunc(content, func(block string) string {
+		m := featureBlockPattern.FindStringSubmatch(block)
+		category, feature, ok := splitFeatureRef(m[1])
+		if ok && toggles.IsFeatureEnabled(category, feature) {
+			return m[2]
+		}
+		return m[4]
+	})
+}
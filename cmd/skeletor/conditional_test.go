@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalPath_UnmarshalJSON_Legacy(t *testing.T) {
+	var cp ConditionalPath
+	require.NoError(t, json.Unmarshal([]byte(`"{{ if .Foo }}bar.tmpl{{ end }}"`), &cp))
+	assert.Equal(t, "{{ if .Foo }}bar.tmpl{{ end }}", cp.Template)
+	assert.Empty(t, cp.When)
+}
+
+func TestConditionalPath_UnmarshalJSON_Structured(t *testing.T) {
+	var cp ConditionalPath
+	require.NoError(t, json.Unmarshal([]byte(`{"when": "feature('auth.mfa')"}`), &cp))
+	assert.Equal(t, "feature('auth.mfa')", cp.When)
+	assert.Empty(t, cp.Template)
+}
+
+func TestConditionalPath_UnmarshalJSON_StructuredRequiresWhen(t *testing.T) {
+	var cp ConditionalPath
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &cp))
+}
+
+func TestEvaluateCondition_Feature(t *testing.T) {
+	toggles := &FeatureToggles{Auth: &AuthFeatures{Enabled: true, MFA: true}}
+
+	keep, err := EvaluateCondition(`feature("auth.mfa")`, toggles, nil)
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	keep, err = EvaluateCondition(`feature("auth.sso")`, toggles, nil)
+	require.NoError(t, err)
+	assert.False(t, keep)
+}
+
+func TestEvaluateCondition_AnyAllNot(t *testing.T) {
+	toggles := &FeatureToggles{
+		Security: &SecurityFeatures{Enabled: true, RateLimiting: true},
+		Auth:     &AuthFeatures{Enabled: true, MFA: true},
+	}
+
+	keep, err := EvaluateCondition(`any(feature("auth.sso"), feature("security.rate_limiting"))`, toggles, nil)
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	keep, err = EvaluateCondition(`all(feature("auth.mfa"), feature("security.rate_limiting"))`, toggles, nil)
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	keep, err = EvaluateCondition(`all(feature("auth.mfa"), feature("auth.sso"))`, toggles, nil)
+	require.NoError(t, err)
+	assert.False(t, keep)
+
+	keep, err = EvaluateCondition(`not(feature("auth.sso"))`, toggles, nil)
+	require.NoError(t, err)
+	assert.True(t, keep)
+}
+
+func TestEvaluateCondition_VarComparisonAndLogicalOps(t *testing.T) {
+	toggles := &FeatureToggles{Compliance: &ComplianceFeatures{Enabled: true, SOC2: true}}
+	vars := map[string]interface{}{"ComplianceLevel": "high"}
+
+	keep, err := EvaluateCondition(`feature('compliance.soc2') && var.ComplianceLevel == 'high'`, toggles, vars)
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	keep, err = EvaluateCondition(`feature('compliance.soc2') && var.ComplianceLevel == 'low'`, toggles, vars)
+	require.NoError(t, err)
+	assert.False(t, keep)
+
+	keep, err = EvaluateCondition(`var.ComplianceLevel != 'low' || feature('compliance.gdpr')`, toggles, vars)
+	require.NoError(t, err)
+	assert.True(t, keep)
+}
+
+func TestEvaluateCondition_ParensAndNilToggles(t *testing.T) {
+	keep, err := EvaluateCondition(`not(feature("auth.mfa")) && (true || false)`, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, keep)
+}
+
+func TestEvaluateCondition_Errors(t *testing.T) {
+	cases := []string{
+		`feature(auth.mfa)`,    // unquoted argument
+		`feature("badref")`,    // missing category.feature dot
+		`any()`,                // no arguments
+		`var.Foo ===`,          // malformed operator
+		`feature("a.b"`,        // unterminated call
+		`feature("a.b") &&`,    // dangling operator
+		`feature("a.b") extra`, // trailing input
+	}
+	for _, expr := range cases {
+		_, err := EvaluateCondition(expr, &FeatureToggles{}, nil)
+		assert.Errorf(t, err, "expected error for %q", expr)
+	}
+}
+
+func TestPreprocessFeatureBlocks(t *testing.T) {
+	toggles := &FeatureToggles{Auth: &AuthFeatures{Enabled: true, MFA: true}}
+
+	out := preprocessFeatureBlocks(`before {{#if feature "auth.mfa"}}mfa-on{{#else}}mfa-off{{/if}} after`, toggles)
+	assert.Equal(t, "before mfa-on after", out)
+
+	out = preprocessFeatureBlocks(`{{#if feature "auth.sso"}}sso-on{{#else}}sso-off{{/if}}`, toggles)
+	assert.Equal(t, "sso-off", out)
+
+	out = preprocessFeatureBlocks(`{{#if feature "auth.mfa"}}mfa-on{{/if}}`, toggles)
+	assert.Equal(t, "mfa-on", out)
+
+	out = preprocessFeatureBlocks(`{{#if feature "auth.sso"}}sso-on{{/if}}`, toggles)
+	assert.Equal(t, "", out)
+
+	out = preprocessFeatureBlocks("no blocks here", toggles)
+	assert.Equal(t, "no blocks here", out)
+}
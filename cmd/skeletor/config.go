@@ -2,27 +2,89 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors" // Import errors
 	"fmt"
 	"io/fs" // Import io/fs
 	"os"
 	"os/exec" // Import os/exec
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime" // Import runtime
 	"strings"
 	"text/template"
+	"time"
+
+	"go.uber.org/multierr"
 )
 
 // TemplateConfig represents the configuration for a template
 type TemplateConfig struct {
-	Name             string              `json:"name"`
-	Description      string              `json:"description"`
-	Variables        map[string]Variable `json:"variables"`
-	Hooks            map[string][]string `json:"hooks"`
-	Ignore           []string            `json:"ignore"`
-	ConditionalPaths map[string]string   `json:"conditional_paths,omitempty"` // Map of relative path -> Go template condition string
-	FeatureToggles   *FeatureToggles     `json:"feature_toggles,omitempty"`   // Enterprise feature toggle configuration
+	Name             string                     `json:"name"`
+	Description      string                     `json:"description"`
+	Variables        map[string]Variable        `json:"variables"`
+	Hooks            map[string][]HookStep      `json:"hooks"`
+	HooksRuntime     HookRuntime                `json:"hooks_runtime,omitempty"` // Container backend for hooks that set their own "image": podman, or docker otherwise; a hook without an image always runs on the host
+	Ignore           []string                   `json:"ignore"`
+	SkipPatterns     []string                   `json:"skip_patterns,omitempty"`     // doublestar globs matched against a candidate's destRelPath; a directory match prunes its whole subtree, a leaf match drops just that file (see evaluateSkipPatterns)
+	FileConditions   map[string]string          `json:"file_conditions,omitempty"`   // doublestar glob -> Go template expression (e.g. "pkg/security/**": "{{ .EnableSecurity }}"); the most specific matching pattern's template is rendered against the same data every file renders with, and a falsy result drops the file without needing a conditional_paths entry
+	ConditionalPaths map[string]ConditionalPath `json:"conditional_paths,omitempty"` // Map of relative path -> legacy template string or {"when": DSL expr}
+	PathPolicy       string                     `json:"path_policy,omitempty"`       // Vault-policy-style `path "glob" { capabilities = [...] }` blocks (see ParsePathPolicy); additive to, and takes precedence over, ConditionalPaths for any path a block also matches
+	FeatureToggles   *FeatureToggles            `json:"feature_toggles,omitempty"`   // Enterprise feature toggle configuration
+	Functions        []string                   `json:"functions,omitempty"`         // Names of builtinHelperFuncs to add to every template's FuncMap
+	Constraints      map[string]string          `json:"constraints,omitempty"`       // Map of relative .tmpl path -> build-tag-like constraint expression (see EvaluateConstraint); a "<path>.constraints" sidecar file takes precedence over this
+	PolicyRuleDirs   []string                   `json:"policy_rule_dirs,omitempty"`  // Template-relative directories of Rego policy rules (see pkg/policy) gating generation inputs/outputs, on top of any bundled or --policy-dir rules
+	Licenses         []LicensePolicy            `json:"licenses,omitempty"`          // Per-subtree SPDX license headers (see LicensePolicy); matched in order, first Root match wins
+	Dependencies     []Dependency               `json:"dependencies,omitempty"`      // Sub-templates composed into this one's output (see Dependency, resolveDependencies)
+
+	// sourceFile and rawSource are set by LoadTemplateConfig so
+	// locationOf can point a validation error at the line it came from.
+	// They're unset (and locationOf degrades to a file-only Location) for
+	// a TemplateConfig built by hand, e.g. in tests.
+	sourceFile string
+	rawSource  []byte
+}
+
+// locationOf finds where varName is declared in c's source config file, for
+// a ConfigError raised while validating its value. It falls back to a
+// file-only Location (or the zero Location, if c wasn't loaded from a file
+// at all) when the exact line can't be found -- a best-effort line/column
+// lookup over the raw source text, not a real parse-time position.
+func (c *TemplateConfig) locationOf(varName string) Location {
+	if c == nil || c.sourceFile == "" {
+		return Location{}
+	}
+	if line, col, ok := findKeyPosition(c.rawSource, varName); ok {
+		return Location{File: c.sourceFile, Line: line, Column: col}
+	}
+	return Location{File: c.sourceFile}
+}
+
+// findKeyPosition scans src for varName used as a key -- "varName": (JSON)
+// or varName: / varName = (YAML/TOML) -- and returns its 1-based line and
+// column. It's a plain text scan, not format-aware, so it can be fooled by
+// a key name that also appears as a string value; good enough for pointing
+// a human at roughly the right line.
+func findKeyPosition(src []byte, varName string) (line, col int, ok bool) {
+	needles := []string{`"` + varName + `"`, varName + ":", varName + "="}
+	line = 1
+	col = 1
+	for i := 0; i < len(src); i++ {
+		for _, needle := range needles {
+			if i+len(needle) <= len(src) && string(src[i:i+len(needle)]) == needle {
+				return line, col, true
+			}
+		}
+		if src[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return 0, 0, false
 }
 
 // FeatureToggles represents enterprise feature toggle configuration
@@ -31,27 +93,152 @@ type FeatureToggles struct {
 	Compliance    *ComplianceFeatures    `json:"compliance,omitempty"`
 	Auth          *AuthFeatures          `json:"auth,omitempty"`
 	Observability *ObservabilityFeatures `json:"observability,omitempty"`
+
+	// Enforcement holds OPA Gatekeeper-style scoped enforcement actions,
+	// keyed by category then feature name, e.g. Enforcement["security"]["input_validation"].
+	// It is independent of the Enabled bools above: a feature can be toggled
+	// on/off by Security/Compliance/Auth/Observability while separately
+	// carrying zero or more ScopedActions here.
+	Enforcement map[string]map[string]FeatureEntry `json:"enforcement,omitempty"`
+
+	// PolicyInput is the variable set each category's PolicyExpr is
+	// evaluated against (typically TemplateConfig variables merged with the
+	// process environment). Set it via SetPolicyInput before calling
+	// GetEnabledFeatures.
+	PolicyInput map[string]interface{} `json:"-"`
+
+	policyEngine PolicyEngine
+	decisions    []Decision
+}
+
+// SetPolicyEngine overrides the PolicyEngine used to evaluate PolicyExpr
+// fields, falling back to DefaultPolicyEngine when unset.
+func (ft *FeatureToggles) SetPolicyEngine(engine PolicyEngine) {
+	ft.policyEngine = engine
+}
+
+// SetPolicyInput sets the variables PolicyExpr fields are evaluated against.
+func (ft *FeatureToggles) SetPolicyInput(input map[string]interface{}) {
+	ft.PolicyInput = input
+}
+
+// Decisions returns the policy decision trace recorded by the most recent
+// GetEnabledFeatures call, one Decision per feature, for --explain output
+// and other audit trails.
+func (ft *FeatureToggles) Decisions() []Decision {
+	return ft.decisions
+}
+
+// engine returns ft's configured PolicyEngine, or DefaultPolicyEngine.
+func (ft *FeatureToggles) engine() PolicyEngine {
+	if ft.policyEngine != nil {
+		return ft.policyEngine
+	}
+	return DefaultPolicyEngine
+}
+
+// evaluateCategoryPolicy runs expr (a category's PolicyExpr) against
+// ft.PolicyInput and records a Decision for each of features. An empty expr
+// always passes. An evaluation error is treated as a denial so a broken
+// policy fails closed rather than silently enabling the category.
+func (ft *FeatureToggles) evaluateCategoryPolicy(category, expr string, features []string) bool {
+	if expr == "" {
+		for _, feature := range features {
+			ft.decisions = append(ft.decisions, Decision{
+				Category: category, Feature: feature, Enabled: true,
+				Reason: "static toggle, no policy expression",
+			})
+		}
+		return true
+	}
+
+	allowed, err := ft.engine().Evaluate(expr, ft.PolicyInput)
+	reason := fmt.Sprintf("policy %q -> %t", expr, allowed)
+	if err != nil {
+		allowed = false
+		reason = fmt.Sprintf("policy %q error: %v", expr, err)
+	}
+	for _, feature := range features {
+		ft.decisions = append(ft.decisions, Decision{
+			Category: category, Feature: feature, Enabled: allowed, Expr: expr, Reason: reason,
+		})
+	}
+	return allowed
+}
+
+// Action is an OPA Gatekeeper-style enforcement action.
+type Action string
+
+const (
+	ActionDeny   Action = "deny"
+	ActionWarn   Action = "warn"
+	ActionDryRun Action = "dryrun"
+)
+
+// EnforcementScope is the pass at which a ScopedAction applies.
+type EnforcementScope string
+
+const (
+	ScopeGenerate    EnforcementScope = "generate"     // mixin scaffolding / template rendering
+	ScopeValidate    EnforcementScope = "validate"     // CI validation of a generated mixin
+	ScopeRuntimeHook EnforcementScope = "runtime-hook" // a generated mixin's own runtime hooks
+)
+
+// ScopedAction pairs an Action with the EnforcementScope it applies to, so a
+// feature can e.g. warn at generation time but deny at CI-validate time.
+type ScopedAction struct {
+	Action Action           `json:"action"`
+	Scope  EnforcementScope `json:"scope"`
+}
+
+// FeatureEntry is a feature's enforcement configuration. It unmarshals from
+// either a bare bool -- the legacy form, equivalent to {"enabled": <bool>}
+// with no scoped actions -- or the full object form, so existing
+// template.json files keep working unmodified.
+type FeatureEntry struct {
+	Enabled     bool           `json:"enabled"`
+	Enforcement []ScopedAction `json:"enforcement,omitempty"`
+}
+
+// UnmarshalJSON accepts either a bare bool (legacy) or the full object form.
+func (e *FeatureEntry) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		e.Enabled = enabled
+		e.Enforcement = nil
+		return nil
+	}
+
+	type featureEntryAlias FeatureEntry
+	var alias featureEntryAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("feature entry must be a bool or an object with \"enabled\"/\"enforcement\": %w", err)
+	}
+	*e = FeatureEntry(alias)
+	return nil
 }
 
 // SecurityFeatures represents security-related feature toggles
 type SecurityFeatures struct {
-	Enabled               bool `json:"enabled"`
-	InputValidation       bool `json:"input_validation"`
-	RateLimiting          bool `json:"rate_limiting"`
-	SecureHeaders         bool `json:"secure_headers"`
-	VulnerabilityScanning bool `json:"vulnerability_scanning"`
-	PolicyEnforcement     bool `json:"policy_enforcement"`
+	Enabled               bool   `json:"enabled"`
+	InputValidation       bool   `json:"input_validation"`
+	RateLimiting          bool   `json:"rate_limiting"`
+	SecureHeaders         bool   `json:"secure_headers"`
+	VulnerabilityScanning bool   `json:"vulnerability_scanning"`
+	PolicyEnforcement     bool   `json:"policy_enforcement"`
+	PolicyExpr            string `json:"policy_expr,omitempty"` // CEL/Rego expression gating the whole category, e.g. "region in ['us','eu']"
 }
 
 // ComplianceFeatures represents compliance framework feature toggles
 type ComplianceFeatures struct {
-	Enabled  bool                    `json:"enabled"`
-	SOC2     bool                    `json:"soc2"`
-	GDPR     bool                    `json:"gdpr"`
-	HIPAA    bool                    `json:"hipaa"`
-	PCIDSS   bool                    `json:"pci_dss"`
-	Custom   map[string]bool         `json:"custom,omitempty"`
-	Policies map[string]PolicyConfig `json:"policies,omitempty"`
+	Enabled    bool                    `json:"enabled"`
+	SOC2       bool                    `json:"soc2"`
+	GDPR       bool                    `json:"gdpr"`
+	HIPAA      bool                    `json:"hipaa"`
+	PCIDSS     bool                    `json:"pci_dss"`
+	Custom     map[string]bool         `json:"custom,omitempty"`
+	Policies   map[string]PolicyConfig `json:"policies,omitempty"`
+	PolicyExpr string                  `json:"policy_expr,omitempty"` // CEL/Rego expression gating the whole category, e.g. "industry == 'health'"
 }
 
 // AuthFeatures represents authentication and authorization feature toggles
@@ -64,6 +251,7 @@ type AuthFeatures struct {
 	Vault        bool            `json:"vault"`
 	SessionMgmt  bool            `json:"session_management"`
 	Integrations map[string]bool `json:"integrations,omitempty"`
+	PolicyExpr   string          `json:"policy_expr,omitempty"` // CEL/Rego expression gating the whole category
 }
 
 // ObservabilityFeatures represents observability and monitoring feature toggles
@@ -77,6 +265,7 @@ type ObservabilityFeatures struct {
 	AuditLogging   bool            `json:"audit_logging"`
 	Tracing        bool            `json:"tracing"`
 	Backends       map[string]bool `json:"backends,omitempty"`
+	PolicyExpr     string          `json:"policy_expr,omitempty"` // CEL/Rego expression gating the whole category
 }
 
 // PolicyConfig represents configuration for compliance policies
@@ -91,133 +280,573 @@ type PolicyConfig struct {
 type Variable struct {
 	Description string      `json:"description"`
 	Default     interface{} `json:"default,omitempty"`
-	Type        string      `json:"type,omitempty"` // string, bool, int, etc.
+	Type        string      `json:"type,omitempty"` // string, bool, int, list, map, etc.
 	Required    bool        `json:"required,omitempty"`
 	Choices     []string    `json:"choices,omitempty"` // For enum-like variables
+
+	// Schema is a JSON-Schema-like validation spec for this variable's
+	// resolved value. When unset, buildTemplateData synthesizes an
+	// equivalent schema from Type/Choices/Required, so existing
+	// template.json files keep validating identically.
+	Schema *VariableSchema `json:"schema,omitempty"`
+
+	// OnlyIf is a Go template expression (the same truthy rules as a
+	// HookStep's When: non-empty and not "false"/"0") evaluated against the
+	// variables collected so far; this variable is skipped entirely -- no
+	// prompt, no value, no required error -- unless it renders truthy. SkipIf
+	// is its inverse, skipped when it renders truthy, for whichever reads
+	// more naturally at a given call site (e.g. "SecurityFeatures" wants
+	// OnlyIf: "{{ .EnableSecurity }}"). At most one should be set; OnlyIf
+	// wins if both are.
+	OnlyIf string `json:"only_if,omitempty"`
+	SkipIf string `json:"skip_if,omitempty"`
+
+	// Validation layers checks beyond Schema's JSON-Schema-style keywords
+	// onto this variable's resolved value.
+	Validation *VariableValidation `json:"validation,omitempty"`
+
+	// Group categorizes this variable for the --interactive=tui wizard's
+	// one-page-per-group layout (see wizard.go), e.g. "basic",
+	// "enterprise", "compliance". Defaults to "general" when unset; the
+	// sequential --interactive=prompt (the default) path ignores it.
+	Group string `json:"group,omitempty"`
+}
+
+// VariableValidation is an additional validation pass on a Variable's
+// resolved value, run after Schema's keywords pass. Regex and the
+// length/numeric bounds duplicate what Schema's Pattern/MinLength/MaxLength/
+// Minimum/Maximum already offer -- kept here too since a template author
+// reaching for "validation" rather than "schema" shouldn't have to know
+// they're the same mechanism -- and Expr covers checks neither vocabulary
+// has a keyword for (e.g. comparing two variables against each other).
+type VariableValidation struct {
+	Regex     string   `json:"regex,omitempty"`
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	// Expr is a Go template (e.g. "{{ eq .Password .PasswordConfirm }}")
+	// evaluated against the data collected so far, including this
+	// variable's own already-coerced value under its own name; a falsy
+	// result fails validation with Message, or a generic message if unset.
+	Expr    string `json:"expr,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
-// LoadTemplateConfig loads the template configuration from the given filesystem and root directory
+// VariableSchema is a minimal JSON-Schema-like validation spec for a
+// Variable, modeled on how cnab-go's bundle/definition.Schema validates
+// bundle parameters: a declared type plus a handful of keyword constraints.
+// Unlike a full JSON Schema implementation, only the keywords below are
+// understood; anything else in the "schema" object is accepted but ignored.
+type VariableSchema struct {
+	Type      string        `json:"type,omitempty"` // string, bool/boolean, int/integer, number, array, object
+	Enum      []interface{} `json:"enum,omitempty"`
+	Pattern   string        `json:"pattern,omitempty"`
+	Minimum   *float64      `json:"minimum,omitempty"`
+	Maximum   *float64      `json:"maximum,omitempty"`
+	MinLength *int          `json:"minLength,omitempty"`
+	MaxLength *int          `json:"maxLength,omitempty"`
+	Required  bool          `json:"required,omitempty"`
+}
+
+// templateConfigFilenames is the set of template config filenames
+// LoadTemplateConfig looks for in a template source, in the order they're
+// tried. Exactly one may be present -- a template source defining more than
+// one is almost always a leftover from switching formats, not an
+// intentional combination, and silently preferring one would hide that.
+var templateConfigFilenames = []string{"template.json", "template.toml", "template.yaml", "template.yml"}
+
+// LoadTemplateConfig loads the template configuration from the given
+// filesystem and root directory, auto-detecting which of
+// templateConfigFilenames the template source uses.
 func LoadTemplateConfig(tmplFS fs.FS, templateRoot string) (*TemplateConfig, error) {
-	configPath := filepath.Join(templateRoot, "template.json") // Path within the FS
+	var found []string
+	for _, filename := range templateConfigFilenames {
+		if _, err := fs.Stat(tmplFS, path.Join(templateRoot, filename)); err == nil {
+			found = append(found, filename)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to stat template config %s: %w", path.Join(templateRoot, filename), err)
+		}
+	}
 
-	// Check if config file exists within the FS using fs.Stat
-	if _, err := fs.Stat(tmplFS, configPath); errors.Is(err, fs.ErrNotExist) {
-		// Return default config if no config file exists in the source FS
+	switch len(found) {
+	case 0:
 		fmt.Println("Warning: template.json not found in template source, using default configuration.")
-		return &TemplateConfig{
-			Name:        "Porter Mixin Template (Default)", // Indicate default
-			Description: "Default Porter mixin template",
-			Variables: map[string]Variable{
-				"MixinName": {
-					Description: "Name of the mixin (lowercase)",
-					Type:        "string",
-					Required:    true,
-				},
-				"AuthorName": {
-					Description: "Author name",
-					Type:        "string",
-					Required:    true,
-				},
-				"ModulePath": {
-					Description: "Go module path",
-					Type:        "string",
-					Default:     "github.com/getporter/{{ .MixinName }}",
-				},
-				// ComplianceLevel is now defined in template.json
-				"MixinFeedRepoURL": { // Add MixinFeedRepoURL variable
-					Description: "Git URL for the mixin feed repository (e.g., git@github.com:YOUR/packages.git)",
-					Type:        "string",
-					Required:    false, // Optional
-				},
-				"MixinFeedBranch": { // Add MixinFeedBranch variable
-					Description: "Branch in the mixin feed repository to commit to",
-					Type:        "string",
-					Default:     "main",
-					Required:    false,
-				},
-				"AuthorEmail": { // Add AuthorEmail variable
-					Description: "Author's email for security contact",
-					Type:        "string",
-					Required:    false, // Optional, defaults in security.txt
-				},
-			},
-		}, nil
-	} else if err != nil {
-		// Other error during stat
-		return nil, fmt.Errorf("failed to stat template config %s: %w", configPath, err)
+		return defaultTemplateConfig(), nil
+	case 1:
+		// Exactly one config file, the expected case -- fall through.
+	default:
+		return nil, fmt.Errorf("template source defines more than one config file (%s); keep only one", strings.Join(found, ", "))
 	}
 
-	// Read and parse config file from the FS using fs.ReadFile
+	configPath := path.Join(templateRoot, found[0])
 	data, err := fs.ReadFile(tmplFS, configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template config %s from FS: %w", configPath, err)
 	}
 
-	var config TemplateConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Reuse Source.decode/treeToConfig from configloader.go: they already
+	// know how to turn json/toml/yaml bytes into a generic tree and back
+	// into a TemplateConfig, so template.toml/template.yaml get every
+	// custom UnmarshalJSON method a TemplateConfig field defines --
+	// ConditionalPath, HookStep, FeatureEntry -- for free, the same way a
+	// LoadLayeredConfig source does.
+	tree, _, err := (Source{Literal: data, Format: formatsByExt[strings.ToLower(filepath.Ext(found[0]))]}).decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template config %s: %w", configPath, err)
+	}
+
+	config, err := treeToConfig(tree)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse template config: %w", err)
 	}
+	config.sourceFile = configPath
+	config.rawSource = data
+
+	return config, nil
+}
 
-	return &config, nil
+// defaultTemplateConfig is the configuration LoadTemplateConfig falls back
+// to when a template source defines none of templateConfigFilenames.
+func defaultTemplateConfig() *TemplateConfig {
+	return &TemplateConfig{
+		Name:        "Porter Mixin Template (Default)", // Indicate default
+		Description: "Default Porter mixin template",
+		Variables: map[string]Variable{
+			"MixinName": {
+				Description: "Name of the mixin (lowercase)",
+				Type:        "string",
+				Required:    true,
+			},
+			"AuthorName": {
+				Description: "Author name",
+				Type:        "string",
+				Required:    true,
+			},
+			"ModulePath": {
+				Description: "Go module path",
+				Type:        "string",
+				Default:     "github.com/getporter/{{ .MixinName }}",
+			},
+			// ComplianceLevel is now defined in template.json
+			"MixinFeedRepoURL": { // Add MixinFeedRepoURL variable
+				Description: "Git URL for the mixin feed repository (e.g., git@github.com:YOUR/packages.git)",
+				Type:        "string",
+				Required:    false, // Optional
+			},
+			"MixinFeedBranch": { // Add MixinFeedBranch variable
+				Description: "Branch in the mixin feed repository to commit to",
+				Type:        "string",
+				Default:     "main",
+				Required:    false,
+			},
+			"AuthorEmail": { // Add AuthorEmail variable
+				Description: "Author's email for security contact",
+				Type:        "string",
+				Required:    false, // Optional, defaults in security.txt
+			},
+		},
+		// "validate" is the declarative replacement for createMixin's old
+		// hardcoded go mod tidy/build/test sequence: ContinueOnError on
+		// every step reproduces that sequence's "warn but keep going"
+		// behavior, so a template.json that doesn't define its own
+		// "validate" hooks still gets it for free.
+		Hooks: map[string][]HookStep{
+			"validate": {
+				{Name: "go-mod-tidy", Command: "go mod tidy", ContinueOnError: true},
+				{Name: "go-build", Command: "go build ./...", ContinueOnError: true},
+				{Name: "go-test", Command: "go test ./...", ContinueOnError: true},
+			},
+		},
+	}
 }
 
-// RunHooks executes the hooks defined in the template configuration, substituting variables
+// RunHooks executes the hooks defined in the template configuration,
+// substituting variables. Each HookStep runs on the host (subject to
+// DefaultAllowedHookCommands) unless it names an "image", in which case it
+// runs in that container via config.HooksRuntime (docker by default).
+//
+// It is a thin wrapper around RunHooksWithOptions with default options
+// (accumulate all failures rather than stopping at the first one); callers
+// that need --fail-fast or a JSON summary should call that directly.
 func RunHooks(config *TemplateConfig, hookName string, outputDir string, data map[string]interface{}) error {
+	_, err := RunHooksWithOptions(config, hookName, outputDir, data, HookRunOptions{})
+	return err
+}
+
+// HookRunOptions controls RunHooksWithOptions' failure handling and
+// reporting.
+type HookRunOptions struct {
+	// FailFast stops the chain at the first hook failure, restoring the
+	// original fail-fast behavior. A HookStep with ContinueOnError set is
+	// run past regardless.
+	FailFast bool
+	// JSONSummary writes a JSON summary of every hook's HookResult to
+	// stderr once the chain finishes, for CI consumers.
+	JSONSummary bool
+	// Skip names steps (by HookStep.Name) to mark "skipped" instead of
+	// running, the --skip-hooks selector. Ignored for a step with no Name,
+	// since there's nothing to match it against.
+	Skip []string
+	// Only, if non-empty, runs just the named steps and marks every other
+	// step "skipped" -- the --only-hooks selector. Takes priority over
+	// Skip when both are set.
+	Only []string
+}
+
+// HookResult records one hook's outcome for --hooks-json-summary and the
+// combined error RunHooksWithOptions returns.
+type HookResult struct {
+	Index      int    `json:"index"`
+	Command    string `json:"command"`
+	Status     string `json:"status"` // "ok", "failed" or "skipped"
+	ExitCode   int    `json:"exit_code"`
+	StderrTail string `json:"stderr_tail,omitempty"`
+}
+
+// RunHooksWithOptions is RunHooks with control over fail-fast behavior and
+// JSON summary output. Every hook runs (in order) even after a failure,
+// unless opts.FailFast is set; a HookStep with ContinueOnError never halts
+// the chain or contributes to the combined error, with or without
+// FailFast. The combined error wraps every non-ContinueOnError failure via
+// multierr, preserving each hook's index, command and exit code.
+func RunHooksWithOptions(config *TemplateConfig, hookName string, outputDir string, data map[string]interface{}, opts HookRunOptions) ([]HookResult, error) {
 	hooks, exists := config.Hooks[hookName]
 	if !exists || len(hooks) == 0 {
-		return nil // No hooks for this stage
+		return nil, nil // No hooks for this stage
 	}
 
 	fmt.Printf("Running %s hooks...\n", hookName)
-	for _, commandTmplStr := range hooks {
-		// Process command string as a template
-		commandTmpl, err := template.New("hook-cmd").Parse(commandTmplStr)
-		if err != nil {
-			// If parsing fails, treat it as a literal command for backward compatibility? Or error out?
-			// Let's error out for now to encourage proper templating.
-			return fmt.Errorf("failed to parse hook command template '%s': %w", commandTmplStr, err)
+	results := make([]HookResult, 0, len(hooks))
+	var combined error
+	for i, step := range hooks {
+		if !hookSelected(step, opts) {
+			results = append(results, HookResult{Index: i, Status: "skipped"})
+			continue
 		}
-
-		var commandBuf bytes.Buffer // Need to import "bytes"
-		if err := commandTmpl.Execute(&commandBuf, data); err != nil {
-			return fmt.Errorf("failed to execute hook command template '%s': %w", commandTmplStr, err)
+		if step.When != "" {
+			keep, err := evaluateHookWhen(step.When, data)
+			if err != nil {
+				return results, err
+			}
+			if !keep {
+				results = append(results, HookResult{Index: i, Status: "skipped"})
+				continue
+			}
 		}
-		processedCommand := commandBuf.String()
 
-		// Split command into executable and args (basic split, might need refinement for complex cases)
-		parts := strings.Fields(processedCommand)
-		if len(parts) == 0 {
+		inv, err := renderHookInvocation(step, data)
+		if err != nil {
+			return results, err
+		}
+		display := inv.Command
+		if display == "" {
+			display = strings.Join(inv.Argv, " ")
+		}
+		if display == "" {
+			results = append(results, HookResult{Index: i, Status: "skipped"})
 			continue // Skip empty commands
 		}
-		executable := parts[0]
-		args := parts[1:]
 
-		fmt.Printf("  Executing: %s\n", processedCommand)
-		cmd := createCommand(executable, args...) // Use the helper function
-		cmd.Dir = outputDir                       // Run in the generated directory
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		ctx := context.Background()
+		cancel := func() {}
+		if step.Timeout != "" {
+			d, err := time.ParseDuration(step.Timeout)
+			if err != nil {
+				return results, fmt.Errorf("hook %d (%q): invalid timeout %q: %w", i, display, step.Timeout, err)
+			}
+			ctx, cancel = context.WithTimeout(ctx, d)
+		}
+
+		executor := selectHookExecutor(config, step)
+		fmt.Printf("  Executing: %s\n", display)
+		exitCode, stderrTail, runErr := executor.Run(ctx, step, inv, outputDir)
+		cancel()
+		result := HookResult{Index: i, Command: display, ExitCode: exitCode, StderrTail: stderrTail}
 
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("hook '%s' failed: %w", processedCommand, err)
+		if runErr != nil {
+			result.Status = "failed"
+			results = append(results, result)
+			if !step.ContinueOnError {
+				combined = multierr.Append(combined, fmt.Errorf("hook %d (%q) failed: %w", i, display, runErr))
+				if opts.FailFast {
+					break
+				}
+			}
+			continue
 		}
+		result.Status = "ok"
+		results = append(results, result)
 	}
 	fmt.Printf("%s hooks completed.\n", hookName)
 
+	if opts.JSONSummary {
+		emitHookJSONSummary(hookName, results)
+	}
+
+	return results, combined
+}
+
+// emitHookJSONSummary writes results as JSON to stderr, for CI consumers
+// that want a machine-readable account of a hook stage regardless of
+// whether it succeeded.
+func emitHookJSONSummary(hookName string, results []HookResult) {
+	summary := struct {
+		Hook    string       `json:"hook"`
+		Results []HookResult `json:"results"`
+	}{Hook: hookName, Results: results}
+
+	encoded, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode hook summary: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(encoded))
+}
+
+// renderHookInvocation renders step's command into the form its
+// HookExecutor runs: step.Cmd (argv, one template per element, no shell
+// involved) if set, otherwise the legacy step.Command template string.
+func renderHookInvocation(step HookStep, data map[string]interface{}) (hookInvocation, error) {
+	if len(step.Cmd) > 0 {
+		argv, err := renderHookArgs(step.Cmd, data)
+		if err != nil {
+			return hookInvocation{}, err
+		}
+		return hookInvocation{Argv: argv}, nil
+	}
+	command, err := renderHookCommand(step.Command, data)
+	if err != nil {
+		return hookInvocation{}, err
+	}
+	return hookInvocation{Command: command}, nil
+}
+
+// renderHookArgs renders each of args as its own Go template against data,
+// the argv-form counterpart to renderHookCommand.
+func renderHookArgs(args []string, data map[string]interface{}) ([]string, error) {
+	rendered := make([]string, len(args))
+	for i, arg := range args {
+		out, err := renderHookCommand(arg, data)
+		if err != nil {
+			return nil, fmt.Errorf("hook cmd arg %d: %w", i, err)
+		}
+		rendered[i] = out
+	}
+	return rendered, nil
+}
+
+// hookSelected reports whether step should run given opts.Skip/opts.Only --
+// a step with no Name can't be matched by either and always runs. Only, if
+// set, wins over Skip.
+func hookSelected(step HookStep, opts HookRunOptions) bool {
+	if step.Name == "" {
+		return true
+	}
+	if len(opts.Only) > 0 {
+		for _, name := range opts.Only {
+			if name == step.Name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range opts.Skip {
+		if name == step.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateHookWhen renders whenExpr (a Go template, e.g. "{{ .EnableCI }}")
+// against data and reports whether the result is truthy -- non-empty and
+// not "false" or "0".
+func evaluateHookWhen(whenExpr string, data map[string]interface{}) (bool, error) {
+	rendered, err := renderHookCommand(whenExpr, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate hook \"when\" %q: %w", whenExpr, err)
+	}
+	rendered = strings.TrimSpace(rendered)
+	return rendered != "" && rendered != "false" && rendered != "0", nil
+}
+
+// envVarName is the environment variable resolveVariable checks for
+// varName, e.g. "MixinName" -> "SKELETOR_VAR_MIXINNAME" -- a layer between
+// --values-file and --var so a CI pipeline can export variables instead of
+// repeating --var flags or piping a values file in.
+func envVarName(varName string) string {
+	return "SKELETOR_VAR_" + strings.ToUpper(varName)
+}
+
+// variableSource is what resolveVariable found for one variable, or the
+// zero value if none of its sources had it.
+type variableSource struct {
+	Found bool
+	// Raw holds an untyped string value (from --var or a SKELETOR_VAR_
+	// environment variable) that still needs coerceToSchema. Typed holds an
+	// already-typed value (from --values-file); IsTyped distinguishes it
+	// from the zero value of Raw being the found value.
+	Raw     string
+	Typed   interface{}
+	IsTyped bool
+	// Loc is where the value came from, for the error NewConfigError wraps
+	// a coercion/validation failure in.
+	Loc Location
+}
+
+// parseRawVars splits each extraVars entry (a --var KEY=VALUE string) into
+// rawVars, keyed by variable name, and rawVarIndex, its position in
+// extraVars for resolveVariable's error-location reporting. A duplicate
+// KEY overwrites its earlier entry, same as a map literal would.
+func parseRawVars(extraVars []string) (map[string]string, map[string]int, error) {
+	rawVars := make(map[string]string, len(extraVars))
+	rawVarIndex := make(map[string]int, len(extraVars))
+	for i, varDef := range extraVars {
+		parts := strings.SplitN(varDef, "=", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("invalid variable format: %s (expected KEY=VALUE)", varDef)
+		}
+		rawVars[parts[0]] = parts[1]
+		rawVarIndex[parts[0]] = i
+	}
+	return rawVars, rawVarIndex, nil
+}
+
+// resolveVariable walks varName's value sources in the precedence
+// buildTemplateData uses for a variable that isn't one of the dedicated
+// name/author/modulePath CLI flags: --var outranks a SKELETOR_VAR_<NAME>
+// environment variable, which outranks a --values-file entry. Each source
+// is checked in full before falling through to the next, so a variable set
+// in a values file but overridden by --var uses the --var value alone
+// rather than merging the two.
+func resolveVariable(varName string, rawVars map[string]string, rawVarIndex map[string]int, extraVars []string, values map[string]interface{}) variableSource {
+	if raw, ok := rawVars[varName]; ok {
+		idx := rawVarIndex[varName]
+		return variableSource{Found: true, Raw: raw, Loc: Location{File: fmt.Sprintf("--var[%d]:%s", idx, extraVars[idx])}}
+	}
+	if raw, ok := os.LookupEnv(envVarName(varName)); ok {
+		return variableSource{Found: true, Raw: raw, Loc: Location{File: envVarName(varName)}}
+	}
+	if value, ok := values[varName]; ok {
+		return variableSource{Found: true, Typed: value, IsTyped: true, Loc: Location{File: "--values-file"}}
+	}
+	return variableSource{}
+}
+
+// variableSkipped reports whether varConfig's OnlyIf/SkipIf gate this
+// variable out of data against the variables collected so far, reusing
+// evaluateHookWhen's truthy rules. OnlyIf wins if both are somehow set.
+func variableSkipped(varConfig Variable, data map[string]interface{}) (bool, error) {
+	if varConfig.OnlyIf != "" {
+		keep, err := evaluateHookWhen(varConfig.OnlyIf, data)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate only_if: %w", err)
+		}
+		return !keep, nil
+	}
+	if varConfig.SkipIf != "" {
+		skip, err := evaluateHookWhen(varConfig.SkipIf, data)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate skip_if: %w", err)
+		}
+		return skip, nil
+	}
+	return false, nil
+}
+
+// validateVariableExtra runs validation's Regex/MinLength/MaxLength/Min/Max/
+// Expr checks against value, in addition to whatever validateAgainstSchema
+// already checked. data is the variables resolved so far (variables are
+// walked in sorted name order, so an earlier-sorting variable referenced by
+// Expr is reliably present); Expr sees value itself under varName -- enough for Expr to compare this variable to another (e.g.
+// "{{ eq .Password .PasswordConfirm }}").
+func validateVariableExtra(varName string, validation *VariableValidation, value interface{}, data map[string]interface{}) error {
+	if validation == nil {
+		return nil
+	}
+
+	if validation.Regex != "" {
+		if str, ok := value.(string); ok {
+			matched, err := regexp.MatchString(validation.Regex, str)
+			if err != nil {
+				return fmt.Errorf("variable %s: invalid validation regex %q: %w", varName, validation.Regex, err)
+			}
+			if !matched {
+				return fmt.Errorf("variable %s: value %q does not match required pattern %q", varName, str, validation.Regex)
+			}
+		}
+	}
+
+	if num, ok := schemaNumericValue(value); ok {
+		if validation.Min != nil && num < *validation.Min {
+			return fmt.Errorf("variable %s: value %v is below minimum %v", varName, value, *validation.Min)
+		}
+		if validation.Max != nil && num > *validation.Max {
+			return fmt.Errorf("variable %s: value %v exceeds maximum %v", varName, value, *validation.Max)
+		}
+	}
+
+	if length, ok := schemaLength(value); ok {
+		if validation.MinLength != nil && length < *validation.MinLength {
+			return fmt.Errorf("variable %s: length %d is below minLength %d", varName, length, *validation.MinLength)
+		}
+		if validation.MaxLength != nil && length > *validation.MaxLength {
+			return fmt.Errorf("variable %s: length %d exceeds maxLength %d", varName, length, *validation.MaxLength)
+		}
+	}
+
+	if validation.Expr != "" {
+		exprData := make(map[string]interface{}, len(data)+1)
+		for k, v := range data {
+			exprData[k] = v
+		}
+		exprData[varName] = value
+		keep, err := evaluateHookWhen(validation.Expr, exprData)
+		if err != nil {
+			return fmt.Errorf("variable %s: failed to evaluate validation expr: %w", varName, err)
+		}
+		if !keep {
+			message := validation.Message
+			if message == "" {
+				message = fmt.Sprintf("failed validation expr %q", validation.Expr)
+			}
+			return fmt.Errorf("variable %s: %s", varName, message)
+		}
+	}
+
 	return nil
 }
 
-// Allowed commands for hooks
-var allowedHookCommands = map[string]bool{
-	"go":  true,
-	"git": true,
-	// Add other safe commands here if needed, e.g., "echo", "mage"
+// renderHookCommand executes commandTmplStr as a Go template against data.
+func renderHookCommand(commandTmplStr string, data map[string]interface{}) (string, error) {
+	commandTmpl, err := template.New("hook-cmd").Parse(commandTmplStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse hook command template '%s': %w", commandTmplStr, err)
+	}
+
+	var commandBuf bytes.Buffer
+	if err := commandTmpl.Execute(&commandBuf, data); err != nil {
+		return "", fmt.Errorf("failed to execute hook command template '%s': %w", commandTmplStr, err)
+	}
+	return commandBuf.String(), nil
+}
+
+// DefaultAllowedHookCommands is the allow-list the host hook executor
+// consults when a HookStep doesn't pin a container image. Templates that
+// need another executable can extend it at init time, e.g.
+// DefaultAllowedHookCommands["mage"] = true.
+var DefaultAllowedHookCommands = map[string]bool{
+	"go":     true,
+	"git":    true,
+	"oras":   true,
+	"cosign": true,
 }
 
 // Helper function to create OS-specific commands, checking against an allow-list
 func createCommand(name string, args ...string) *exec.Cmd {
 	// Check if the command is allowed
-	if !allowedHookCommands[name] {
+	if !DefaultAllowedHookCommands[name] {
 		// Return a command that will error out immediately
 		// This prevents execution of arbitrary commands from templates
 		// We use "false" which is a standard shell utility that just exits with status 1
@@ -244,18 +873,50 @@ func (ft *FeatureToggles) IsFeatureEnabled(category, feature string) bool {
 		return false
 	}
 
-	switch category {
-	case "security":
-		return ft.isSecurityFeatureEnabled(feature)
-	case "compliance":
-		return ft.isComplianceFeatureEnabled(feature)
-	case "auth":
-		return ft.isAuthFeatureEnabled(feature)
-	case "observability":
-		return ft.isObservabilityFeatureEnabled(feature)
-	default:
+	provider, ok := defaultFeatureRegistry.Lookup(category)
+	if !ok {
 		return false
 	}
+	return provider.IsEnabled(ft, feature)
+}
+
+// IsFeatureEnabledStrict is the FeatureGate-backed counterpart to
+// IsFeatureEnabled: it returns the registry's "unrecognized feature gate"
+// error for an unknown category/feature pair instead of silently reporting
+// false, and treats the gate as an additional kill-switch on top of this
+// FeatureToggles config — a feature is only enabled if both the gate and the
+// toggle agree.
+func (ft *FeatureToggles) IsFeatureEnabledStrict(category, feature string) (bool, error) {
+	gateEnabled, err := DefaultFeatureGate.Enabled(category + "." + feature)
+	if err != nil {
+		return false, err
+	}
+	if !gateEnabled {
+		return false, nil
+	}
+	return ft.IsFeatureEnabled(category, feature), nil
+}
+
+// GetEnforcement returns the Action(s) configured for category.feature at
+// scope, e.g. GetEnforcement("security", "input_validation", ScopeValidate).
+// It returns nil if the feature has no enforcement entry or none of its
+// ScopedActions match scope -- callers should treat a nil result as "no
+// opinion" and fall back to their own default (typically IsFeatureEnabled).
+func (ft *FeatureToggles) GetEnforcement(category, feature string, scope EnforcementScope) []Action {
+	if ft == nil || ft.Enforcement == nil {
+		return nil
+	}
+	entry, ok := ft.Enforcement[category][feature]
+	if !ok {
+		return nil
+	}
+	var actions []Action
+	for _, scoped := range entry.Enforcement {
+		if scoped.Scope == scope {
+			actions = append(actions, scoped.Action)
+		}
+	}
+	return actions
 }
 
 // isSecurityFeatureEnabled checks if a security feature is enabled
@@ -362,127 +1023,163 @@ func (ft *FeatureToggles) isObservabilityFeatureEnabled(feature string) bool {
 	}
 }
 
-// GetEnabledFeatures returns a map of all enabled features organized by category
-func (ft *FeatureToggles) GetEnabledFeatures() map[string][]string {
-	enabled := make(map[string][]string)
-
-	if ft == nil {
-		return enabled
+// listSecurityFeatures returns every enabled security feature name.
+func (ft *FeatureToggles) listSecurityFeatures() []string {
+	if ft.Security == nil || !ft.Security.Enabled {
+		return nil
+	}
+	var features []string
+	if ft.Security.InputValidation {
+		features = append(features, "input_validation")
+	}
+	if ft.Security.RateLimiting {
+		features = append(features, "rate_limiting")
+	}
+	if ft.Security.SecureHeaders {
+		features = append(features, "secure_headers")
+	}
+	if ft.Security.VulnerabilityScanning {
+		features = append(features, "vulnerability_scanning")
+	}
+	if ft.Security.PolicyEnforcement {
+		features = append(features, "policy_enforcement")
 	}
+	return features
+}
 
-	// Security features
-	if ft.Security != nil && ft.Security.Enabled {
-		var securityFeatures []string
-		if ft.Security.InputValidation {
-			securityFeatures = append(securityFeatures, "input_validation")
-		}
-		if ft.Security.RateLimiting {
-			securityFeatures = append(securityFeatures, "rate_limiting")
-		}
-		if ft.Security.SecureHeaders {
-			securityFeatures = append(securityFeatures, "secure_headers")
-		}
-		if ft.Security.VulnerabilityScanning {
-			securityFeatures = append(securityFeatures, "vulnerability_scanning")
-		}
-		if ft.Security.PolicyEnforcement {
-			securityFeatures = append(securityFeatures, "policy_enforcement")
-		}
-		if len(securityFeatures) > 0 {
-			enabled["security"] = securityFeatures
+// listComplianceFeatures returns every enabled compliance feature name,
+// including custom entries.
+func (ft *FeatureToggles) listComplianceFeatures() []string {
+	if ft.Compliance == nil || !ft.Compliance.Enabled {
+		return nil
+	}
+	var features []string
+	if ft.Compliance.SOC2 {
+		features = append(features, "soc2")
+	}
+	if ft.Compliance.GDPR {
+		features = append(features, "gdpr")
+	}
+	if ft.Compliance.HIPAA {
+		features = append(features, "hipaa")
+	}
+	if ft.Compliance.PCIDSS {
+		features = append(features, "pci_dss")
+	}
+	for feature, enabled := range ft.Compliance.Custom {
+		if enabled {
+			features = append(features, feature)
 		}
 	}
+	return features
+}
 
-	// Compliance features
-	if ft.Compliance != nil && ft.Compliance.Enabled {
-		var complianceFeatures []string
-		if ft.Compliance.SOC2 {
-			complianceFeatures = append(complianceFeatures, "soc2")
-		}
-		if ft.Compliance.GDPR {
-			complianceFeatures = append(complianceFeatures, "gdpr")
-		}
-		if ft.Compliance.HIPAA {
-			complianceFeatures = append(complianceFeatures, "hipaa")
-		}
-		if ft.Compliance.PCIDSS {
-			complianceFeatures = append(complianceFeatures, "pci_dss")
-		}
-		// Add custom compliance features
-		for feature, enabled := range ft.Compliance.Custom {
-			if enabled {
-				complianceFeatures = append(complianceFeatures, feature)
-			}
-		}
-		if len(complianceFeatures) > 0 {
-			enabled["compliance"] = complianceFeatures
+// listAuthFeatures returns every enabled auth feature name, including
+// integration-specific entries.
+func (ft *FeatureToggles) listAuthFeatures() []string {
+	if ft.Auth == nil || !ft.Auth.Enabled {
+		return nil
+	}
+	var features []string
+	if ft.Auth.RBAC {
+		features = append(features, "rbac")
+	}
+	if ft.Auth.LDAP {
+		features = append(features, "ldap")
+	}
+	if ft.Auth.SSO {
+		features = append(features, "sso")
+	}
+	if ft.Auth.MFA {
+		features = append(features, "mfa")
+	}
+	if ft.Auth.Vault {
+		features = append(features, "vault")
+	}
+	if ft.Auth.SessionMgmt {
+		features = append(features, "session_management")
+	}
+	for feature, enabled := range ft.Auth.Integrations {
+		if enabled {
+			features = append(features, feature)
 		}
 	}
+	return features
+}
 
-	// Auth features
-	if ft.Auth != nil && ft.Auth.Enabled {
-		var authFeatures []string
-		if ft.Auth.RBAC {
-			authFeatures = append(authFeatures, "rbac")
-		}
-		if ft.Auth.LDAP {
-			authFeatures = append(authFeatures, "ldap")
-		}
-		if ft.Auth.SSO {
-			authFeatures = append(authFeatures, "sso")
-		}
-		if ft.Auth.MFA {
-			authFeatures = append(authFeatures, "mfa")
-		}
-		if ft.Auth.Vault {
-			authFeatures = append(authFeatures, "vault")
-		}
-		if ft.Auth.SessionMgmt {
-			authFeatures = append(authFeatures, "session_management")
-		}
-		// Add integration-specific features
-		for feature, enabled := range ft.Auth.Integrations {
-			if enabled {
-				authFeatures = append(authFeatures, feature)
-			}
-		}
-		if len(authFeatures) > 0 {
-			enabled["auth"] = authFeatures
+// listObservabilityFeatures returns every enabled observability feature
+// name, including backend-specific entries.
+func (ft *FeatureToggles) listObservabilityFeatures() []string {
+	if ft.Observability == nil || !ft.Observability.Enabled {
+		return nil
+	}
+	var features []string
+	if ft.Observability.APM {
+		features = append(features, "apm")
+	}
+	if ft.Observability.Infrastructure {
+		features = append(features, "infrastructure")
+	}
+	if ft.Observability.CustomMetrics {
+		features = append(features, "custom_metrics")
+	}
+	if ft.Observability.HealthChecks {
+		features = append(features, "health_checks")
+	}
+	if ft.Observability.OpenTelemetry {
+		features = append(features, "opentelemetry")
+	}
+	if ft.Observability.AuditLogging {
+		features = append(features, "audit_logging")
+	}
+	if ft.Observability.Tracing {
+		features = append(features, "tracing")
+	}
+	for feature, enabled := range ft.Observability.Backends {
+		if enabled {
+			features = append(features, feature)
 		}
 	}
+	return features
+}
 
-	// Observability features
-	if ft.Observability != nil && ft.Observability.Enabled {
-		var obsFeatures []string
-		if ft.Observability.APM {
-			obsFeatures = append(obsFeatures, "apm")
-		}
-		if ft.Observability.Infrastructure {
-			obsFeatures = append(obsFeatures, "infrastructure")
-		}
-		if ft.Observability.CustomMetrics {
-			obsFeatures = append(obsFeatures, "custom_metrics")
-		}
-		if ft.Observability.HealthChecks {
-			obsFeatures = append(obsFeatures, "health_checks")
-		}
-		if ft.Observability.OpenTelemetry {
-			obsFeatures = append(obsFeatures, "opentelemetry")
-		}
-		if ft.Observability.AuditLogging {
-			obsFeatures = append(obsFeatures, "audit_logging")
-		}
-		if ft.Observability.Tracing {
-			obsFeatures = append(obsFeatures, "tracing")
+// GetEnabledFeatures returns a map of all enabled features organized by
+// category. The four built-in categories additionally run their
+// PolicyExpr (if any) through evaluateCategoryPolicy; categories
+// registered with RegisterFeatureProvider beyond those four are listed via
+// their provider's ListEnabled with no policy gating of their own.
+func (ft *FeatureToggles) GetEnabledFeatures() map[string][]string {
+	enabled := make(map[string][]string)
+
+	if ft == nil {
+		return enabled
+	}
+	ft.decisions = nil
+
+	if features := ft.listSecurityFeatures(); len(features) > 0 && ft.evaluateCategoryPolicy("security", ft.Security.PolicyExpr, features) {
+		enabled["security"] = features
+	}
+	if features := ft.listComplianceFeatures(); len(features) > 0 && ft.evaluateCategoryPolicy("compliance", ft.Compliance.PolicyExpr, features) {
+		enabled["compliance"] = features
+	}
+	if features := ft.listAuthFeatures(); len(features) > 0 && ft.evaluateCategoryPolicy("auth", ft.Auth.PolicyExpr, features) {
+		enabled["auth"] = features
+	}
+	if features := ft.listObservabilityFeatures(); len(features) > 0 && ft.evaluateCategoryPolicy("observability", ft.Observability.PolicyExpr, features) {
+		enabled["observability"] = features
+	}
+
+	for _, category := range defaultFeatureRegistry.Categories() {
+		if _, builtin := enabled[category]; builtin {
+			continue
 		}
-		// Add backend-specific features
-		for feature, enabled := range ft.Observability.Backends {
-			if enabled {
-				obsFeatures = append(obsFeatures, feature)
-			}
+		switch category {
+		case "security", "compliance", "auth", "observability":
+			continue
 		}
-		if len(obsFeatures) > 0 {
-			enabled["observability"] = obsFeatures
+		provider, _ := defaultFeatureRegistry.Lookup(category)
+		if features := provider.ListEnabled(ft); len(features) > 0 {
+			enabled[category] = features
 		}
 	}
 
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v3"
+)
+
+// SourceFormat identifies how to parse a Source's bytes.
+type SourceFormat string
+
+const (
+	FormatJSON SourceFormat = "json"
+	FormatHCL  SourceFormat = "hcl"
+	FormatYAML SourceFormat = "yaml"
+	FormatTOML SourceFormat = "toml"
+)
+
+// formatsByExt maps a file extension to the SourceFormat used to infer a
+// Source's Format when it isn't set explicitly.
+var formatsByExt = map[string]SourceFormat{
+	".json": FormatJSON,
+	".hcl":  FormatHCL,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".toml": FormatTOML,
+}
+
+// Source is one layer of a LoadOpts config stack: either a file on disk
+// (Path) or an inline snippet (Literal), e.g. a CLI --config-override value.
+// Format is inferred from Path's extension when left empty.
+type Source struct {
+	Path    string
+	Literal []byte
+	Format  SourceFormat
+}
+
+// decode reads and parses the source, returning its contents as a generic
+// tree suitable for deep-merging. A Path that does not exist is not an
+// error: it produces a warning so callers can skip optional layers (e.g. an
+// org-wide defaults file that hasn't been set up yet).
+func (s Source) decode() (tree map[string]interface{}, warning string, err error) {
+	data := s.Literal
+	if s.Path != "" {
+		data, err = os.ReadFile(s.Path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Sprintf("config source %s not found, skipping", s.Path), nil
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read config source %s: %w", s.Path, err)
+		}
+	}
+
+	format := s.Format
+	if format == "" {
+		format = formatsByExt[strings.ToLower(filepath.Ext(s.Path))]
+	}
+	if format == "" {
+		return nil, "", fmt.Errorf("config source %s: could not infer format, set Source.Format explicitly", s.Path)
+	}
+
+	tree = make(map[string]interface{})
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return nil, "", fmt.Errorf("config source %s: invalid JSON: %w", s.Path, err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, "", fmt.Errorf("config source %s: invalid YAML: %w", s.Path, err)
+		}
+	case FormatHCL:
+		if err := hcl.Unmarshal(data, &tree); err != nil {
+			return nil, "", fmt.Errorf("config source %s: invalid HCL: %w", s.Path, err)
+		}
+	case FormatTOML:
+		if _, err := toml.Decode(string(data), &tree); err != nil {
+			return nil, "", fmt.Errorf("config source %s: invalid TOML: %w", s.Path, err)
+		}
+	default:
+		return nil, "", fmt.Errorf("config source %s: unsupported format %q", s.Path, format)
+	}
+	return tree, "", nil
+}
+
+// LoadOpts is a Consul-style layered config builder: DefaultConfig seeds the
+// merge, then each entry in Sources and finally each entry in Overrides is
+// deep-merged on top, in order, so later entries win. A typical stack is an
+// org-wide defaults file, a per-repo template.hcl, and CLI --config-override
+// snippets, without hand-editing template.json.
+type LoadOpts struct {
+	DefaultConfig *TemplateConfig
+	Sources       []Source
+	Overrides     []Source
+}
+
+// LoadResult is the deep-merged TemplateConfig plus any non-fatal Warnings
+// collected while loading, such as an optional source that was skipped
+// because it doesn't exist.
+type LoadResult struct {
+	Config   *TemplateConfig
+	Warnings []string
+}
+
+// LoadLayeredConfig deep-merges opts.DefaultConfig with opts.Sources and then
+// opts.Overrides, in order, so later layers win: scalars are replaced,
+// slices append, and FeatureToggles booleans are OR-merged so enabling a
+// feature in any layer enables it everywhere.
+func LoadLayeredConfig(opts LoadOpts) (*LoadResult, error) {
+	merged := make(map[string]interface{})
+	if opts.DefaultConfig != nil {
+		defaultTree, err := configToTree(opts.DefaultConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize default config: %w", err)
+		}
+		merged = defaultTree
+	}
+
+	result := &LoadResult{}
+	layers := make([]Source, 0, len(opts.Sources)+len(opts.Overrides))
+	layers = append(layers, opts.Sources...)
+	layers = append(layers, opts.Overrides...)
+
+	for _, src := range layers {
+		tree, warning, err := src.decode()
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+			continue
+		}
+		mergeTrees(merged, tree, false)
+	}
+
+	config, err := treeToConfig(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode merged config: %w", err)
+	}
+	result.Config = config
+	return result, nil
+}
+
+// mergeTrees merges src into dst in place. Maps recurse, slices append, and
+// every other value is replaced by src's (later wins) -- except booleans
+// once inside the feature_toggles subtree, which are OR-merged so a feature
+// enabled by any layer stays enabled.
+func mergeTrees(dst, src map[string]interface{}, orBooleans bool) {
+	for key, srcVal := range src {
+		dstVal, exists := dst[key]
+		if !exists {
+			dst[key] = srcVal
+			continue
+		}
+
+		nextOrBooleans := orBooleans || key == "feature_toggles"
+		switch srcTyped := srcVal.(type) {
+		case map[string]interface{}:
+			if dstTyped, ok := dstVal.(map[string]interface{}); ok {
+				mergeTrees(dstTyped, srcTyped, nextOrBooleans)
+				continue
+			}
+			dst[key] = srcVal
+		case []interface{}:
+			if dstTyped, ok := dstVal.([]interface{}); ok {
+				dst[key] = append(dstTyped, srcTyped...)
+				continue
+			}
+			dst[key] = srcVal
+		case bool:
+			if orBooleans {
+				if dstBool, ok := dstVal.(bool); ok {
+					dst[key] = dstBool || srcTyped
+					continue
+				}
+			}
+			dst[key] = srcVal
+		default:
+			dst[key] = srcVal
+		}
+	}
+}
+
+// configToTree and treeToConfig round-trip a TemplateConfig through JSON to
+// get a generic map[string]interface{} tree that mergeTrees can operate on,
+// regardless of which format (json/hcl/yaml) a layer was decoded from.
+func configToTree(config *TemplateConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	tree := make(map[string]interface{})
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// parseConfigFileSources turns --config-source file paths into Sources,
+// inferring format from each path's extension.
+func parseConfigFileSources(paths []string) []Source {
+	sources := make([]Source, 0, len(paths))
+	for _, path := range paths {
+		sources = append(sources, Source{Path: path})
+	}
+	return sources
+}
+
+// parseConfigOverrideSources turns --config-override values into literal
+// Sources. Since an inline snippet has no file extension to infer a format
+// from, each value must be prefixed with its format: "json:{...}",
+// "yaml:...", or "hcl:...".
+func parseConfigOverrideSources(values []string) []Source {
+	sources := make([]Source, 0, len(values))
+	for _, value := range values {
+		format, content, ok := strings.Cut(value, ":")
+		if !ok {
+			format = string(FormatJSON)
+			content = value
+		}
+		sources = append(sources, Source{Literal: []byte(content), Format: SourceFormat(format)})
+	}
+	return sources
+}
+
+func treeToConfig(tree map[string]interface{}) (*TemplateConfig, error) {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	var config TemplateConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
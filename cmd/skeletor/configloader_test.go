@@ -0,0 +1,175 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLayeredConfig_ScalarsLaterWins(t *testing.T) {
+	dir := t.TempDir()
+	orgDefaults := filepath.Join(dir, "org-defaults.json")
+	require.NoError(t, os.WriteFile(orgDefaults, []byte(`{"name":"org-default-name"}`), 0o644))
+
+	result, err := LoadLayeredConfig(LoadOpts{
+		DefaultConfig: &TemplateConfig{Name: "builtin-name"},
+		Sources:       []Source{{Path: orgDefaults}},
+		Overrides:     []Source{{Literal: []byte(`{"name":"cli-name"}`), Format: FormatJSON}},
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, "cli-name", result.Config.Name)
+}
+
+func TestLoadLayeredConfig_MissingSourceWarns(t *testing.T) {
+	result, err := LoadLayeredConfig(LoadOpts{
+		DefaultConfig: &TemplateConfig{Name: "builtin-name"},
+		Sources:       []Source{{Path: "/no/such/template.hcl"}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "not found, skipping")
+	assert.Equal(t, "builtin-name", result.Config.Name)
+}
+
+func TestLoadLayeredConfig_SlicesAppend(t *testing.T) {
+	result, err := LoadLayeredConfig(LoadOpts{
+		DefaultConfig: &TemplateConfig{Ignore: []string{"*.tmp"}},
+		Overrides:     []Source{{Literal: []byte(`{"ignore":["*.log"]}`), Format: FormatJSON}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.tmp", "*.log"}, result.Config.Ignore)
+}
+
+func TestLoadLayeredConfig_FeatureTogglesOrMerged(t *testing.T) {
+	result, err := LoadLayeredConfig(LoadOpts{
+		DefaultConfig: &TemplateConfig{
+			FeatureToggles: &FeatureToggles{
+				Security: &SecurityFeatures{Enabled: true, InputValidation: true},
+			},
+		},
+		Overrides: []Source{{
+			Literal: []byte(`{"feature_toggles":{"security":{"rate_limiting":true}}}`),
+			Format:  FormatJSON,
+		}},
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Config.FeatureToggles.Security)
+	assert.True(t, result.Config.FeatureToggles.Security.InputValidation, "earlier layer's true should survive the OR-merge")
+	assert.True(t, result.Config.FeatureToggles.Security.RateLimiting, "later layer's true should be picked up")
+}
+
+func TestLoadLayeredConfig_YAMLAndHCLSources(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "defaults.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("description: from-yaml\n"), 0o644))
+	hclPath := filepath.Join(dir, "template.hcl")
+	require.NoError(t, os.WriteFile(hclPath, []byte(`name = "from-hcl"`), 0o644))
+
+	result, err := LoadLayeredConfig(LoadOpts{
+		DefaultConfig: &TemplateConfig{Name: "builtin-name", Description: "builtin-description"},
+		Sources:       []Source{{Path: yamlPath}, {Path: hclPath}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-hcl", result.Config.Name)
+	assert.Equal(t, "from-yaml", result.Config.Description)
+}
+
+func TestSource_Decode_UnknownFormat(t *testing.T) {
+	_, _, err := Source{Literal: []byte("{}")}.decode()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not infer format")
+}
+
+func TestParseConfigOverrideSources(t *testing.T) {
+	sources := parseConfigOverrideSources([]string{`json:{"name":"x"}`, `yaml:name: y`})
+	require.Len(t, sources, 2)
+	assert.Equal(t, FormatJSON, sources[0].Format)
+	assert.Equal(t, FormatYAML, sources[1].Format)
+}
+
+func TestLoadTemplateConfig_TOMLAndYAML(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		filename string
+		data     string
+	}{
+		{
+			name:     "TOML",
+			filename: "template.toml",
+			data: `
+name = "TOML Template"
+ignore = ["*.tmp"]
+
+[variables.MixinName]
+description = "Name of the mixin"
+type = "string"
+required = true
+
+[conditional_paths]
+".golangci.yml" = { when = "feature(\"security.input_validation\")" }
+
+[hooks]
+post_gen = ["go mod tidy"]
+`,
+		},
+		{
+			name:     "YAML",
+			filename: "template.yaml",
+			data: `
+name: YAML Template
+ignore: ["*.tmp"]
+variables:
+  MixinName:
+    description: Name of the mixin
+    type: string
+    required: true
+conditional_paths:
+  .golangci.yml:
+    when: feature("security.input_validation")
+hooks:
+  post_gen:
+    - go mod tidy
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFS := fstest.MapFS{
+				filepath.Join("template", tc.filename): &fstest.MapFile{Data: []byte(tc.data)},
+			}
+
+			config, err := LoadTemplateConfig(mockFS, "template")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.name+" Template", config.Name)
+			assert.Equal(t, []string{"*.tmp"}, config.Ignore)
+			require.Contains(t, config.Variables, "MixinName")
+			assert.True(t, config.Variables["MixinName"].Required)
+			require.Contains(t, config.ConditionalPaths, ".golangci.yml")
+			assert.Equal(t, `feature("security.input_validation")`, config.ConditionalPaths[".golangci.yml"].When)
+			assert.Equal(t, []HookStep{{Command: "go mod tidy"}}, config.Hooks["post_gen"])
+		})
+	}
+}
+
+func TestLoadTemplateConfig_RejectsMultipleFormats(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template/template.json": &fstest.MapFile{Data: []byte(`{"name":"json"}`)},
+		"template/template.yaml": &fstest.MapFile{Data: []byte("name: yaml\n")},
+	}
+
+	_, err := LoadTemplateConfig(mockFS, "template")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than one config file")
+	assert.Contains(t, err.Error(), "template.json")
+	assert.Contains(t, err.Error(), "template.yaml")
+}
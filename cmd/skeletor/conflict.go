@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// ConflictPolicy decides what CreateMixin does with a planned file that
+// already exists in outputDir, for the "cookiecutter-update" workflow of
+// re-running generation against a mixin the user has since edited by hand.
+type ConflictPolicy string
+
+const (
+	// ConflictOverwrite replaces the existing file, the historical behavior
+	// and the default, so existing callers and scripts are unaffected.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail aborts generation the first time a planned file already
+	// exists, before any file is written.
+	ConflictFail ConflictPolicy = "fail"
+	// ConflictSkip leaves the existing file untouched and does not write it.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictMerge leaves existing files untouched (like ConflictSkip) but,
+	// unlike it, exists as a distinct name so --on-conflict=merge documents
+	// intent: pulling in new files a template added since the last run
+	// without clobbering the ones the user has already customized.
+	ConflictMerge ConflictPolicy = "merge"
+	// ConflictPrompt asks the user, per conflicting file, whether to
+	// overwrite it.
+	ConflictPrompt ConflictPolicy = "prompt"
+)
+
+// parseConflictPolicy parses the --on-conflict flag value, defaulting to
+// ConflictOverwrite (the pre-existing behavior) for an empty string.
+func parseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch ConflictPolicy(s) {
+	case "", ConflictOverwrite:
+		return ConflictOverwrite, nil
+	case ConflictFail, ConflictSkip, ConflictMerge, ConflictPrompt:
+		return ConflictPolicy(s), nil
+	default:
+		return "", fmt.Errorf("unknown --on-conflict value %q (expected fail, overwrite, skip, merge, or prompt)", s)
+	}
+}
+
+// resolvePlanConflicts filters plan's file entries against their current
+// state in outputDir according to policy, dropping (or failing on) any that
+// already exist. Directory entries always pass through unchanged, since
+// MkdirAll-ing an existing directory is never a conflict.
+func resolvePlanConflicts(plan []genWorkItem, policy ConflictPolicy) ([]genWorkItem, error) {
+	resolved := make([]genWorkItem, 0, len(plan))
+	for _, item := range plan {
+		if item.isDir {
+			resolved = append(resolved, item)
+			continue
+		}
+
+		if _, err := os.Stat(item.destPath); err != nil {
+			resolved = append(resolved, item)
+			continue
+		}
+
+		if item.forceOverwrite {
+			// A path_policy "overwrite" capability wins regardless of the
+			// run's global --on-conflict policy.
+			resolved = append(resolved, item)
+			continue
+		}
+
+		switch policy {
+		case ConflictFail:
+			return nil, fmt.Errorf("refusing to overwrite existing file %s (--on-conflict=fail); rerun with --on-conflict=overwrite, skip, or merge", item.destPath)
+		case ConflictSkip, ConflictMerge:
+			fmt.Printf("  Skipping existing file: %s\n", item.destPath)
+		case ConflictPrompt:
+			if promptYesNo(fmt.Sprintf("%s already exists. Overwrite?", item.destPath)) {
+				resolved = append(resolved, item)
+			} else {
+				fmt.Printf("  Skipping existing file: %s\n", item.destPath)
+			}
+		default: // ConflictOverwrite
+			resolved = append(resolved, item)
+		}
+	}
+	return resolved, nil
+}
+
+// describeDryRunConflict returns the [Dry Run] line for item, which is
+// already known to exist on disk, without prompting (dry runs never touch
+// the terminal beyond printing).
+func describeDryRunConflict(item genWorkItem, policy ConflictPolicy) string {
+	if item.forceOverwrite {
+		return fmt.Sprintf("[Dry Run] Would overwrite: %s (from source %s, path_policy overwrite capability)", item.destPath, item.sourcePath)
+	}
+	switch policy {
+	case ConflictFail:
+		return fmt.Sprintf("[Dry Run] Would fail: %s already exists (--on-conflict=fail)", item.destPath)
+	case ConflictSkip, ConflictMerge:
+		return fmt.Sprintf("[Dry Run] Would skip existing file: %s", item.destPath)
+	case ConflictPrompt:
+		return fmt.Sprintf("[Dry Run] Would prompt before overwriting: %s", item.destPath)
+	default: // ConflictOverwrite
+		return fmt.Sprintf("[Dry Run] Would overwrite: %s (from source %s)", item.destPath, item.sourcePath)
+	}
+}
+
+// promptYesNo asks prompt as a yes/no question, defaulting to no on an
+// empty or unrecognized answer.
+func promptYesNo(prompt string) bool {
+	answer := strings.ToLower(promptString(fmt.Sprintf("%s [y/N] ", prompt)))
+	return answer == "y" || answer == "yes"
+}
+
+// mixinGenIgnoreFilename is the gitignore-syntax file, read from the
+// template root, whose patterns augment config.Ignore. Unlike config.Ignore
+// (which ships inside template.json and is authored by the template
+// itself), a .mixingenignore file lives alongside it as plain text so it
+// reads naturally next to a .gitignore in the rendered output's template.
+const mixinGenIgnoreFilename = ".mixingenignore"
+
+// loadMixinGenIgnore reads templateRoot's .mixingenignore file, if present,
+// and returns its patterns to append to config.Ignore. Each non-blank,
+// non-"#"-comment line is a filepath.Match pattern matched the same way
+// config.Ignore's patterns are in calculateDestPath; a missing file is not
+// an error. This is a simplified subset of gitignore syntax -- no
+// negation ("!pattern"), no "**" recursive globs, no directory-only
+// ("trailing/") anchoring -- sized to what filepath.Match already supports.
+func loadMixinGenIgnore(tmplFS fs.FS, templateRoot string) ([]string, error) {
+	ignorePath := path.Join(templateRoot, mixinGenIgnoreFilename)
+	content, err := fs.ReadFile(tmplFS, ignorePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", ignorePath, err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConflictPolicy(t *testing.T) {
+	policy, err := parseConflictPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictOverwrite, policy)
+
+	for _, name := range []string{"fail", "overwrite", "skip", "merge", "prompt"} {
+		policy, err := parseConflictPolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, ConflictPolicy(name), policy)
+	}
+
+	_, err = parseConflictPolicy("explode")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"explode"`)
+}
+
+func conflictTestFixture() (fstest.MapFS, *TemplateConfig, map[string]interface{}) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{
+			Data: []byte(`{"name": "Conflict Test", "variables": {"MixinName": {}}}`),
+		},
+		"existing.txt.tmpl": &fstest.MapFile{Data: []byte("new content for {{ .MixinName }}")},
+		"fresh.txt.tmpl":    &fstest.MapFile{Data: []byte("fresh content for {{ .MixinName }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Conflict Test",
+		Variables: map[string]Variable{"MixinName": {}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+	return mockFS, config, data
+}
+
+func TestCreateMixin_OnConflictOverwriteIsDefault(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new content for my-mixin", string(content))
+}
+
+func TestCreateMixin_OnConflictFail(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+	err := createMixin(data, mockFS, ".", outputDir, config, "", 0, "fail", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "existing.txt")
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old content", string(content), "a failed run must not have touched the file")
+}
+
+func TestCreateMixin_OnConflictSkipAndMergeLeaveExistingFileAlone(t *testing.T) {
+	for _, policy := range []string{"skip", "merge"} {
+		t.Run(policy, func(t *testing.T) {
+			mockFS, config, data := conflictTestFixture()
+			outputDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+			require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, policy, nil))
+
+			content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "old content", string(content))
+
+			// New files from the template still get written.
+			fresh, err := os.ReadFile(filepath.Join(outputDir, "fresh.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "fresh content for my-mixin", string(fresh))
+		})
+	}
+}
+
+func TestCreateMixin_OnConflictPromptRespectsAnswer(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+	cleanup := mockStdin(t, "y\n")
+	defer cleanup()
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "prompt", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new content for my-mixin", string(content))
+}
+
+func TestLoadMixinGenIgnore(t *testing.T) {
+	mockFS := fstest.MapFS{
+		".mixingenignore": &fstest.MapFile{Data: []byte("# comment\n\nsecrets.txt\n*.local\n")},
+	}
+	patterns, err := loadMixinGenIgnore(mockFS, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"secrets.txt", "*.local"}, patterns)
+
+	patterns, err = loadMixinGenIgnore(fstest.MapFS{}, ".")
+	require.NoError(t, err)
+	assert.Nil(t, patterns)
+}
+
+func TestCreateMixin_MixinGenIgnoreAugmentsConfigIgnore(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{
+			Data: []byte(`{"name": "Ignore Test", "variables": {"MixinName": {}}}`),
+		},
+		".mixingenignore": &fstest.MapFile{Data: []byte("secret.txt.tmpl\n")},
+		"secret.txt.tmpl": &fstest.MapFile{Data: []byte("shh")},
+		"public.txt.tmpl": &fstest.MapFile{Data: []byte("hello {{ .MixinName }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Ignore Test",
+		Variables: map[string]Variable{"MixinName": {}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	_, err := os.Stat(filepath.Join(outputDir, "secret.txt"))
+	assert.True(t, os.IsNotExist(err), ".mixingenignore should have kept secret.txt from being written")
+	_, err = os.Stat(filepath.Join(outputDir, ".mixingenignore"))
+	assert.True(t, os.IsNotExist(err), ".mixingenignore itself should not be copied to output")
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "public.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello my-mixin", string(content))
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strings"
+)
+
+// resolveFileConstraint finds the build-constraint expression (if any)
+// gating originalPath: either a "<path>.constraints" sidecar file next to it
+// in tmplFS, or a config.Constraints[destRelPath] entry in template.json.
+// The sidecar takes precedence since it travels with the file it gates. An
+// empty result means the file is unconstrained.
+func resolveFileConstraint(tmplFS fs.FS, originalPath, destRelPath string, constraints map[string]string) (string, error) {
+	sidecarPath := originalPath + ".constraints"
+	if data, err := fs.ReadFile(tmplFS, sidecarPath); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read constraints sidecar %s: %w", sidecarPath, err)
+	}
+
+	if expr, ok := constraints[destRelPath]; ok {
+		return expr, nil
+	}
+	return "", nil
+}
+
+// fileConstraintTags builds the synthetic tag set a file's constraint
+// expression is evaluated against: runtime.GOOS and runtime.GOARCH, plus one
+// tag per non-empty string-valued template variable (its value) and one tag
+// per true bool-valued template variable (its name). So a variable
+// ComplianceLevel: "slsa-l3" makes the "slsa-l3" tag true, and a variable
+// EnableSecurity: true makes the "EnableSecurity" tag true.
+func fileConstraintTags(data map[string]interface{}) map[string]bool {
+	tags := map[string]bool{
+		runtime.GOOS:   true,
+		runtime.GOARCH: true,
+	}
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				tags[v] = true
+			}
+		case bool:
+			if v {
+				tags[key] = true
+			}
+		}
+	}
+	return tags
+}
+
+// EvaluateConstraint reports whether expr -- a build-tag-like expression
+// such as "slsa-l3,!windows" or "linux|darwin" -- is satisfied by tags. It
+// implements the same boolean algebra as Go's build-constraint matching:
+// "," or "&&" is AND, "|" or "||" is OR, "!" is NOT, and parentheses group,
+// with AND binding tighter than OR. An undeclared tag is simply false,
+// never an error.
+func EvaluateConstraint(expr string, tags map[string]bool) (bool, error) {
+	tokens, err := tokenizeConstraint(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &constraintParser{tokens: tokens, tags: tags}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.peek().kind != constraintTokEOF {
+		return false, fmt.Errorf("unexpected token %q in constraint %q", p.peek().text, expr)
+	}
+	return result, nil
+}
+
+type constraintTokenKind int
+
+const (
+	constraintTokEOF constraintTokenKind = iota
+	constraintTokIdent
+	constraintTokAnd
+	constraintTokOr
+	constraintTokNot
+	constraintTokLParen
+	constraintTokRParen
+)
+
+type constraintToken struct {
+	kind constraintTokenKind
+	text string
+}
+
+// tokenizeConstraint splits expr into tags, "," / "&&" (AND), "|" / "||"
+// (OR), "!" (NOT) and parentheses.
+func tokenizeConstraint(expr string) ([]constraintToken, error) {
+	var tokens []constraintToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == ',':
+			tokens = append(tokens, constraintToken{constraintTokAnd, ","})
+			i++
+		case r == '!':
+			tokens = append(tokens, constraintToken{constraintTokNot, "!"})
+			i++
+		case r == '(':
+			tokens = append(tokens, constraintToken{constraintTokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, constraintToken{constraintTokRParen, ")"})
+			i++
+		case r == '&':
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				tokens = append(tokens, constraintToken{constraintTokAnd, "&&"})
+				i += 2
+			} else {
+				tokens = append(tokens, constraintToken{constraintTokAnd, "&"})
+				i++
+			}
+		case r == '|':
+			if i+1 < len(runes) && runes[i+1] == '|' {
+				tokens = append(tokens, constraintToken{constraintTokOr, "||"})
+				i += 2
+			} else {
+				tokens = append(tokens, constraintToken{constraintTokOr, "|"})
+				i++
+			}
+		default:
+			start := i
+			for i < len(runes) && !strings.ContainsRune(" \t\n\r,!()&|", runes[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected character %q in constraint %q", r, expr)
+			}
+			tokens = append(tokens, constraintToken{constraintTokIdent, string(runes[start:i])})
+		}
+	}
+	tokens = append(tokens, constraintToken{constraintTokEOF, ""})
+	return tokens, nil
+}
+
+// constraintParser is a recursive-descent parser for EvaluateConstraint's
+// grammar: or := and (OR and)*; and := unary (AND unary)*;
+// unary := NOT unary | "(" or ")" | IDENT.
+type constraintParser struct {
+	tokens []constraintToken
+	pos    int
+	tags   map[string]bool
+}
+
+func (p *constraintParser) peek() constraintToken {
+	return p.tokens[p.pos]
+}
+
+func (p *constraintParser) next() constraintToken {
+	tok := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *constraintParser) parseOr() (bool, error) {
+	result, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for p.peek().kind == constraintTokOr {
+		p.next()
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		result = result || rhs
+	}
+	return result, nil
+}
+
+func (p *constraintParser) parseAnd() (bool, error) {
+	result, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for p.peek().kind == constraintTokAnd {
+		p.next()
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		result = result && rhs
+	}
+	return result, nil
+}
+
+func (p *constraintParser) parseUnary() (bool, error) {
+	switch p.peek().kind {
+	case constraintTokNot:
+		p.next()
+		value, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		return !value, nil
+	case constraintTokLParen:
+		p.next()
+		value, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if p.peek().kind != constraintTokRParen {
+			return false, fmt.Errorf("missing closing paren in constraint")
+		}
+		p.next()
+		return value, nil
+	case constraintTokIdent:
+		tok := p.next()
+		return p.tags[tok.text], nil
+	default:
+		return false, fmt.Errorf("expected a tag, \"!\" or \"(\", got %q", p.peek().text)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateConstraint(t *testing.T) {
+	tags := map[string]bool{"slsa-l3": true, "linux": true}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr string
+	}{
+		{name: "bare tag true", expr: "slsa-l3", want: true},
+		{name: "bare tag false", expr: "windows", want: false},
+		{name: "AND via comma, both true", expr: "slsa-l3,linux", want: true},
+		{name: "AND via comma, one false", expr: "slsa-l3,windows", want: false},
+		{name: "AND via &&", expr: "slsa-l3 && linux", want: true},
+		{name: "OR via |", expr: "windows|linux", want: true},
+		{name: "OR via ||", expr: "windows || darwin", want: false},
+		{name: "NOT", expr: "!windows", want: true},
+		{name: "NOT of true tag", expr: "!slsa-l3", want: false},
+		{name: "combined AND/OR/NOT", expr: "slsa-l3 && !windows", want: true},
+		{name: "parens override precedence", expr: "(windows|linux) && slsa-l3", want: true},
+		{name: "unknown tag is just false", expr: "unknown-tag", want: false},
+		{name: "trailing garbage errors", expr: "slsa-l3)", wantErr: "unexpected token"},
+		{name: "dangling operator errors", expr: "slsa-l3 &&", wantErr: "expected a tag"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := EvaluateConstraint(tc.expr, tags)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFileConstraintTags(t *testing.T) {
+	tags := fileConstraintTags(map[string]interface{}{
+		"ComplianceLevel": "slsa-l3",
+		"EnableSecurity":  true,
+		"EnableAuth":      false,
+		"EmptyVar":        "",
+	})
+
+	assert.True(t, tags["slsa-l3"])
+	assert.True(t, tags["EnableSecurity"])
+	assert.False(t, tags["EnableAuth"])
+	assert.False(t, tags[""])
+	assert.True(t, tags[runtime.GOOS])
+	assert.True(t, tags[runtime.GOARCH])
+}
+
+func TestCreateMixin_ConstraintsSkipFiles(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{Data: []byte(`{
+			"name": "Constraints Test",
+			"variables": {"MixinName": {"type": "string", "required": true}},
+			"constraints": {"Dockerfile.windows.tmpl": "windows"}
+		}`)},
+		"Dockerfile.windows.tmpl": &fstest.MapFile{Data: []byte("windows dockerfile for {{ .MixinName }}")},
+		"Dockerfile.linux.tmpl":   &fstest.MapFile{Data: []byte("linux dockerfile for {{ .MixinName }}")},
+		"Dockerfile.linux.tmpl.constraints": &fstest.MapFile{
+			Data: []byte("linux\n"),
+		},
+	}
+	config := &TemplateConfig{
+		Name:        "Constraints Test",
+		Variables:   map[string]Variable{"MixinName": {Type: "string", Required: true}},
+		Constraints: map[string]string{"Dockerfile.windows.tmpl": "windows"},
+		Ignore:      []string{},
+		Hooks:       map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	_, err := os.Stat(filepath.Join(outputDir, "Dockerfile.windows"))
+	if runtime.GOOS == "windows" {
+		assert.NoError(t, err, "windows dockerfile should be written on a windows runtime")
+	} else {
+		assert.True(t, os.IsNotExist(err), "windows dockerfile should be skipped off a windows runtime")
+	}
+
+	wantLinux := runtime.GOOS == "linux"
+	_, err = os.Stat(filepath.Join(outputDir, "Dockerfile.linux"))
+	if wantLinux {
+		assert.NoError(t, err, "linux dockerfile should be written on a linux runtime")
+	} else {
+		assert.True(t, os.IsNotExist(err), "linux dockerfile should be skipped off a linux runtime")
+	}
+}
@@ -27,7 +27,7 @@ func TestCreateMixinDebug(t *testing.T) {
 		t.Logf("Testing createMixin with dry run...")
 
 		// Actually call createMixin with dry run
-		err = createMixin(data, pkg.MixinTemplateFS, "template", "./debug-test", config, true)
+		err = createMixin(data, pkg.MixinTemplateFS, "template", "./debug-test", config, "plain", 0, "", nil)
 		require.NoError(t, err)
 
 		// Also test the walking behavior separately for debugging
@@ -52,7 +52,7 @@ func TestCreateMixinDebug(t *testing.T) {
 			}
 
 			// Determine the actual source path and file info, handling conditional logic
-			sourcePath, info, skip, err := determineSourcePath(pkg.MixinTemplateFS, path, destRelPath, "template", config.ConditionalPaths, data)
+			sourcePath, info, skip, err := determineSourcePath(pkg.MixinTemplateFS, path, destRelPath, "template", config.ConditionalPaths, data, funcMap)
 			if err != nil {
 				t.Logf("  Error in determineSourcePath for %s: %v", path, err)
 				return err
@@ -66,7 +66,7 @@ func TestCreateMixinDebug(t *testing.T) {
 			}
 
 			// Process the final destination path using template data
-			finalDestPath, err := processDestPath(destRelPath, "./debug-test", data)
+			finalDestPath, err := processDestPath(destRelPath, "./debug-test", data, funcMap)
 			if err != nil {
 				t.Logf("  Error in processDestPath for %s: %v", destRelPath, err)
 				return err
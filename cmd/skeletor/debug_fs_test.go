@@ -115,3 +115,40 @@ func TestEmbeddedFilesystem(t *testing.T) {
 		}
 	})
 }
+
+func TestEmbeddedPluginFilesystem(t *testing.T) {
+	t.Run("Check embedded FS structure", func(t *testing.T) {
+		entries, err := fs.ReadDir(pkg.PluginTemplateFS, ".")
+		require.NoError(t, err)
+
+		t.Logf("Root entries: %d", len(entries))
+		for _, entry := range entries {
+			t.Logf("  - %s (dir: %v)", entry.Name(), entry.IsDir())
+		}
+
+		templateEntries, err := fs.ReadDir(pkg.PluginTemplateFS, "template-plugin")
+		require.NoError(t, err)
+
+		t.Logf("Template entries: %d", len(templateEntries))
+		for _, entry := range templateEntries {
+			t.Logf("  - template-plugin/%s (dir: %v)", entry.Name(), entry.IsDir())
+		}
+
+		assert.True(t, len(templateEntries) > 0, "Plugin template directory should contain files")
+
+		_, err = fs.Stat(pkg.PluginTemplateFS, "template-plugin/template.json")
+		assert.NoError(t, err, "template.json should exist")
+
+		_, err = fs.Stat(pkg.PluginTemplateFS, "template-plugin/cmd/plugin/main.go.tmpl")
+		assert.NoError(t, err, "main.go.tmpl should exist")
+
+		_, err = fs.Stat(pkg.PluginTemplateFS, "template-plugin/pkg/plugin.go.tmpl")
+		assert.NoError(t, err, "plugin.go.tmpl should exist")
+	})
+
+	t.Run("Test calculateDestPath function with template-plugin root", func(t *testing.T) {
+		destPath, skip := calculateDestPath("template-plugin/cmd/plugin/main.go.tmpl", "template-plugin", []string{})
+		assert.Equal(t, "cmd/plugin/main.go.tmpl", destPath)
+		assert.False(t, skip)
+	})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Dependency declares another template composed into this one's output, the
+// template-author equivalent of a Helm subchart: a shared "observability"
+// or "security" sub-template can be pulled in by name instead of every
+// top-level template re-implementing the same wiring behind its own
+// boolean toggle.
+type Dependency struct {
+	// Name keys this dependency's assembled data under the parent's
+	// .Dependencies (e.g. .Dependencies.observability) and is OutputFolder's
+	// default.
+	Name string `json:"name"`
+
+	// Source locates the dependency's template: a path relative to this
+	// template's own root, resolved against the same filesystem this
+	// template was loaded from (e.g. "shared/observability"), or a
+	// "scheme://ref" resolvable via ResolveTemplateSource, e.g.
+	// "git://github.com/example/templates.git#v1.0".
+	Source string `json:"source"`
+
+	// Variables projects the parent's data into the dependency's variable
+	// namespace: dependency variable name -> Go template expression
+	// evaluated against the parent's data, e.g.
+	// {"ServiceName": "{{ .MixinName }}"}. The dependency is resolved
+	// non-interactively, so any of its own required variables not covered
+	// here must have a Default.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// OutputFolder is where the dependency's generated files land, relative
+	// to this template's own output directory. Defaults to Name.
+	OutputFolder string `json:"output_folder,omitempty"`
+
+	// OnlyIf is a Go template expression (the same truthy rules as a
+	// HookStep's When) evaluated against the parent's data; the dependency
+	// is skipped entirely -- no data, no generated files -- unless it
+	// renders truthy. Omit to always resolve the dependency.
+	OnlyIf string `json:"only_if,omitempty"`
+}
+
+// resolveDependencySource locates dep.Source's template tree: a
+// "scheme://ref" is handed to ResolveTemplateSource (the same registry
+// --template-source uses), anything else is treated as a path relative to
+// parentRoot within parentFS, for a sub-template that ships inside the
+// parent's own template bundle.
+func resolveDependencySource(parentFS fs.FS, parentRoot string, dep Dependency) (fs.FS, string, func() error, error) {
+	if _, _, found := strings.Cut(dep.Source, "://"); found {
+		fsys, root, cleanup, err := ResolveTemplateSource(dep.Source)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("dependency %q: %w", dep.Name, err)
+		}
+		return fsys, root, cleanup, nil
+	}
+
+	root := path.Join(parentRoot, dep.Source)
+	if _, err := fs.Stat(parentFS, root); err != nil {
+		return nil, "", nil, fmt.Errorf("dependency %q: local source %q not found under %s: %w", dep.Name, dep.Source, parentRoot, err)
+	}
+	return parentFS, root, nil, nil
+}
+
+// resolveDependencies walks config.Dependencies depth-first: each
+// dependency's own dependencies are resolved and generated before the
+// dependency itself is generated, so a nested sub-template's output exists
+// by the time anything that composes it runs. It returns the resolved
+// dependencies' assembled data, keyed by Dependency.Name, for the caller to
+// expose to its own templates as .Dependencies.<name>.
+//
+// chain is the list of Source values already being resolved on the current
+// path, used to reject a cycle (e.g. A depends on B which depends on A
+// again) instead of recursing forever.
+func resolveDependencies(e *Engine, parentFS fs.FS, parentRoot string, config *TemplateConfig, parentData map[string]interface{}, outputDir, complianceLevel, dryRun string, parallelism int, onConflict string, chain []string) (map[string]interface{}, error) {
+	if len(config.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	resolved := make(map[string]interface{}, len(config.Dependencies))
+	for _, dep := range config.Dependencies {
+		if dep.OnlyIf != "" {
+			keep, err := evaluateHookWhen(dep.OnlyIf, parentData)
+			if err != nil {
+				return nil, fmt.Errorf("dependency %q: %w", dep.Name, err)
+			}
+			if !keep {
+				continue
+			}
+		}
+
+		for _, seen := range chain {
+			if seen == dep.Source {
+				return nil, fmt.Errorf("dependency cycle detected: %s -> %s", strings.Join(chain, " -> "), dep.Source)
+			}
+		}
+
+		depFS, depRoot, cleanup, err := resolveDependencySource(parentFS, parentRoot, dep)
+		if err != nil {
+			return nil, err
+		}
+		if cleanup != nil {
+			defer cleanup()
+		}
+
+		depConfig, err := LoadTemplateConfig(depFS, depRoot)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: failed to load template config: %w", dep.Name, err)
+		}
+
+		projected := make(map[string]interface{}, len(dep.Variables))
+		for childVar, expr := range dep.Variables {
+			rendered, err := renderHookCommand(expr, parentData)
+			if err != nil {
+				return nil, fmt.Errorf("dependency %q: projecting variable %q: %w", dep.Name, childVar, err)
+			}
+			projected[childVar] = rendered
+		}
+
+		outputFolder := dep.OutputFolder
+		if outputFolder == "" {
+			outputFolder = dep.Name
+		}
+		depOutputDir := filepath.Join(outputDir, outputFolder)
+
+		depData, err := buildTemplateData(depConfig, "", "", "", depOutputDir, complianceLevel, true, nil, projected)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", dep.Name, err)
+		}
+
+		nestedDeps, err := resolveDependencies(e, depFS, depRoot, depConfig, depData, depOutputDir, complianceLevel, dryRun, parallelism, onConflict, append(chain, dep.Source))
+		if err != nil {
+			return nil, err
+		}
+		if len(nestedDeps) > 0 {
+			depData["Dependencies"] = nestedDeps
+		}
+
+		if err := e.CreateMixin(depData, depFS, depRoot, depOutputDir, depConfig, dryRun, parallelism, onConflict, nil); err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", dep.Name, err)
+		}
+
+		resolved[dep.Name] = depData
+	}
+	return resolved, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDependencySource_LocalPath(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json":                      &fstest.MapFile{Data: []byte(`{"name": "Parent"}`)},
+		"shared/observability/template.json": &fstest.MapFile{Data: []byte(`{"name": "Observability"}`)},
+	}
+
+	fsys, root, cleanup, err := resolveDependencySource(mockFS, ".", Dependency{Name: "observability", Source: "shared/observability"})
+	require.NoError(t, err)
+	assert.Nil(t, cleanup)
+	assert.Equal(t, mockFS, fsys)
+	assert.Equal(t, "shared/observability", root)
+}
+
+func TestResolveDependencySource_LocalPathMissing(t *testing.T) {
+	mockFS := fstest.MapFS{"template.json": &fstest.MapFile{Data: []byte(`{"name": "Parent"}`)}}
+
+	_, _, _, err := resolveDependencySource(mockFS, ".", Dependency{Name: "missing", Source: "no/such/dir"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+}
+
+func dependenciesTestFixture() (fstest.MapFS, *TemplateConfig, map[string]interface{}) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{Data: []byte(`{"name": "Parent", "variables": {"MixinName": {}}}`)},
+		"shared/observability/template.json": &fstest.MapFile{
+			Data: []byte(`{
+				"name": "Observability",
+				"variables": {"ServiceName": {"required": true}}
+			}`),
+		},
+		"shared/observability/otel.go.tmpl": &fstest.MapFile{Data: []byte("package otel // wired for {{ .ServiceName }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Parent",
+		Variables: map[string]Variable{"MixinName": {}},
+		Dependencies: []Dependency{
+			{
+				Name:      "observability",
+				Source:    "shared/observability",
+				Variables: map[string]string{"ServiceName": "{{ .MixinName }}"},
+			},
+		},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+	return mockFS, config, data
+}
+
+func TestResolveDependencies_ProjectsVariablesAndGeneratesFiles(t *testing.T) {
+	mockFS, config, data := dependenciesTestFixture()
+	outputDir := t.TempDir()
+
+	resolved, err := resolveDependencies(&Engine{}, mockFS, ".", config, data, outputDir, "basic", "", 0, "", nil)
+	require.NoError(t, err)
+
+	depData, ok := resolved["observability"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "my-mixin", depData["ServiceName"])
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "observability", "otel.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package otel // wired for my-mixin", string(content))
+}
+
+func TestResolveDependencies_OutputFolderOverridesName(t *testing.T) {
+	mockFS, config, data := dependenciesTestFixture()
+	config.Dependencies[0].OutputFolder = "otel"
+	outputDir := t.TempDir()
+
+	_, err := resolveDependencies(&Engine{}, mockFS, ".", config, data, outputDir, "basic", "", 0, "", nil)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(outputDir, "otel", "otel.go"))
+	require.NoError(t, err)
+}
+
+func TestResolveDependencies_OnlyIfSkipsDependency(t *testing.T) {
+	mockFS, config, data := dependenciesTestFixture()
+	config.Dependencies[0].OnlyIf = "{{ .EnableObservability }}"
+	outputDir := t.TempDir()
+
+	resolved, err := resolveDependencies(&Engine{}, mockFS, ".", config, data, outputDir, "basic", "", 0, "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, resolved)
+
+	_, err = os.Stat(filepath.Join(outputDir, "observability"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestResolveDependencies_NoDependenciesReturnsNil(t *testing.T) {
+	config := &TemplateConfig{Name: "Parent"}
+	resolved, err := resolveDependencies(&Engine{}, fstest.MapFS{}, ".", config, map[string]interface{}{}, t.TempDir(), "basic", "", 0, "", nil)
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+}
+
+func TestResolveDependencies_CycleDetected(t *testing.T) {
+	mockFS, config, data := dependenciesTestFixture()
+	outputDir := t.TempDir()
+
+	_, err := resolveDependencies(&Engine{}, mockFS, ".", config, data, outputDir, "basic", "", 0, "", []string{"shared/observability"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
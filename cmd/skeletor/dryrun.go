@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/template"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DryRunMode is the --dry-run flag's value: off, the historical plain-text
+// simulation, or a machine-readable plan for CI to gate on.
+type DryRunMode string
+
+const (
+	// DryRunOff runs CreateMixin for real -- the default, an empty string.
+	DryRunOff DryRunMode = ""
+	// DryRunPlain is the historical bare "--dry-run" behavior: a "[Dry Run]
+	// ..." line per planned file/directory, with a unified diff against any
+	// existing file that would change.
+	DryRunPlain DryRunMode = "plain"
+	// DryRunJSON emits the plan as a JSON array of DryRunPlanEntry instead
+	// of the human-readable log, for a CI step to parse.
+	DryRunJSON DryRunMode = "json"
+)
+
+// parseDryRunMode parses the --dry-run flag value. It's only ever called
+// with "" (flag unset), "plain" (bare --dry-run, via its NoOptDefVal), or
+// "json" (--dry-run=json); anything else is a user typo.
+func parseDryRunMode(s string) (DryRunMode, error) {
+	switch DryRunMode(s) {
+	case DryRunOff, DryRunPlain, DryRunJSON:
+		return DryRunMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown --dry-run value %q (expected plain or json)", s)
+	}
+}
+
+// DryRunPlanEntry is one planned file's outcome in a --dry-run=json plan:
+// whether it's new, would change an existing file, or would leave one
+// unchanged, plus the sha256 of its content before and after so CI can
+// diff plans across runs without re-rendering anything itself.
+type DryRunPlanEntry struct {
+	Path         string `json:"path"`
+	Action       string `json:"action"` // "new", "changed", or "unchanged"
+	SHA256Before string `json:"sha256_before,omitempty"`
+	SHA256After  string `json:"sha256_after,omitempty"`
+}
+
+// runDryRunPlan renders every file in plan (without writing it) and reports
+// the result per mode: DryRunPlain prints a "[Dry Run] ..." line per entry,
+// a unified diff for any file that would change, and a final
+// "N files new, M files changed, K files unchanged" summary; DryRunJSON
+// prints the plan as JSON instead. Directory entries are only ever "new" in
+// the sense that they'd be created, so they're logged in plain mode but
+// don't appear in the JSON plan or summary counts.
+func runDryRunPlan(tmplFS fs.FS, plan []genWorkItem, data map[string]interface{}, funcs template.FuncMap, policy ConflictPolicy, mode DryRunMode, licensePolicies []LicensePolicy) error {
+	if mode == DryRunPlain {
+		fmt.Println("[Dry Run] Simulating file generation...")
+	}
+
+	var entries []DryRunPlanEntry
+	var newCount, changedCount, unchangedCount int
+
+	for _, item := range plan {
+		if item.isDir {
+			if mode == DryRunPlain {
+				fmt.Printf("[Dry Run] Would create directory: %s\n", item.destPath)
+			}
+			continue
+		}
+
+		rendered, err := renderTemplateFileContent(tmplFS, item.sourcePath, item.destPath, data, funcs, licensePolicies, item.destRelPath)
+		if err != nil {
+			return err
+		}
+		entry := DryRunPlanEntry{Path: item.destPath, SHA256After: sha256HexBytes([]byte(rendered))}
+
+		existing, readErr := os.ReadFile(item.destPath)
+		switch {
+		case readErr != nil:
+			entry.Action = "new"
+			newCount++
+			if mode == DryRunPlain {
+				fmt.Printf("[Dry Run] Would write file: %s (from source %s)\n", item.destPath, item.sourcePath)
+			}
+		case string(existing) == rendered:
+			entry.Action = "unchanged"
+			entry.SHA256Before = sha256HexBytes(existing)
+			unchangedCount++
+			if mode == DryRunPlain {
+				fmt.Printf("[Dry Run] Unchanged: %s\n", item.destPath)
+			}
+		default:
+			entry.Action = "changed"
+			entry.SHA256Before = sha256HexBytes(existing)
+			changedCount++
+			if mode == DryRunPlain {
+				fmt.Println(describeDryRunConflict(item, policy))
+				fmt.Print(unifiedDiff(string(existing), rendered, item.destPath))
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if mode == DryRunJSON {
+		encoded, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode dry-run plan: %w", err)
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
+	fmt.Printf("\n[Dry Run] %d files new, %d files changed, %d files unchanged.\n", newCount, changedCount, unchangedCount)
+	fmt.Println("[Dry Run] Simulation complete.")
+	return nil
+}
+
+// unifiedDiff renders a git-style unified diff of before -> after, both
+// attributed to path since a dry run diffs a single file against itself at
+// two points in time rather than two different files.
+func unifiedDiff(before, after, path string) string {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	}
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return fmt.Sprintf("(failed to compute diff for %s: %v)\n", path, err)
+	}
+	return text
+}
+
+// sha256HexBytes hashes data and returns its hex-encoded sha256 digest, for
+// DryRunPlanEntry's sha256_before/sha256_after fields.
+func sha256HexBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDryRunMode(t *testing.T) {
+	mode, err := parseDryRunMode("")
+	require.NoError(t, err)
+	assert.Equal(t, DryRunOff, mode)
+
+	mode, err = parseDryRunMode("plain")
+	require.NoError(t, err)
+	assert.Equal(t, DryRunPlain, mode)
+
+	mode, err = parseDryRunMode("json")
+	require.NoError(t, err)
+	assert.Equal(t, DryRunJSON, mode)
+
+	_, err = parseDryRunMode("yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"yaml"`)
+}
+
+func TestCreateMixin_DryRunPlainReportsChangedAndUnchangedFiles(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "fresh.txt"), []byte("fresh content for my-mixin"), 0600))
+
+	output := captureOutput(func() {
+		require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "plain", 0, "", nil))
+	})
+
+	assert.Contains(t, output, "-old content")
+	assert.Contains(t, output, "+new content for my-mixin")
+	assert.Contains(t, output, "Unchanged: "+filepath.Join(outputDir, "fresh.txt"))
+	assert.Contains(t, output, "0 files new, 1 files changed, 1 files unchanged.")
+
+	// A dry run must never write anything, regardless of what it reports.
+	content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old content", string(content))
+}
+
+func TestCreateMixin_DryRunJSONEmitsPlanEntries(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+	output := captureOutput(func() {
+		require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "json", 0, "", nil))
+	})
+
+	var entries []DryRunPlanEntry
+	require.NoError(t, json.Unmarshal([]byte(output), &entries))
+	require.Len(t, entries, 2)
+
+	byPath := make(map[string]DryRunPlanEntry, len(entries))
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+
+	changed := byPath[filepath.Join(outputDir, "existing.txt")]
+	assert.Equal(t, "changed", changed.Action)
+	assert.NotEmpty(t, changed.SHA256Before)
+	assert.NotEmpty(t, changed.SHA256After)
+	assert.NotEqual(t, changed.SHA256Before, changed.SHA256After)
+
+	fresh := byPath[filepath.Join(outputDir, "fresh.txt")]
+	assert.Equal(t, "new", fresh.Action)
+	assert.Empty(t, fresh.SHA256Before)
+	assert.NotEmpty(t, fresh.SHA256After)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	diff := unifiedDiff("line one\nline two\n", "line one\nline three\n", "some/file.txt")
+	assert.Contains(t, diff, "--- some/file.txt")
+	assert.Contains(t, diff, "+++ some/file.txt")
+	assert.Contains(t, diff, "-line two")
+	assert.Contains(t, diff, "+line three")
+}
+
+func TestSha256HexBytes(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha256HexBytes([]byte("hello")))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureEntry_UnmarshalJSON_LegacyBool(t *testing.T) {
+	var entry FeatureEntry
+	require.NoError(t, json.Unmarshal([]byte(`true`), &entry))
+	assert.True(t, entry.Enabled)
+	assert.Nil(t, entry.Enforcement)
+}
+
+func TestFeatureEntry_UnmarshalJSON_ObjectForm(t *testing.T) {
+	var entry FeatureEntry
+	raw := `{"enabled": true, "enforcement": [{"action": "warn", "scope": "generate"}, {"action": "deny", "scope": "validate"}]}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &entry))
+	assert.True(t, entry.Enabled)
+	require.Len(t, entry.Enforcement, 2)
+	assert.Equal(t, ScopedAction{Action: ActionWarn, Scope: ScopeGenerate}, entry.Enforcement[0])
+	assert.Equal(t, ScopedAction{Action: ActionDeny, Scope: ScopeValidate}, entry.Enforcement[1])
+}
+
+func TestFeatureEntry_UnmarshalJSON_Invalid(t *testing.T) {
+	var entry FeatureEntry
+	err := json.Unmarshal([]byte(`"not-a-bool-or-object"`), &entry)
+	require.Error(t, err)
+}
+
+func TestFeatureToggles_GetEnforcement(t *testing.T) {
+	toggles := &FeatureToggles{
+		Enforcement: map[string]map[string]FeatureEntry{
+			"security": {
+				"input_validation": {
+					Enabled: true,
+					Enforcement: []ScopedAction{
+						{Action: ActionWarn, Scope: ScopeGenerate},
+						{Action: ActionDeny, Scope: ScopeValidate},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []Action{ActionWarn}, toggles.GetEnforcement("security", "input_validation", ScopeGenerate))
+	assert.Equal(t, []Action{ActionDeny}, toggles.GetEnforcement("security", "input_validation", ScopeValidate))
+	assert.Nil(t, toggles.GetEnforcement("security", "input_validation", ScopeRuntimeHook))
+	assert.Nil(t, toggles.GetEnforcement("security", "unknown_feature", ScopeGenerate))
+	assert.Nil(t, toggles.GetEnforcement("unknown_category", "input_validation", ScopeGenerate))
+}
+
+func TestFeatureToggles_GetEnforcement_NilSafe(t *testing.T) {
+	var toggles *FeatureToggles
+	assert.Nil(t, toggles.GetEnforcement("security", "input_validation", ScopeGenerate))
+}
+
+func TestEnforcementFor_And_HasEnforcement(t *testing.T) {
+	data := map[string]interface{}{
+		"FeatureToggles": &FeatureToggles{
+			Enforcement: map[string]map[string]FeatureEntry{
+				"security": {
+					"rate_limiting": {
+						Enabled:     true,
+						Enforcement: []ScopedAction{{Action: ActionDryRun, Scope: ScopeRuntimeHook}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"dryrun"}, enforcementFor(data, "security", "rate_limiting", "runtime-hook"))
+	assert.True(t, hasEnforcement(data, "security", "rate_limiting", "runtime-hook"))
+	assert.False(t, hasEnforcement(data, "security", "rate_limiting", "generate"))
+}
+
+func TestEnforcementFor_MissingFeatureToggles(t *testing.T) {
+	data := map[string]interface{}{}
+	assert.Empty(t, enforcementFor(data, "security", "rate_limiting", "generate"))
+	assert.False(t, hasEnforcement(data, "security", "rate_limiting", "generate"))
+}
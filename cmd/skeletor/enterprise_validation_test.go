@@ -106,6 +106,8 @@ func TestEnterpriseFeatureToggleCombinations(t *testing.T) {
 				"basic",
 				true, // non-interactive
 				[]string{},
+				nil,
+				nil, // features.yaml flag set
 				tt.enableSecurity,
 				tt.enableCompliance,
 				tt.enableAuth,
@@ -245,6 +247,7 @@ func TestBackwardCompatibility(t *testing.T) {
 			"basic",
 			true, // non-interactive
 			[]string{},
+			nil,
 		)
 
 		require.NoError(t, err)
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// fileDecision records, for one candidate path in a template tree, whether
+// it was kept or dropped and which rule decided it -- `skeletor render
+// --explain`'s output.
+type fileDecision struct {
+	Path     string
+	Decision string // "kept" or "dropped"
+	Rule     string
+}
+
+// explainTemplateWalk walks tmplFS in the same order and with the same
+// skip/condition rules as CreateMixin's generation walk -- ignore/
+// .mixingenignore, SkipPatterns, FileConditions, ConditionalPaths, then
+// PathPolicy -- recording which rule kept or dropped each candidate instead
+// of rendering or writing anything.
+func explainTemplateWalk(tmplFS fs.FS, templateRoot string, config *TemplateConfig, data map[string]interface{}) ([]fileDecision, error) {
+	mixinGenIgnore, err := loadMixinGenIgnore(tmplFS, templateRoot)
+	if err != nil {
+		return nil, err
+	}
+	ignorePatterns := append(append([]string{}, config.Ignore...), mixinGenIgnore...)
+
+	funcs, err := (&Engine{}).FuncMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	pathRules, err := compileConfigPathPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+	fileConditionRules := compileFileConditions(config.FileConditions)
+
+	var decisions []fileDecision
+	walkErr := fs.WalkDir(tmplFS, templateRoot, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return fmt.Errorf("error walking template source at %s: %w", path, walkErr)
+		}
+		if path == templateRoot && d.IsDir() {
+			return nil
+		}
+
+		destRelPath, skip := calculateDestPath(path, templateRoot, ignorePatterns)
+		if skip {
+			for _, pattern := range ignorePatterns {
+				if matched, _ := filepath.Match(pattern, path); matched {
+					decisions = append(decisions, fileDecision{Path: path, Decision: "dropped", Rule: fmt.Sprintf("ignore %q", pattern)})
+					break
+				}
+			}
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if pattern, matched := evaluateSkipPatterns(config.SkipPatterns, destRelPath); matched {
+			decisions = append(decisions, fileDecision{Path: destRelPath, Decision: "dropped", Rule: fmt.Sprintf("skip_patterns %q", pattern)})
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		pattern, keep, matched, condErr := evaluateFileCondition(fileConditionRules, destRelPath, data, funcs)
+		if condErr != nil {
+			return condErr
+		}
+		if matched && !keep {
+			decisions = append(decisions, fileDecision{Path: destRelPath, Decision: "dropped", Rule: fmt.Sprintf("file_conditions %q", pattern)})
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		sourcePath, info, skip, err := determineSourcePath(tmplFS, path, destRelPath, templateRoot, config.ConditionalPaths, data, funcs)
+		_ = sourcePath
+		if err != nil {
+			return err
+		}
+		if skip {
+			decisions = append(decisions, fileDecision{Path: destRelPath, Decision: "dropped", Rule: "conditional_paths"})
+			if info != nil && info.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if pathRules != nil {
+			toggles, _ := data["FeatureToggles"].(*FeatureToggles)
+			rule, matchErr := pathRules.Match(destRelPath, toggles, data)
+			if matchErr != nil {
+				return matchErr
+			}
+			if rule != nil && rule.HasCapability(CapabilitySkip) {
+				decisions = append(decisions, fileDecision{Path: destRelPath, Decision: "dropped", Rule: fmt.Sprintf("path_policy %q", rule.Pattern)})
+				if info.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+
+		decisions = append(decisions, fileDecision{Path: destRelPath, Decision: "kept", Rule: "render"})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return decisions, nil
+}
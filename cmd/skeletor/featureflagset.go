@@ -0,0 +1,215 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// featureFlagsFilename is the features.yaml file a template ships next to
+// its template.json, the per-template analogue of the process-wide
+// DefaultFeatureGate: instead of a fixed catalog of built-in gates, a
+// template author declares exactly the flags their own template tree reads,
+// each with a lifecycle stage and dependencies on other flags in the file.
+const featureFlagsFilename = "features.yaml"
+
+// FeatureFlagStage is a FeatureFlagDef's lifecycle stage, mirroring the
+// FeatureGate Alpha/Beta/GA/Deprecated stages but named for a single
+// template's flags rather than skeletor's own built-in gates.
+type FeatureFlagStage string
+
+const (
+	StageExperimental FeatureFlagStage = "experimental"
+	StageStable       FeatureFlagStage = "stable"
+	StageDeprecated   FeatureFlagStage = "deprecated"
+	StageRetired      FeatureFlagStage = "retired"
+)
+
+// FeatureFlagDef is one flag declared in a template's features.yaml.
+type FeatureFlagDef struct {
+	Name               string           `yaml:"name"`
+	Stage              FeatureFlagStage `yaml:"stage"`
+	Default            bool             `yaml:"default"`
+	DependsOn          []string         `yaml:"depends_on,omitempty"`
+	DeprecationWarning string           `yaml:"deprecation_warning,omitempty"`
+}
+
+// FeatureFlagSet is a template's own feature-flag catalog, loaded from
+// features.yaml. Unlike DefaultFeatureGate, it's scoped to a single
+// generation run and never shared across templates.
+//
+// A flag's effective state is its Default unless Enable has explicitly
+// turned it on; there is deliberately no way to turn one off once enabled,
+// since a dependency another flag enabled transitively must stay on.
+type FeatureFlagSet struct {
+	defs    map[string]FeatureFlagDef
+	enabled map[string]bool
+	warned  map[string]bool
+}
+
+// LoadFeatureFlagSet reads templateRoot/features.yaml from tmplFS. A
+// template that doesn't ship one gets an empty set rather than an error,
+// matching loadMixinGenIgnore's tolerance of an absent sidecar file.
+func LoadFeatureFlagSet(tmplFS fs.FS, templateRoot string) (*FeatureFlagSet, error) {
+	flagsPath := path.Join(templateRoot, featureFlagsFilename)
+	content, err := fs.ReadFile(tmplFS, flagsPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return newFeatureFlagSet(nil), nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", flagsPath, err)
+	}
+
+	var defs []FeatureFlagDef
+	if err := yaml.Unmarshal(content, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", flagsPath, err)
+	}
+
+	set := newFeatureFlagSet(defs)
+	for _, def := range defs {
+		for _, dep := range def.DependsOn {
+			if _, ok := set.defs[dep]; !ok {
+				return nil, fmt.Errorf("%s: flag %q depends on unknown flag %q", flagsPath, def.Name, dep)
+			}
+		}
+	}
+
+	for name, def := range set.defs {
+		if def.Default {
+			if err := set.Enable(name); err != nil {
+				return nil, fmt.Errorf("%s: default-enabled flag %q: %w", flagsPath, name, err)
+			}
+		}
+	}
+	return set, nil
+}
+
+func newFeatureFlagSet(defs []FeatureFlagDef) *FeatureFlagSet {
+	set := &FeatureFlagSet{
+		defs:    make(map[string]FeatureFlagDef, len(defs)),
+		enabled: make(map[string]bool, len(defs)),
+		warned:  make(map[string]bool),
+	}
+	for _, def := range defs {
+		set.defs[def.Name] = def
+	}
+	return set
+}
+
+// Enable turns name on, after transitively enabling every flag it depends
+// on. It rejects an unrecognized name and refuses to enable a retired flag;
+// enabling an experimental flag is allowed but logged once, the same way
+// FeatureGate.Enabled warns once per Deprecated gate it's asked about.
+func (s *FeatureFlagSet) Enable(name string) error {
+	return s.enable(name, map[string]bool{})
+}
+
+func (s *FeatureFlagSet) enable(name string, visiting map[string]bool) error {
+	def, ok := s.defs[name]
+	if !ok {
+		return fmt.Errorf("unrecognized feature flag: %s", name)
+	}
+	if def.Stage == StageRetired {
+		return fmt.Errorf("feature flag %q is retired and can no longer be enabled", name)
+	}
+	if s.enabled[name] {
+		return nil
+	}
+	if visiting[name] {
+		return fmt.Errorf("feature flag %q has a circular dependency", name)
+	}
+	visiting[name] = true
+	for _, dep := range def.DependsOn {
+		if err := s.enable(dep, visiting); err != nil {
+			return fmt.Errorf("enabling %q: %w", name, err)
+		}
+	}
+
+	if def.Stage == StageExperimental && !s.warned[name] {
+		s.warned[name] = true
+		fmt.Printf("Warning: feature flag %q is experimental\n", name)
+	}
+	if def.Stage == StageDeprecated && !s.warned[name] {
+		s.warned[name] = true
+		msg := def.DeprecationWarning
+		if msg == "" {
+			msg = "no replacement given"
+		}
+		fmt.Printf("Warning: feature flag %q is deprecated: %s\n", name, msg)
+	}
+	s.enabled[name] = true
+	return nil
+}
+
+// Enabled reports whether category.feature is enabled, for
+// "{{ .Features.Enabled "security" "input_validation" }}" in templates.
+// An unrecognized category.feature simply reports false, matching the old
+// package-level featureEnabled's tolerance of features a template.json
+// predates.
+func (s *FeatureFlagSet) Enabled(category, feature string) bool {
+	if s == nil {
+		return false
+	}
+	name := category + "." + feature
+	if value, ok := s.enabled[name]; ok {
+		return value
+	}
+	def, ok := s.defs[name]
+	return ok && def.Default
+}
+
+// Names returns every declared flag name, sorted, for the --features
+// subcommand and tests.
+func (s *FeatureFlagSet) Names() []string {
+	names := make([]string, 0, len(s.defs))
+	for name := range s.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyFeatureFlagOverrides enables any flag named by a "--var name=true" or
+// --values-file entry, so adding a flag to features.yaml is enough to make
+// it settable from the CLI -- no new cobra flag, and so no code change,
+// unlike the fixed SecurityFeatures/ComplianceFrameworks/... flags it
+// replaces. Entries that don't name a known flag are left alone, since
+// extraVars/values also carry the template's ordinary template.json
+// variables through the very same mechanism.
+func applyFeatureFlagOverrides(flags *FeatureFlagSet, extraVars []string, values map[string]interface{}) error {
+	for name, value := range values {
+		if _, known := flags.defs[name]; !known {
+			continue
+		}
+		enabled, ok := value.(bool)
+		if !ok || !enabled {
+			continue
+		}
+		if err := flags.Enable(name); err != nil {
+			return err
+		}
+	}
+	for _, varDef := range extraVars {
+		name, raw, ok := strings.Cut(varDef, "=")
+		if !ok {
+			continue
+		}
+		if _, known := flags.defs[name]; !known {
+			continue
+		}
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil || !enabled {
+			continue
+		}
+		if err := flags.Enable(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
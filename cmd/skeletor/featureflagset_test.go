@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func featureFlagsFixture(yamlBody string) fstest.MapFS {
+	return fstest.MapFS{
+		"template/features.yaml": &fstest.MapFile{Data: []byte(yamlBody)},
+	}
+}
+
+func TestLoadFeatureFlagSet_MissingFileIsEmptySet(t *testing.T) {
+	set, err := LoadFeatureFlagSet(fstest.MapFS{}, "template")
+	require.NoError(t, err)
+	assert.Empty(t, set.Names())
+	assert.False(t, set.Enabled("security", "mfa"))
+}
+
+func TestLoadFeatureFlagSet_DefaultsAreEnabledOnLoad(t *testing.T) {
+	mockFS := featureFlagsFixture(`
+- name: security.mfa
+  stage: stable
+  default: true
+- name: security.rbac
+  stage: stable
+  default: false
+`)
+	set, err := LoadFeatureFlagSet(mockFS, "template")
+	require.NoError(t, err)
+	assert.True(t, set.Enabled("security", "mfa"))
+	assert.False(t, set.Enabled("security", "rbac"))
+}
+
+func TestFeatureFlagSet_EnableTransitivelyEnablesDependencies(t *testing.T) {
+	mockFS := featureFlagsFixture(`
+- name: auth.sso
+  stage: stable
+  default: false
+- name: auth.rbac
+  stage: stable
+  default: false
+  depends_on: ["auth.sso"]
+`)
+	set, err := LoadFeatureFlagSet(mockFS, "template")
+	require.NoError(t, err)
+
+	require.NoError(t, set.Enable("auth.rbac"))
+	assert.True(t, set.Enabled("auth", "rbac"))
+	assert.True(t, set.Enabled("auth", "sso"), "enabling auth.rbac should have transitively enabled its auth.sso dependency")
+}
+
+func TestFeatureFlagSet_EnableRejectsUnknownAndRetired(t *testing.T) {
+	mockFS := featureFlagsFixture(`
+- name: auth.session_management
+  stage: retired
+  default: false
+`)
+	set, err := LoadFeatureFlagSet(mockFS, "template")
+	require.NoError(t, err)
+
+	err = set.Enable("auth.session_management")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retired")
+
+	err = set.Enable("auth.does_not_exist")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unrecognized")
+}
+
+func TestLoadFeatureFlagSet_RejectsUnknownDependency(t *testing.T) {
+	mockFS := featureFlagsFixture(`
+- name: auth.rbac
+  stage: stable
+  default: false
+  depends_on: ["auth.vault"]
+`)
+	_, err := LoadFeatureFlagSet(mockFS, "template")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown flag "auth.vault"`)
+}
+
+func TestApplyFeatureFlagOverrides_FromVarsAndValues(t *testing.T) {
+	mockFS := featureFlagsFixture(`
+- name: security.mfa
+  stage: stable
+  default: false
+- name: security.rbac
+  stage: stable
+  default: false
+`)
+	set, err := LoadFeatureFlagSet(mockFS, "template")
+	require.NoError(t, err)
+
+	err = applyFeatureFlagOverrides(set, []string{"security.mfa=true", "MixinName=demo"}, map[string]interface{}{"security.rbac": true})
+	require.NoError(t, err)
+	assert.True(t, set.Enabled("security", "mfa"))
+	assert.True(t, set.Enabled("security", "rbac"))
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FeatureStage classifies a feature gate's maturity, mirroring Kubernetes'
+// component-base/featuregate Alpha/Beta/GA/Deprecated lifecycle.
+type FeatureStage string
+
+const (
+	Alpha      FeatureStage = "ALPHA"
+	Beta       FeatureStage = "BETA"
+	GA         FeatureStage = "GA"
+	Deprecated FeatureStage = "DEPRECATED"
+)
+
+// Meta-gates recognized by FeatureGate.Set, mirroring Kubernetes'
+// AllAlpha/AllBeta gates: setting one flips every currently-registered gate
+// at that stage (unless the gate is LockToDefault).
+const (
+	allAlphaGate = "AllAlpha"
+	allBetaGate  = "AllBeta"
+)
+
+// featureNamePattern is the crowdsec fflag-style naming rule for feature
+// gates: lowercase segments of letters, digits, underscores and dots.
+var featureNamePattern = regexp.MustCompile(`^[a-z0-9_.]+$`)
+
+// defaultEnvPrefix is prepended to a gate's upper-cased, dot-to-underscore
+// name to derive its environment variable, e.g. "security.rbac" becomes
+// SKELETOR_FEATURE_SECURITY_RBAC.
+const defaultEnvPrefix = "SKELETOR_FEATURE_"
+
+// FeatureSource identifies which layer of the precedence chain last set a
+// gate's value: built-in default -> template.json FeatureToggles -> env var
+// -> CLI --feature-gates. Each layer only overrides a gate already set by an
+// earlier (lower-ranked) layer, never a later one.
+type FeatureSource string
+
+const (
+	SourceDefault  FeatureSource = "default"
+	SourceTemplate FeatureSource = "template.json"
+	SourceEnv      FeatureSource = "env"
+	SourceCLI      FeatureSource = "--feature-gates"
+)
+
+// sourceRank orders FeatureSource values along the precedence chain; higher
+// ranks win when two layers disagree.
+var sourceRank = map[FeatureSource]int{
+	SourceDefault:  0,
+	SourceTemplate: 1,
+	SourceEnv:      2,
+	SourceCLI:      3,
+}
+
+// FeatureStatus is one row of the registry table printed by the --features
+// subcommand, similar to `cscli features list`.
+type FeatureStatus struct {
+	Name        string
+	Stage       FeatureStage
+	Default     bool
+	Current     bool
+	Source      FeatureSource
+	Description string
+}
+
+// FeatureSpec is the registration metadata for one feature gate.
+type FeatureSpec struct {
+	Default       bool
+	LockToDefault bool
+	PreRelease    FeatureStage
+	Description   string
+}
+
+// FeatureGate is a registry of named, staged feature toggles, in the spirit
+// of Kubernetes' component-base/featuregate. It implements pflag.Value so it
+// can be bound directly to a --feature-gates flag.
+//
+// A gate's effective value follows a crowdsec fflag-style precedence chain,
+// lowest to highest: the FeatureSpec.Default registered at startup, then a
+// template.json FeatureToggles block (SetFromTemplate), then an env var
+// under EnvPrefix (LoadFromEnv), then the CLI --feature-gates flag (Set /
+// SetFromMap). Each layer only overrides a value already set by an earlier
+// layer, never a later one, regardless of the order the layers are applied
+// in.
+type FeatureGate struct {
+	mu        sync.RWMutex
+	known     map[string]FeatureSpec
+	enabled   map[string]bool          // explicit overrides, from any source
+	source    map[string]FeatureSource // source that set the matching enabled entry
+	warned    map[string]bool          // Deprecated gates already warned about
+	EnvPrefix string                   // prefix for env-var overrides, e.g. SKELETOR_FEATURE_
+}
+
+// NewFeatureGate returns an empty registry with the default env prefix.
+func NewFeatureGate() *FeatureGate {
+	return &FeatureGate{
+		known:     make(map[string]FeatureSpec),
+		enabled:   make(map[string]bool),
+		source:    make(map[string]FeatureSource),
+		warned:    make(map[string]bool),
+		EnvPrefix: defaultEnvPrefix,
+	}
+}
+
+// DefaultFeatureGate is the process-wide registry bound to the
+// --feature-gates flag and consulted by FeatureToggles.IsFeatureEnabledStrict.
+var DefaultFeatureGate = NewFeatureGate()
+
+func init() {
+	builtinFeatureGates := map[string]FeatureSpec{
+		"security.input_validation":       {Default: false, PreRelease: Beta, Description: "Input validation middleware in generated mixins"},
+		"security.rate_limiting":          {Default: false, PreRelease: Beta, Description: "Rate limiting middleware in generated mixins"},
+		"security.secure_headers":         {Default: false, PreRelease: Beta, Description: "Secure HTTP headers in generated mixins"},
+		"security.vulnerability_scanning": {Default: false, PreRelease: Alpha, Description: "govulncheck wiring in generated mixins"},
+		"security.policy_enforcement":     {Default: false, PreRelease: Alpha, Description: "Policy enforcement scaffolding"},
+		"compliance.soc2":                 {Default: false, PreRelease: Beta, Description: "SOC2 compliance scaffolding"},
+		"compliance.gdpr":                 {Default: false, PreRelease: Beta, Description: "GDPR compliance scaffolding"},
+		"compliance.hipaa":                {Default: false, PreRelease: Alpha, Description: "HIPAA compliance scaffolding"},
+		"compliance.pci_dss":              {Default: false, PreRelease: Alpha, Description: "PCI-DSS compliance scaffolding"},
+		"auth.rbac":                       {Default: false, PreRelease: Beta, Description: "RBAC scaffolding"},
+		"auth.ldap":                       {Default: false, PreRelease: Alpha, Description: "LDAP integration scaffolding"},
+		"auth.sso":                        {Default: false, PreRelease: Beta, Description: "SSO integration scaffolding"},
+		"auth.mfa":                        {Default: false, PreRelease: Alpha, Description: "MFA scaffolding"},
+		"auth.vault":                      {Default: false, PreRelease: Alpha, Description: "HashiCorp Vault integration scaffolding"},
+		"auth.session_management":         {Default: false, PreRelease: Deprecated, Description: "Legacy in-process session management; superseded by auth.sso"},
+		"observability.apm":               {Default: false, PreRelease: Beta, Description: "APM instrumentation scaffolding"},
+		"observability.infrastructure":    {Default: false, PreRelease: Beta, Description: "Infrastructure metrics scaffolding"},
+		"observability.custom_metrics":    {Default: false, PreRelease: Alpha, Description: "Custom metrics scaffolding"},
+		"observability.health_checks":     {Default: true, LockToDefault: true, PreRelease: GA, Description: "Health check endpoints; always generated"},
+		"observability.opentelemetry":     {Default: false, PreRelease: Beta, Description: "OpenTelemetry tracing/metrics scaffolding"},
+		"observability.audit_logging":     {Default: false, PreRelease: Beta, Description: "Audit logging scaffolding"},
+		"observability.tracing":           {Default: false, PreRelease: Alpha, Description: "Distributed tracing scaffolding"},
+	}
+	for name, spec := range builtinFeatureGates {
+		if err := DefaultFeatureGate.Register(name, spec); err != nil {
+			panic(err) // programmer error: conflicting registration of a builtin gate
+		}
+	}
+}
+
+// Register adds a feature gate. The name must be lowercase letters, digits,
+// underscores and dots (the crowdsec fflag convention), since it is also
+// used to derive the gate's env var. It returns an error if name is already
+// registered with a different spec.
+func (f *FeatureGate) Register(name string, spec FeatureSpec) error {
+	if !featureNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid feature gate name %q: must match %s", name, featureNamePattern.String())
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if existing, ok := f.known[name]; ok && existing != spec {
+		return fmt.Errorf("feature gate %q already registered with a different spec", name)
+	}
+	f.known[name] = spec
+	return nil
+}
+
+// envVarName returns the environment variable that overrides name, e.g.
+// "security.rbac" -> "SKELETOR_FEATURE_SECURITY_RBAC".
+func (f *FeatureGate) envVarName(name string) string {
+	prefix := f.EnvPrefix
+	if prefix == "" {
+		prefix = defaultEnvPrefix
+	}
+	return prefix + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
+
+// setFromSource records value for name if source's rank is at least as high
+// as whatever last set it, so a lower-precedence layer can never clobber a
+// higher one applied earlier. Callers must hold f.mu.
+func (f *FeatureGate) setFromSource(name string, value bool, source FeatureSource) error {
+	spec, ok := f.known[name]
+	if !ok {
+		return fmt.Errorf("unrecognized feature gate: %s", name)
+	}
+	if spec.LockToDefault && value != spec.Default {
+		return fmt.Errorf("cannot set feature gate %q to %t, locked to %t", name, value, spec.Default)
+	}
+	if existing, ok := f.source[name]; ok && sourceRank[existing] > sourceRank[source] {
+		return nil
+	}
+	f.enabled[name] = value
+	f.source[name] = source
+	return nil
+}
+
+// Enabled reports whether name is enabled: an explicit override if Set or
+// SetFromMap provided one, otherwise the gate's registered default. Unlike
+// FeatureToggles.IsFeatureEnabled, it returns an error for unrecognized
+// gates instead of silently reporting false, and prints a one-time warning
+// the first time a Deprecated gate is read.
+func (f *FeatureGate) Enabled(name string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spec, ok := f.known[name]
+	if !ok {
+		return false, fmt.Errorf("unrecognized feature gate: %s", name)
+	}
+	if spec.PreRelease == Deprecated && !f.warned[name] {
+		f.warned[name] = true
+		fmt.Printf("Warning: feature gate %q is deprecated: %s\n", name, spec.Description)
+	}
+	if value, ok := f.enabled[name]; ok {
+		return value, nil
+	}
+	return spec.Default, nil
+}
+
+// SetFromMap applies explicit true/false overrides from the CLI
+// --feature-gates flag. It returns an "unrecognized feature gate" error if
+// any name isn't registered, or a locked-gate error if a LockToDefault gate
+// is set to anything but its default.
+func (f *FeatureGate) SetFromMap(overrides map[string]bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name, value := range overrides {
+		if err := f.setFromSource(name, value, SourceCLI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFromTemplate applies the feature_toggles block of a loaded
+// template.json, ranking its overrides above the built-in defaults but
+// below env vars and the CLI --feature-gates flag. Unrecognized gates and
+// categories in toggles are ignored rather than erroring, since a
+// template.json may legitimately predate a gate added later.
+func (f *FeatureGate) SetFromTemplate(toggles *FeatureToggles) error {
+	if toggles == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range f.known {
+		category, feature, ok := strings.Cut(name, ".")
+		if !ok {
+			continue
+		}
+		if !toggles.IsFeatureEnabled(category, feature) {
+			continue
+		}
+		if err := f.setFromSource(name, true, SourceTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFromEnv applies SKELETOR_FEATURE_<NAME> overrides (with EnvPrefix
+// configurable per registry) for every registered gate, ranking them above
+// template.json and the built-in default but below the CLI --feature-gates
+// flag. Unset env vars are skipped; a set value that doesn't parse as a bool
+// is an error.
+func (f *FeatureGate) LoadFromEnv() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range f.known {
+		raw, ok := os.LookupEnv(f.envVarName(name))
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for env var %s: %w", raw, f.envVarName(name), err)
+		}
+		if err := f.setFromSource(name, value, SourceEnv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set parses a comma-separated "name=true,name2=false" list, as accepted by
+// Kubernetes' --feature-gates flag, plus the AllAlpha/AllBeta meta-gates
+// which flip every currently-registered gate at that stage (skipping any
+// that are LockToDefault). It satisfies pflag.Value.
+func (f *FeatureGate) Set(value string) error {
+	overrides := make(map[string]bool)
+	var metaAlpha, metaBeta *bool
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("missing '=' in feature gate %q", pair)
+		}
+		name = strings.TrimSpace(name)
+		boolValue, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid value %q for feature gate %q: %w", raw, name, err)
+		}
+		switch name {
+		case allAlphaGate:
+			metaAlpha = &boolValue
+		case allBetaGate:
+			metaBeta = &boolValue
+		default:
+			overrides[name] = boolValue
+		}
+	}
+
+	f.mu.Lock()
+	if metaAlpha != nil {
+		for name, spec := range f.known {
+			if spec.PreRelease == Alpha && !spec.LockToDefault {
+				f.enabled[name] = *metaAlpha
+				f.source[name] = SourceCLI
+			}
+		}
+	}
+	if metaBeta != nil {
+		for name, spec := range f.known {
+			if spec.PreRelease == Beta && !spec.LockToDefault {
+				f.enabled[name] = *metaBeta
+				f.source[name] = SourceCLI
+			}
+		}
+	}
+	f.mu.Unlock()
+
+	return f.SetFromMap(overrides)
+}
+
+// String renders the registry's current explicit overrides, satisfying
+// pflag.Value.
+func (f *FeatureGate) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.enabled))
+	for name := range f.enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, f.enabled[name]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Type satisfies pflag.Value.
+func (f *FeatureGate) Type() string {
+	return "featureGate"
+}
+
+// KnownFeatures renders every registered gate as "name=default (STAGE) - description",
+// one per line, for use in --help text.
+func (f *FeatureGate) KnownFeatures() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.known))
+	for name := range f.known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		spec := f.known[name]
+		lines = append(lines, fmt.Sprintf("%s=%t (%s) - %s", name, spec.Default, spec.PreRelease, spec.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetEnabledFeatures returns every registered gate's current effective
+// state, sorted by name, labeled with whichever layer of the precedence
+// chain (built-in default -> template.json -> env var -> CLI
+// --feature-gates) last set it. It backs the --features subcommand's
+// registry table, similar to `cscli features list`.
+func (f *FeatureGate) GetEnabledFeatures() []FeatureStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.known))
+	for name := range f.known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	statuses := make([]FeatureStatus, 0, len(names))
+	for _, name := range names {
+		spec := f.known[name]
+		current := spec.Default
+		source := SourceDefault
+		if value, ok := f.enabled[name]; ok {
+			current = value
+			source = f.source[name]
+		}
+		statuses = append(statuses, FeatureStatus{
+			Name:        name,
+			Stage:       spec.PreRelease,
+			Default:     spec.Default,
+			Current:     current,
+			Source:      source,
+			Description: spec.Description,
+		})
+	}
+	return statuses
+}
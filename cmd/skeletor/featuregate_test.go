@@ -0,0 +1,184 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFeatureGate() *FeatureGate {
+	gate := NewFeatureGate()
+	_ = gate.Register("alpha.one", FeatureSpec{Default: false, PreRelease: Alpha})
+	_ = gate.Register("alpha.two", FeatureSpec{Default: false, PreRelease: Alpha})
+	_ = gate.Register("beta.one", FeatureSpec{Default: true, PreRelease: Beta})
+	_ = gate.Register("ga.locked", FeatureSpec{Default: true, LockToDefault: true, PreRelease: GA})
+	_ = gate.Register("legacy.one", FeatureSpec{Default: true, PreRelease: Deprecated, Description: "use ga.locked instead"})
+	return gate
+}
+
+func TestFeatureGate_Enabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		gateName    string
+		expectValue bool
+		expectErr   bool
+	}{
+		{name: "registered default false", gateName: "alpha.one", expectValue: false},
+		{name: "registered default true", gateName: "beta.one", expectValue: true},
+		{name: "unrecognized gate errors", gateName: "does.not.exist", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := newTestFeatureGate()
+			value, err := gate.Enabled(tt.gateName)
+			if tt.expectErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unrecognized feature gate")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectValue, value)
+		})
+	}
+}
+
+func TestFeatureGate_Set(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		check     string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "explicit override", value: "alpha.one=true", check: "alpha.one", expected: true},
+		{name: "AllAlpha flips alpha gates", value: "AllAlpha=true", check: "alpha.two", expected: true},
+		{name: "AllAlpha does not touch beta gates", value: "AllAlpha=true", check: "beta.one", expected: true},
+		{name: "locked gate rejects non-default", value: "ga.locked=false", expectErr: true},
+		{name: "unrecognized gate errors", value: "nope=true", expectErr: true},
+		{name: "malformed pair errors", value: "alpha.one", expectErr: true},
+		{name: "malformed value errors", value: "alpha.one=maybe", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := newTestFeatureGate()
+			err := gate.Set(tt.value)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			value, err := gate.Enabled(tt.check)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestFeatureGate_SetFromMap(t *testing.T) {
+	gate := newTestFeatureGate()
+
+	err := gate.SetFromMap(map[string]bool{"alpha.one": true})
+	require.NoError(t, err)
+	value, err := gate.Enabled("alpha.one")
+	require.NoError(t, err)
+	assert.True(t, value)
+
+	err = gate.SetFromMap(map[string]bool{"unknown": true})
+	require.Error(t, err)
+
+	err = gate.SetFromMap(map[string]bool{"ga.locked": false})
+	require.Error(t, err)
+}
+
+func TestFeatureGate_Register_ConflictingSpec(t *testing.T) {
+	gate := NewFeatureGate()
+	require.NoError(t, gate.Register("one", FeatureSpec{Default: false, PreRelease: Alpha}))
+	require.NoError(t, gate.Register("one", FeatureSpec{Default: false, PreRelease: Alpha})) // identical re-registration is fine
+	err := gate.Register("one", FeatureSpec{Default: true, PreRelease: Alpha})
+	require.Error(t, err)
+}
+
+func TestDefaultFeatureGate_HasBuiltins(t *testing.T) {
+	value, err := DefaultFeatureGate.Enabled("security.input_validation")
+	require.NoError(t, err)
+	assert.False(t, value)
+}
+
+func TestFeatureGate_Register_InvalidName(t *testing.T) {
+	gate := NewFeatureGate()
+	err := gate.Register("Security.RBAC", FeatureSpec{Default: false, PreRelease: Alpha})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid feature gate name")
+}
+
+func TestFeatureGate_SetFromTemplate(t *testing.T) {
+	gate := newTestFeatureGate()
+	_ = gate.Register("security.rbac", FeatureSpec{Default: false, PreRelease: Beta})
+
+	toggles := &FeatureToggles{Security: &SecurityFeatures{Enabled: true, RateLimiting: true}}
+	require.NoError(t, gate.SetFromTemplate(toggles))
+
+	value, err := gate.Enabled("security.rbac")
+	require.NoError(t, err)
+	assert.False(t, value, "toggles block did not enable security.rbac")
+}
+
+func TestFeatureGate_EnvOverridesTemplateButNotCLI(t *testing.T) {
+	gate := NewFeatureGate()
+	require.NoError(t, gate.Register("auth.rbac", FeatureSpec{Default: false, PreRelease: Beta}))
+
+	require.NoError(t, gate.SetFromTemplate(&FeatureToggles{Auth: &AuthFeatures{Enabled: true, RBAC: true}}))
+	value, err := gate.Enabled("auth.rbac")
+	require.NoError(t, err)
+	assert.True(t, value, "template.json toggle should enable auth.rbac")
+
+	envName := gate.envVarName("auth.rbac")
+	t.Setenv(envName, "false")
+	require.NoError(t, gate.LoadFromEnv())
+	value, err = gate.Enabled("auth.rbac")
+	require.NoError(t, err)
+	assert.False(t, value, "env var should override the template.json value")
+
+	require.NoError(t, gate.Set("auth.rbac=true"))
+	value, err = gate.Enabled("auth.rbac")
+	require.NoError(t, err)
+	assert.True(t, value, "CLI --feature-gates should override the env var")
+
+	// A later, lower-precedence env reload must not clobber the CLI value.
+	t.Setenv(envName, "false")
+	require.NoError(t, gate.LoadFromEnv())
+	value, err = gate.Enabled("auth.rbac")
+	require.NoError(t, err)
+	assert.True(t, value, "env reload should not override a value already set by the CLI flag")
+}
+
+func TestFeatureGate_LoadFromEnv_InvalidValue(t *testing.T) {
+	gate := NewFeatureGate()
+	require.NoError(t, gate.Register("auth.rbac", FeatureSpec{Default: false, PreRelease: Beta}))
+	t.Setenv(gate.envVarName("auth.rbac"), "not-a-bool")
+	err := gate.LoadFromEnv()
+	require.Error(t, err)
+}
+
+func TestFeatureGate_GetEnabledFeatures(t *testing.T) {
+	gate := newTestFeatureGate()
+	require.NoError(t, gate.Set("alpha.one=true"))
+
+	statuses := gate.GetEnabledFeatures()
+	require.Len(t, statuses, 5)
+
+	byName := make(map[string]FeatureStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+
+	assert.Equal(t, FeatureStatus{
+		Name: "alpha.one", Stage: Alpha, Default: false, Current: true, Source: SourceCLI,
+	}, byName["alpha.one"])
+	assert.Equal(t, FeatureStatus{
+		Name: "beta.one", Stage: Beta, Default: true, Current: true, Source: SourceDefault,
+	}, byName["beta.one"])
+}
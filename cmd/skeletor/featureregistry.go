@@ -0,0 +1,117 @@
+package main
+
+import "sort"
+
+// FeatureProvider lets a package other than this one add a feature category
+// -- "cost", "networking", whatever a downstream scaffold needs -- without
+// editing the switch statements in FeatureToggles.IsFeatureEnabled and
+// GetEnabledFeatures. Built-in providers for security/compliance/auth/
+// observability register themselves in this file's init(); third-party
+// providers register via RegisterFeatureProvider.
+type FeatureProvider interface {
+	// Category is the name used in FeatureToggles.IsFeatureEnabled's
+	// category argument, e.g. "security".
+	Category() string
+	// IsEnabled reports whether feature is enabled for this category in ft.
+	IsEnabled(ft *FeatureToggles, feature string) bool
+	// ListEnabled returns every enabled feature name in this category for ft.
+	ListEnabled(ft *FeatureToggles) []string
+}
+
+// FeatureRegistry is a keyed lookup of FeatureProvider by category name.
+// It is safe to read concurrently once registration (normally done from
+// init functions at program startup) has finished.
+type FeatureRegistry struct {
+	providers map[string]FeatureProvider
+}
+
+// NewFeatureRegistry returns an empty registry.
+func NewFeatureRegistry() *FeatureRegistry {
+	return &FeatureRegistry{providers: make(map[string]FeatureProvider)}
+}
+
+// Register adds p to the registry, replacing any existing provider for the
+// same category.
+func (r *FeatureRegistry) Register(p FeatureProvider) {
+	r.providers[p.Category()] = p
+}
+
+// Lookup returns the provider registered for category, if any.
+func (r *FeatureRegistry) Lookup(category string) (FeatureProvider, bool) {
+	p, ok := r.providers[category]
+	return p, ok
+}
+
+// Categories returns every registered category name, sorted for stable
+// output (e.g. in `skeletor features`).
+func (r *FeatureRegistry) Categories() []string {
+	categories := make([]string, 0, len(r.providers))
+	for category := range r.providers {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// defaultFeatureRegistry is the registry IsFeatureEnabled consults for every
+// category, and GetEnabledFeatures for categories beyond the four built-ins.
+var defaultFeatureRegistry = NewFeatureRegistry()
+
+// RegisterFeatureProvider adds p to the default registry so
+// FeatureToggles.IsFeatureEnabled and GetEnabledFeatures recognize its
+// category. Call it from an init function, before any FeatureToggles
+// methods run.
+func RegisterFeatureProvider(p FeatureProvider) {
+	defaultFeatureRegistry.Register(p)
+}
+
+// securityFeatureProvider, complianceFeatureProvider, authFeatureProvider and
+// observabilityFeatureProvider adapt the four built-in categories to
+// FeatureProvider so they go through the same registry-based dispatch as
+// third-party categories registered with RegisterFeatureProvider.
+type securityFeatureProvider struct{}
+
+func (securityFeatureProvider) Category() string { return "security" }
+func (securityFeatureProvider) IsEnabled(ft *FeatureToggles, feature string) bool {
+	return ft.isSecurityFeatureEnabled(feature)
+}
+func (securityFeatureProvider) ListEnabled(ft *FeatureToggles) []string {
+	return ft.listSecurityFeatures()
+}
+
+type complianceFeatureProvider struct{}
+
+func (complianceFeatureProvider) Category() string { return "compliance" }
+func (complianceFeatureProvider) IsEnabled(ft *FeatureToggles, feature string) bool {
+	return ft.isComplianceFeatureEnabled(feature)
+}
+func (complianceFeatureProvider) ListEnabled(ft *FeatureToggles) []string {
+	return ft.listComplianceFeatures()
+}
+
+type authFeatureProvider struct{}
+
+func (authFeatureProvider) Category() string { return "auth" }
+func (authFeatureProvider) IsEnabled(ft *FeatureToggles, feature string) bool {
+	return ft.isAuthFeatureEnabled(feature)
+}
+func (authFeatureProvider) ListEnabled(ft *FeatureToggles) []string {
+	return ft.listAuthFeatures()
+}
+
+type observabilityFeatureProvider struct{}
+
+func (observabilityFeatureProvider) Category() string { return "observability" }
+func (observabilityFeatureProvider) IsEnabled(ft *FeatureToggles, feature string) bool {
+	return ft.isObservabilityFeatureEnabled(feature)
+}
+func (observabilityFeatureProvider) ListEnabled(ft *FeatureToggles) []string {
+	return ft.listObservabilityFeatures()
+}
+
+func init() {
+	RegisterFeatureProvider(securityFeatureProvider{})
+	RegisterFeatureProvider(complianceFeatureProvider{})
+	RegisterFeatureProvider(authFeatureProvider{})
+	RegisterFeatureProvider(observabilityFeatureProvider{})
+}
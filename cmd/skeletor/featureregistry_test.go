@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type costFeatureProvider struct{}
+
+func (costFeatureProvider) Category() string { return "cost" }
+func (costFeatureProvider) IsEnabled(ft *FeatureToggles, feature string) bool {
+	return feature == "budget_alerts"
+}
+func (costFeatureProvider) ListEnabled(ft *FeatureToggles) []string {
+	return []string{"budget_alerts"}
+}
+
+func TestFeatureRegistry_RegisterAndLookup(t *testing.T) {
+	registry := NewFeatureRegistry()
+	registry.Register(costFeatureProvider{})
+
+	provider, ok := registry.Lookup("cost")
+	assert.True(t, ok)
+	assert.Equal(t, "cost", provider.Category())
+
+	_, ok = registry.Lookup("networking")
+	assert.False(t, ok)
+}
+
+func TestFeatureRegistry_Categories_Sorted(t *testing.T) {
+	registry := NewFeatureRegistry()
+	registry.Register(observabilityFeatureProvider{})
+	registry.Register(authFeatureProvider{})
+	registry.Register(costFeatureProvider{})
+
+	assert.Equal(t, []string{"auth", "cost", "observability"}, registry.Categories())
+}
+
+func TestDefaultFeatureRegistry_HasBuiltinProviders(t *testing.T) {
+	for _, category := range []string{"security", "compliance", "auth", "observability"} {
+		_, ok := defaultFeatureRegistry.Lookup(category)
+		assert.True(t, ok, "expected built-in provider for %s", category)
+	}
+}
+
+func TestRegisterFeatureProvider_ExtendsIsFeatureEnabledAndGetEnabledFeatures(t *testing.T) {
+	RegisterFeatureProvider(costFeatureProvider{})
+	t.Cleanup(func() { delete(defaultFeatureRegistry.providers, "cost") })
+
+	toggles := &FeatureToggles{}
+	assert.True(t, toggles.IsFeatureEnabled("cost", "budget_alerts"))
+	assert.False(t, toggles.IsFeatureEnabled("cost", "other"))
+
+	enabled := toggles.GetEnabledFeatures()
+	assert.Equal(t, []string{"budget_alerts"}, enabled["cost"])
+}
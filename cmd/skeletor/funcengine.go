@@ -0,0 +1,251 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Engine renders a TemplateConfig's files to disk (or dry-run-simulates it)
+// via CreateMixin, parsing every .tmpl file and conditional-path expression
+// with a merged FuncMap: the package's built-in funcMap, then whichever
+// builtinHelperFuncs names config.Functions enables, then whatever the
+// embedder has registered via RegisterFunc. The zero value is ready to use;
+// this mirrors Traefik's BaseProvider, which lets each provider supply its
+// own funcMap to the template engine instead of forking it.
+type Engine struct {
+	custom template.FuncMap
+}
+
+// RegisterFunc registers fn under name so every template e renders can call
+// it, alongside the package's built-in funcMap and config.Functions' built-in
+// helpers. fn must be a valid text/template function value: one return
+// value, or two the second of which is error.
+func (e *Engine) RegisterFunc(name string, fn any) error {
+	if name == "" {
+		return fmt.Errorf("function name must not be empty")
+	}
+	if err := validateTemplateFunc(fn); err != nil {
+		return fmt.Errorf("invalid template function %q: %w", name, err)
+	}
+	if e.custom == nil {
+		e.custom = template.FuncMap{}
+	}
+	e.custom[name] = fn
+	return nil
+}
+
+// FuncMap returns the FuncMap CreateMixin parses every template with for
+// config: the package's built-in funcMap, then config.Functions' built-in
+// helpers, then e's RegisterFunc-registered functions -- each layer able to
+// override names from the one before.
+func (e *Engine) FuncMap(config *TemplateConfig) (template.FuncMap, error) {
+	merged := make(template.FuncMap, len(funcMap)+len(config.Functions)+len(e.custom))
+	for name, fn := range funcMap {
+		merged[name] = fn
+	}
+	for _, name := range config.Functions {
+		fn, ok := builtinHelperFuncs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q in template.json \"functions\" (available: %s)", name, strings.Join(availableFunctionNames(builtinHelperFuncs), ", "))
+		}
+		merged[name] = fn
+	}
+	for name, fn := range e.custom {
+		merged[name] = fn
+	}
+	return merged, nil
+}
+
+// validateTemplateFunc reports whether fn could be installed into a
+// text/template.FuncMap: it must be a function returning one value, or two
+// values the second of which is error.
+func validateTemplateFunc(fn any) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fmt.Errorf("must be a function, got %T", fn)
+	}
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	switch t := v.Type(); t.NumOut() {
+	case 1:
+		return nil
+	case 2:
+		if !t.Out(1).Implements(errType) {
+			return fmt.Errorf("second return value must be error, got %s", t.Out(1))
+		}
+		return nil
+	default:
+		return fmt.Errorf("must return one value, or two values the second of which is error")
+	}
+}
+
+// wrapUnknownFunctionError enriches a text/template "function ... not
+// defined" parse error with the names available in funcs, so a template
+// author doesn't have to go spelunking through config.Functions and the
+// built-in funcMap to find the typo.
+func wrapUnknownFunctionError(err error, funcs template.FuncMap) error {
+	if err == nil || !strings.Contains(err.Error(), "not defined") {
+		return err
+	}
+	return fmt.Errorf("%w (available functions: %s)", err, strings.Join(availableFunctionNames(funcs), ", "))
+}
+
+// availableFunctionNames returns funcs' keys, sorted, for error messages.
+func availableFunctionNames(funcs template.FuncMap) []string {
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinHelperFuncs is the catalog of optional helpers a template.json can
+// add to its FuncMap by listing their names in TemplateConfig.Functions.
+// Unlike funcMap, these are opt-in: a template that doesn't ask for
+// "sha256" shouldn't see it show up by surprise.
+var builtinHelperFuncs = template.FuncMap{
+	"toKebab":        toKebabCase,
+	"toSnake":        toSnakeCase,
+	"toPascal":       toPascalCase,
+	"sha256":         sha256Hex,
+	"readEnv":        os.Getenv,
+	"defaultIfEmpty": defaultValue,
+
+	// Sprig/gomplate-style helpers, under the spellings those libraries use
+	// rather than the toKebab/toSnake/toPascal ones above, for templates
+	// ported from or modeled on them.
+	"upper":     strings.ToUpper,
+	"trim":      strings.TrimSpace,
+	"replace":   func(old, repl, src string) string { return strings.ReplaceAll(src, old, repl) },
+	"title":     toTitleCase,
+	"camelcase": toCamelCase,
+	"snakecase": toSnakeCase,
+	"kebabcase": toKebabCase,
+	"hasKey":    hasKey,
+	"list":      list,
+	"dict":      dict,
+	"env":       os.Getenv,
+}
+
+// toKebabCase converts name (camelCase, PascalCase, snake_case, or
+// space/hyphen separated) to kebab-case, e.g. "MixinName" -> "mixin-name".
+func toKebabCase(name string) string {
+	return strings.Join(splitIdentifierWords(name), "-")
+}
+
+// toSnakeCase converts name to snake_case, e.g. "MixinName" -> "mixin_name".
+func toSnakeCase(name string) string {
+	return strings.Join(splitIdentifierWords(name), "_")
+}
+
+// toPascalCase converts name to PascalCase, e.g. "mixin-name" -> "MixinName".
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, word := range splitIdentifierWords(name) {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
+}
+
+// toCamelCase converts name to lowerCamelCase, e.g. "mixin-name" -> "mixinName".
+func toCamelCase(name string) string {
+	pascal := toPascalCase(name)
+	if pascal == "" {
+		return ""
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+// toTitleCase title-cases each word of name, e.g. "mixin-name" -> "Mixin Name".
+func toTitleCase(name string) string {
+	words := splitIdentifierWords(name)
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// hasKey reports whether dict contains key, for templates that branch on
+// optional map entries, e.g. {{ if hasKey .Features "auth" }}.
+func hasKey(dict map[string]interface{}, key string) bool {
+	_, ok := dict[key]
+	return ok
+}
+
+// list collects its arguments into a slice, Sprig-style, e.g.
+// {{ range list "a" "b" "c" }}.
+func list(items ...interface{}) []interface{} {
+	return items
+}
+
+// dict builds a map from alternating key/value arguments, Sprig-style, e.g.
+// {{ $d := dict "name" .MixinName "level" .ComplianceLevel }}. Keys must be
+// strings and there must be an even number of arguments.
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict requires an even number of arguments, got %d", len(pairs))
+	}
+	d := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %d must be a string, got %T", i/2, pairs[i])
+		}
+		d[key] = pairs[i+1]
+	}
+	return d, nil
+}
+
+// splitIdentifierWords splits name into lowercase words on underscore,
+// hyphen and space separators, and on camelCase/PascalCase word boundaries.
+// It is the shared logic behind toKebabCase, toSnakeCase and toPascalCase.
+func splitIdentifierWords(name string) []string {
+	var words []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, strings.ToLower(current.String()))
+			current.Reset()
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case unicode.IsUpper(r):
+			prevLowerOrDigit := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			startOfAcronymTail := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLowerOrDigit || startOfAcronymTail {
+				flush()
+			}
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return words
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of s.
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
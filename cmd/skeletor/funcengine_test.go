@@ -0,0 +1,189 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_FuncMap_Default(t *testing.T) {
+	e := &Engine{}
+	config := &TemplateConfig{}
+
+	funcs, err := e.FuncMap(config)
+	require.NoError(t, err)
+	assert.Contains(t, funcs, "lower")
+	assert.NotContains(t, funcs, "toKebab", "builtinHelperFuncs should be opt-in via config.Functions")
+}
+
+func TestEngine_FuncMap_EnablesRequestedBuiltins(t *testing.T) {
+	e := &Engine{}
+	config := &TemplateConfig{Functions: []string{"toKebab", "sha256"}}
+
+	funcs, err := e.FuncMap(config)
+	require.NoError(t, err)
+	assert.Contains(t, funcs, "toKebab")
+	assert.Contains(t, funcs, "sha256")
+	assert.NotContains(t, funcs, "readEnv")
+}
+
+func TestEngine_FuncMap_UnknownBuiltinErrors(t *testing.T) {
+	e := &Engine{}
+	config := &TemplateConfig{Functions: []string{"toWhistle"}}
+
+	_, err := e.FuncMap(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"toWhistle"`)
+	assert.Contains(t, err.Error(), "toKebab")
+}
+
+func TestEngine_RegisterFunc(t *testing.T) {
+	e := &Engine{}
+	require.NoError(t, e.RegisterFunc("shout", func(s string) string { return s + "!" }))
+
+	funcs, err := e.FuncMap(&TemplateConfig{})
+	require.NoError(t, err)
+	shout, ok := funcs["shout"].(func(string) string)
+	require.True(t, ok)
+	assert.Equal(t, "hi!", shout("hi"))
+}
+
+func TestEngine_RegisterFunc_RejectsBadSignature(t *testing.T) {
+	e := &Engine{}
+	err := e.RegisterFunc("bad", func() (string, string) { return "", "" })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad")
+}
+
+func TestEngine_RegisterFunc_EmptyName(t *testing.T) {
+	e := &Engine{}
+	assert.Error(t, e.RegisterFunc("", func() string { return "" }))
+}
+
+func TestCaseConversionHelpers(t *testing.T) {
+	cases := []struct {
+		in               string
+		kebab, snake, pc string
+	}{
+		{"MixinName", "mixin-name", "mixin_name", "MixinName"},
+		{"mixin_name", "mixin-name", "mixin_name", "MixinName"},
+		{"mixin-name", "mixin-name", "mixin_name", "MixinName"},
+		{"HTTPServer", "http-server", "http_server", "HttpServer"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.kebab, toKebabCase(tc.in), "toKebab(%q)", tc.in)
+		assert.Equal(t, tc.snake, toSnakeCase(tc.in), "toSnake(%q)", tc.in)
+		assert.Equal(t, tc.pc, toPascalCase(tc.in), "toPascal(%q)", tc.in)
+	}
+}
+
+func TestSha256Hex(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sha256Hex("hello"))
+}
+
+func TestSprigStyleHelpers(t *testing.T) {
+	assert.Equal(t, "mixinName", toCamelCase("mixin-name"))
+	assert.Equal(t, "Mixin Name", toTitleCase("mixin_name"))
+
+	d, err := dict("a", 1, "b", "two")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": "two"}, d)
+	_, err = dict("a")
+	require.Error(t, err)
+	_, err = dict(1, "a")
+	require.Error(t, err)
+
+	assert.Equal(t, []interface{}{"a", "b", "c"}, list("a", "b", "c"))
+
+	assert.True(t, hasKey(map[string]interface{}{"auth": true}, "auth"))
+	assert.False(t, hasKey(map[string]interface{}{"auth": true}, "rbac"))
+}
+
+func TestEngine_FuncMap_SprigHelpersAreOptIn(t *testing.T) {
+	e := &Engine{}
+	funcs, err := e.FuncMap(&TemplateConfig{Functions: []string{"upper", "camelcase", "dict"}})
+	require.NoError(t, err)
+	assert.Contains(t, funcs, "upper")
+	assert.Contains(t, funcs, "camelcase")
+	assert.Contains(t, funcs, "dict")
+
+	defaultFuncs, err := e.FuncMap(&TemplateConfig{})
+	require.NoError(t, err)
+	assert.NotContains(t, defaultFuncs, "upper", "Sprig-style helpers should be opt-in via config.Functions")
+}
+
+func TestCreateMixin_Include(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{
+			Data: []byte(`{"name": "Include Test", "variables": {"MixinName": {}}}`),
+		},
+		"_partial.txt":  &fstest.MapFile{Data: []byte("partial for {{ .MixinName }}")},
+		"main.txt.tmpl": &fstest.MapFile{Data: []byte(`before {{ include "_partial.txt" }} after`)},
+	}
+	config := &TemplateConfig{
+		Name:      "Include Test",
+		Variables: map[string]Variable{"MixinName": {}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "main.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "before partial for my-mixin after", string(content))
+}
+
+func TestCreateMixin_MissingKeyErrors(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{
+			Data: []byte(`{"name": "Typo Test", "variables": {"MixinName": {}}}`),
+		},
+		"main.txt.tmpl": &fstest.MapFile{Data: []byte("hello {{ .MixinNmae }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Typo Test",
+		Variables: map[string]Variable{"MixinName": {}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	err := createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil)
+	require.Error(t, err, "a typo'd variable name should fail generation instead of rendering <no value>")
+}
+
+func TestCreateMixin_CustomFuncMap_FilenameAndContent(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{
+			Data: []byte(`{"name": "Func Test", "variables": {"MixinName": {}}, "functions": ["toKebab"]}`),
+		},
+		"{{ .MixinName | toKebab }}.txt.tmpl": &fstest.MapFile{
+			Data: []byte("hello {{ .MixinName | toKebab }}"),
+		},
+	}
+	config := &TemplateConfig{
+		Name:      "Func Test",
+		Variables: map[string]Variable{"MixinName": {}},
+		Functions: []string{"toKebab"},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "MixinName"}
+
+	outputDir := t.TempDir()
+	defer os.RemoveAll(outputDir)
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "mixin-name.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello mixin-name", string(content))
+}
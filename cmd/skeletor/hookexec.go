@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// stderrTailBytes bounds how much of a failed hook's stderr is kept for
+// HookResult.StderrTail and the --hooks-json-summary output.
+const stderrTailBytes = 2048
+
+// HookRuntime selects which container backend runs a HookStep that pins its
+// own "image": podman, or docker for any other value (host included). A
+// HookStep without an image always runs on the host.
+type HookRuntime string
+
+const (
+	HookRuntimeHost   HookRuntime = "host"
+	HookRuntimeDocker HookRuntime = "docker"
+	HookRuntimePodman HookRuntime = "podman"
+)
+
+// HookStep is one entry in a template.json hooks list. The legacy form is a
+// bare command string; the object form additionally pins the container
+// image, working directory and environment a hook author wants, so a hook
+// runs the same toolchain everywhere instead of whatever happens to be on
+// the host's PATH.
+type HookStep struct {
+	// Name identifies this step for --skip-hooks/--only-hooks and the
+	// "[name] " prefix its output is tagged with; a step left unnamed can't
+	// be targeted by either flag and is tagged with its executable instead.
+	Name    string            `json:"name,omitempty"`
+	Command string            `json:"command"`
+	Image   string            `json:"image,omitempty"`
+	Workdir string            `json:"workdir,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	// Cmd is the argv-form alternative to Command: each element is
+	// rendered as its own Go template and exec'd directly, with no shell
+	// in between (the container backend runs it as the image's entrypoint
+	// args instead of "sh -c Command"), so a hook's arguments can't be
+	// reinterpreted by a shell. Command is ignored when Cmd is set.
+	Cmd []string `json:"cmd,omitempty"`
+	// AllowNetwork permits a containerized hook (Image set) to reach the
+	// network; the default, false, runs it with "--network=none". Hosts
+	// hooks can't be sandboxed this way and always see the host's network.
+	AllowNetwork bool `json:"allow_network,omitempty"`
+	// Timeout bounds how long this hook may run, as a time.ParseDuration
+	// string (e.g. "30s"); empty means no timeout.
+	Timeout string `json:"timeout,omitempty"`
+	// When is a Go template (e.g. "{{ .EnableCI }}") evaluated against the
+	// generation data; the hook is skipped (HookResult.Status "skipped")
+	// unless it renders to a non-empty value other than "false" or "0".
+	When string `json:"when,omitempty"`
+	// ContinueOnError keeps this hook's failure out of RunHooksWithOptions'
+	// combined error and lets the chain proceed past it even when
+	// --fail-fast is set, for hooks that are advisory (e.g. a linter that
+	// shouldn't block scaffolding).
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+}
+
+// UnmarshalJSON accepts either a bare command string (legacy) or the full
+// object form.
+func (s *HookStep) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*s = HookStep{Command: command}
+		return nil
+	}
+
+	type hookStepAlias HookStep
+	var alias hookStepAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("hook step must be a command string or an object with a \"command\" field: %w", err)
+	}
+	*s = HookStep(alias)
+	return nil
+}
+
+// hookInvocation is a HookStep's command after template rendering, in
+// whichever form it was authored: Argv (from step.Cmd, run with no shell)
+// takes precedence over Command (the legacy single-string form, run via
+// "sh -c" in a container or split on whitespace on the host) when both are
+// somehow set.
+type hookInvocation struct {
+	Command string
+	Argv    []string
+}
+
+// HookExecutor runs one HookStep's already-templated invocation, returning
+// its exit code and a bounded tail of its stderr alongside any error so
+// callers can build a HookResult without re-running the command. ctx bounds
+// the run with step.Timeout, if any.
+type HookExecutor interface {
+	Run(ctx context.Context, step HookStep, inv hookInvocation, outputDir string) (exitCode int, stderrTail string, err error)
+}
+
+// selectHookExecutor picks the HookExecutor for step: the container backend
+// named by config.HooksRuntime (or docker, if step pins an image but the
+// template didn't choose a runtime) when step.Image is set, the host
+// backend otherwise.
+func selectHookExecutor(config *TemplateConfig, step HookStep) HookExecutor {
+	if step.Image == "" {
+		return hostHookExecutor{AllowedCommands: DefaultAllowedHookCommands}
+	}
+
+	runtime := config.HooksRuntime
+	if runtime != HookRuntimePodman {
+		runtime = HookRuntimeDocker
+	}
+	return containerHookExecutor{Runtime: runtime}
+}
+
+// hostHookExecutor runs a hook directly on the host, subject to an
+// allow-list -- today's behavior, generalized so the list is a field
+// instead of a hard-coded package-level map.
+type hostHookExecutor struct {
+	AllowedCommands map[string]bool
+}
+
+func (e hostHookExecutor) Run(ctx context.Context, step HookStep, inv hookInvocation, outputDir string) (int, string, error) {
+	argv := inv.Argv
+	if len(argv) == 0 {
+		argv = splitCommand(inv.Command)
+	}
+	if len(argv) == 0 {
+		return 0, "", nil
+	}
+	executable := argv[0]
+	if !e.AllowedCommands[executable] {
+		return -1, "", fmt.Errorf("hook command %q is not in the allow-list", executable)
+	}
+
+	workdir, err := hookWorkdir(outputDir, step.Workdir)
+	if err != nil {
+		return -1, "", err
+	}
+
+	// #nosec G204 -- executable is allow-listed, args are rendered from trusted template.json
+	cmd := exec.CommandContext(ctx, executable, argv[1:]...)
+	cmd.Dir = workdir
+	cmd.Env = append(hostHookEnv(), envSlice(step.Env)...)
+	prefix := hookLogPrefix(step, inv)
+	cmd.Stdout = newPrefixWriter(prefix, os.Stdout)
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = io.MultiWriter(newPrefixWriter(prefix, os.Stderr), &stderrBuf)
+
+	err = cmd.Run()
+	return exitCodeOf(err), tail(stderrBuf.String(), stderrTailBytes), err
+}
+
+// hostHookEnvAllowlist is the set of host environment variables a host hook
+// inherits by default; everything else is scrubbed so a hook can't read
+// ambient secrets (API tokens, cloud credentials) just by being a post_gen
+// hook in a fetched template. A hook adds what else it needs via its own
+// "env" map.
+var hostHookEnvAllowlist = []string{"PATH", "HOME", "TMPDIR"}
+
+func hostHookEnv() []string {
+	env := make([]string, 0, len(hostHookEnvAllowlist))
+	for _, key := range hostHookEnvAllowlist {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// hookWorkdir resolves step.Workdir against outputDir, confining a host
+// hook's working directory to outputDir's subtree so it can't be pointed
+// at an arbitrary host path.
+func hookWorkdir(outputDir, stepWorkdir string) (string, error) {
+	if stepWorkdir == "" {
+		return outputDir, nil
+	}
+
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve output directory %s: %w", outputDir, err)
+	}
+	joined := filepath.Join(absOutput, stepWorkdir)
+	rel, err := filepath.Rel(absOutput, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("hook workdir %q escapes the output directory", stepWorkdir)
+	}
+	return joined, nil
+}
+
+// containerHookExecutor runs a hook inside step.Image via docker or podman,
+// mounting outputDir so the hook can see (and modify) generated files.
+type containerHookExecutor struct {
+	Runtime HookRuntime
+}
+
+func (e containerHookExecutor) Run(ctx context.Context, step HookStep, inv hookInvocation, outputDir string) (int, string, error) {
+	args := containerRunArgs(e, step, inv, outputDir)
+
+	// #nosec G204 -- runtime is restricted to docker/podman and image/command come from trusted template.json
+	cmd := exec.CommandContext(ctx, string(e.Runtime), args...)
+	prefix := hookLogPrefix(step, inv)
+	cmd.Stdout = newPrefixWriter(prefix, os.Stdout)
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = io.MultiWriter(newPrefixWriter(prefix, os.Stderr), &stderrBuf)
+
+	err := cmd.Run()
+	return exitCodeOf(err), tail(stderrBuf.String(), stderrTailBytes), err
+}
+
+// containerRunArgs builds e's "docker run"/"podman run" argument list for
+// step/inv, factored out of Run so it's unit-testable without actually
+// shelling out to a container runtime.
+func containerRunArgs(e containerHookExecutor, step HookStep, inv hookInvocation, outputDir string) []string {
+	workdir := step.Workdir
+	if workdir == "" {
+		workdir = "/workspace"
+	}
+
+	args := []string{"run", "--rm",
+		"-v", outputDir + ":" + workdir,
+		"-w", workdir,
+	}
+	if !step.AllowNetwork {
+		args = append(args, "--network=none")
+	}
+	for _, kv := range envSlice(step.Env) {
+		args = append(args, "-e", kv)
+	}
+	args = append(args, step.Image)
+	if len(inv.Argv) > 0 {
+		args = append(args, inv.Argv...)
+	} else {
+		args = append(args, "sh", "-c", inv.Command)
+	}
+	return args
+}
+
+// exitCodeOf extracts a command's exit code from the error cmd.Run()
+// returned, or -1 if the process never started (or err is nil, in which
+// case the code is 0).
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// tail returns s's last n bytes, or all of s if it's shorter than that.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
+
+// splitCommand does the same basic whitespace split RunHooks has always
+// used to separate a hook's executable from its arguments.
+func splitCommand(command string) []string {
+	return strings.Fields(command)
+}
+
+// hookLogPrefix returns the "[name] " tag a hook's stdout/stderr lines are
+// written with: step.Name if the template set one, otherwise the
+// invocation's executable -- so two hooks running concurrently (a future
+// parallel hook phase, or a host hook and a container hook racing in
+// different RunHooksWithOptions calls) stay attributable instead of
+// interleaving into unreadable output.
+func hookLogPrefix(step HookStep, inv hookInvocation) string {
+	name := step.Name
+	if name == "" {
+		if len(inv.Argv) > 0 {
+			name = inv.Argv[0]
+		} else if fields := strings.Fields(inv.Command); len(fields) > 0 {
+			name = fields[0]
+		} else {
+			name = "hook"
+		}
+	}
+	return "[" + name + "] "
+}
+
+// prefixWriter tags every line written through it with a fixed prefix,
+// re-emitting it at the start of each new line regardless of how the
+// underlying writes are chunked.
+type prefixWriter struct {
+	prefix      string
+	out         io.Writer
+	atLineStart bool
+}
+
+func newPrefixWriter(prefix string, out io.Writer) *prefixWriter {
+	return &prefixWriter{prefix: prefix, out: out, atLineStart: true}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.atLineStart {
+			if _, err := io.WriteString(w.out, w.prefix); err != nil {
+				return written, err
+			}
+			w.atLineStart = false
+		}
+		idx := bytes.IndexByte(p, '\n')
+		if idx == -1 {
+			n, err := w.out.Write(p)
+			written += n
+			return written, err
+		}
+		n, err := w.out.Write(p[:idx+1])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		w.atLineStart = true
+		p = p[idx+1:]
+	}
+	return written, nil
+}
+
+// splitHookNames parses a --skip-hooks/--only-hooks flag value (a
+// comma-separated list of HookStep.Name values) into a slice, trimming
+// whitespace and dropping empty entries so a trailing comma or stray space
+// doesn't produce a name that can never match.
+func splitHookNames(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// envSlice renders a hook's env map as sorted "KEY=VALUE" entries, for
+// deterministic command-line/exec.Cmd.Env output.
+func envSlice(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	slice := make([]string, 0, len(keys))
+	for _, k := range keys {
+		slice = append(slice, k+"="+env[k])
+	}
+	return slice
+}
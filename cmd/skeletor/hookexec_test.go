@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookStep_UnmarshalJSON_LegacyString(t *testing.T) {
+	var step HookStep
+	require.NoError(t, json.Unmarshal([]byte(`"go build ./..."`), &step))
+	assert.Equal(t, HookStep{Command: "go build ./..."}, step)
+}
+
+func TestHookStep_UnmarshalJSON_ObjectForm(t *testing.T) {
+	var step HookStep
+	raw := `{"command": "golangci-lint run", "image": "golangci/golangci-lint:latest", "workdir": "/src", "env": {"CGO_ENABLED": "0"}}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &step))
+	assert.Equal(t, HookStep{
+		Command: "golangci-lint run",
+		Image:   "golangci/golangci-lint:latest",
+		Workdir: "/src",
+		Env:     map[string]string{"CGO_ENABLED": "0"},
+	}, step)
+}
+
+func TestHookStep_UnmarshalJSON_Invalid(t *testing.T) {
+	var step HookStep
+	err := json.Unmarshal([]byte(`42`), &step)
+	assert.Error(t, err)
+}
+
+func TestSelectHookExecutor(t *testing.T) {
+	config := &TemplateConfig{}
+
+	executor := selectHookExecutor(config, HookStep{Command: "go test ./..."})
+	_, isHost := executor.(hostHookExecutor)
+	assert.True(t, isHost)
+
+	executor = selectHookExecutor(config, HookStep{Command: "make", Image: "golang:1.22"})
+	containerExec, isContainer := executor.(containerHookExecutor)
+	require.True(t, isContainer)
+	assert.Equal(t, HookRuntimeDocker, containerExec.Runtime)
+
+	config.HooksRuntime = HookRuntimePodman
+	executor = selectHookExecutor(config, HookStep{Command: "make", Image: "golang:1.22"})
+	containerExec, isContainer = executor.(containerHookExecutor)
+	require.True(t, isContainer)
+	assert.Equal(t, HookRuntimePodman, containerExec.Runtime)
+}
+
+func TestHostHookExecutor_Run_RejectsDisallowedCommand(t *testing.T) {
+	executor := hostHookExecutor{AllowedCommands: map[string]bool{"go": true}}
+	_, _, err := executor.Run(context.Background(), HookStep{}, hookInvocation{Command: "rm -rf /"}, t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestHostHookExecutor_Run_UsesArgvWhenSet(t *testing.T) {
+	executor := hostHookExecutor{AllowedCommands: map[string]bool{"go": true}}
+	_, _, err := executor.Run(context.Background(), HookStep{}, hookInvocation{Argv: []string{"go", "version"}}, t.TempDir())
+	assert.NoError(t, err)
+}
+
+func TestHookWorkdir_ConfinesToOutputDir(t *testing.T) {
+	outputDir := t.TempDir()
+
+	dir, err := hookWorkdir(outputDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, outputDir, dir)
+
+	dir, err = hookWorkdir(outputDir, "sub")
+	require.NoError(t, err)
+	assert.Contains(t, dir, outputDir)
+
+	_, err = hookWorkdir(outputDir, "../../etc")
+	assert.Error(t, err)
+}
+
+func TestContainerHookExecutor_NetworkAndArgvDispatch(t *testing.T) {
+	args := containerRunArgs(containerHookExecutor{Runtime: HookRuntimeDocker},
+		HookStep{Image: "golang:1.22"}, hookInvocation{Argv: []string{"go", "version"}}, "/out")
+	assert.Contains(t, args, "--network=none")
+	assert.NotContains(t, args, "sh")
+	assert.Contains(t, args, "go")
+
+	args = containerRunArgs(containerHookExecutor{Runtime: HookRuntimeDocker},
+		HookStep{Image: "golang:1.22", AllowNetwork: true}, hookInvocation{Command: "go version"}, "/out")
+	assert.NotContains(t, args, "--network=none")
+	assert.Contains(t, args, "sh")
+	assert.Contains(t, args, "-c")
+	assert.Contains(t, args, "go version")
+}
+
+func TestEnvSlice_SortedDeterministicOutput(t *testing.T) {
+	env := map[string]string{"B": "2", "A": "1"}
+	assert.Equal(t, []string{"A=1", "B=2"}, envSlice(env))
+}
+
+func TestHookLogPrefix(t *testing.T) {
+	assert.Equal(t, "[lint] ", hookLogPrefix(HookStep{Name: "lint"}, hookInvocation{Command: "golangci-lint run"}))
+	assert.Equal(t, "[go] ", hookLogPrefix(HookStep{}, hookInvocation{Argv: []string{"go", "test", "./..."}}))
+	assert.Equal(t, "[go] ", hookLogPrefix(HookStep{}, hookInvocation{Command: "go test ./..."}))
+	assert.Equal(t, "[hook] ", hookLogPrefix(HookStep{}, hookInvocation{}))
+}
+
+func TestPrefixWriter_TagsEachLine(t *testing.T) {
+	var out bytes.Buffer
+	w := newPrefixWriter("[go] ", &out)
+
+	n, err := w.Write([]byte("line one\nline "))
+	require.NoError(t, err)
+	assert.Equal(t, 14, n)
+	n, err = w.Write([]byte("two\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	assert.Equal(t, "[go] line one\n[go] line two\n", out.String())
+}
+
+func TestSplitHookNames(t *testing.T) {
+	assert.Nil(t, splitHookNames(""))
+	assert.Equal(t, []string{"go-build"}, splitHookNames("go-build"))
+	assert.Equal(t, []string{"go-build", "go-test"}, splitHookNames("go-build, go-test,"))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// AllowHooksLevel gates whether post_gen hooks ever run, based on how
+// trustworthy the template's source is. The supply-chain risk this
+// addresses is a --template-url/--template-source template shipping a
+// post_gen hook that runs arbitrary commands on the strength of nothing
+// more than having been fetched and generated.
+type AllowHooksLevel string
+
+const (
+	// AllowHooksNone never runs hooks, regardless of source.
+	AllowHooksNone AllowHooksLevel = "none"
+	// AllowHooksTrusted, the default, runs hooks only for a trusted
+	// source: the embedded templates or a local --template-dir the user
+	// pointed at directly -- never a --template-url/--template-source
+	// fetched over the network.
+	AllowHooksTrusted AllowHooksLevel = "trusted"
+	// AllowHooksAll runs hooks regardless of source.
+	AllowHooksAll AllowHooksLevel = "all"
+)
+
+// parseAllowHooksLevel parses the --allow-hooks flag value, defaulting to
+// AllowHooksTrusted for an empty string.
+func parseAllowHooksLevel(s string) (AllowHooksLevel, error) {
+	switch AllowHooksLevel(s) {
+	case "", AllowHooksTrusted:
+		return AllowHooksTrusted, nil
+	case AllowHooksNone, AllowHooksAll:
+		return AllowHooksLevel(s), nil
+	default:
+		return "", fmt.Errorf("unknown --allow-hooks value %q (expected none, trusted, or all)", s)
+	}
+}
+
+// hooksAllowed reports whether level permits running hooks for a template
+// whose source is remote (--template-url or --template-source) as opposed
+// to the embedded templates or a local --template-dir.
+func hooksAllowed(level AllowHooksLevel, isRemoteSource bool) bool {
+	switch level {
+	case AllowHooksNone:
+		return false
+	case AllowHooksAll:
+		return true
+	default: // AllowHooksTrusted
+		return !isRemoteSource
+	}
+}
+
+// hookManifestDigest hashes config.Hooks -- encoding/json marshals map keys
+// in sorted order, so this is deterministic across runs regardless of map
+// iteration order -- so --hook-trust can pin it across reruns of the same
+// template: a template author changing what a hook does changes the
+// digest, so a pinned --hook-trust catches it instead of silently running
+// the new command.
+func hookManifestDigest(config *TemplateConfig) (string, error) {
+	encoded, err := json.Marshal(config.Hooks)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash hook manifest: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// verifyHookTrust computes config's hook manifest digest and, if
+// expectedDigest is non-empty, errors out when it doesn't match --
+// the --hook-trust flag's pinning check. It always returns the computed
+// digest so a caller establishing trust for the first time can print it.
+func verifyHookTrust(config *TemplateConfig, expectedDigest string) (string, error) {
+	digest, err := hookManifestDigest(config)
+	if err != nil {
+		return "", err
+	}
+	if expectedDigest != "" && digest != expectedDigest {
+		return digest, fmt.Errorf("hook manifest digest %s does not match --hook-trust %s; review the template's hooks before proceeding", digest, expectedDigest)
+	}
+	return digest, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAllowHooksLevel(t *testing.T) {
+	level, err := parseAllowHooksLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, AllowHooksTrusted, level)
+
+	for _, name := range []string{"none", "trusted", "all"} {
+		level, err := parseAllowHooksLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, AllowHooksLevel(name), level)
+	}
+
+	_, err = parseAllowHooksLevel("sometimes")
+	require.Error(t, err)
+}
+
+func TestHooksAllowed(t *testing.T) {
+	assert.False(t, hooksAllowed(AllowHooksNone, false))
+	assert.False(t, hooksAllowed(AllowHooksNone, true))
+
+	assert.True(t, hooksAllowed(AllowHooksTrusted, false))
+	assert.False(t, hooksAllowed(AllowHooksTrusted, true))
+
+	assert.True(t, hooksAllowed(AllowHooksAll, false))
+	assert.True(t, hooksAllowed(AllowHooksAll, true))
+}
+
+func TestHookManifestDigest_DeterministicAndSensitiveToChange(t *testing.T) {
+	a := &TemplateConfig{Hooks: map[string][]HookStep{"post_gen": {{Command: "go build ./..."}}}}
+	b := &TemplateConfig{Hooks: map[string][]HookStep{"post_gen": {{Command: "go build ./..."}}}}
+	c := &TemplateConfig{Hooks: map[string][]HookStep{"post_gen": {{Command: "rm -rf /"}}}}
+
+	digestA, err := hookManifestDigest(a)
+	require.NoError(t, err)
+	digestB, err := hookManifestDigest(b)
+	require.NoError(t, err)
+	digestC, err := hookManifestDigest(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, digestA, digestB)
+	assert.NotEqual(t, digestA, digestC)
+}
+
+func TestVerifyHookTrust(t *testing.T) {
+	config := &TemplateConfig{Hooks: map[string][]HookStep{"post_gen": {{Command: "go build ./..."}}}}
+
+	digest, err := verifyHookTrust(config, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, digest)
+
+	_, err = verifyHookTrust(config, digest)
+	require.NoError(t, err)
+
+	_, err = verifyHookTrust(config, "deadbeef")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHooksWithOptions_AccumulatesByDefault(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "not-allowed-1"},
+				{Command: "not-allowed-2"},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{}, HookRunOptions{})
+	require.Error(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "failed", results[0].Status)
+	assert.Equal(t, "failed", results[1].Status)
+	assert.Contains(t, err.Error(), "not-allowed-1")
+	assert.Contains(t, err.Error(), "not-allowed-2")
+}
+
+func TestRunHooksWithOptions_FailFastStopsEarly(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "not-allowed-1"},
+				{Command: "not-allowed-2"},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{}, HookRunOptions{FailFast: true})
+	require.Error(t, err)
+	require.Len(t, results, 1)
+	assert.NotContains(t, err.Error(), "not-allowed-2")
+}
+
+func TestRunHooksWithOptions_ContinueOnErrorExcludedFromCombinedError(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "not-allowed-1", ContinueOnError: true},
+				{Command: "go version"},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{}, HookRunOptions{FailFast: true})
+	require.Len(t, results, 2)
+	assert.Equal(t, "failed", results[0].Status)
+	if err != nil {
+		assert.NotContains(t, err.Error(), "not-allowed-1")
+	}
+}
+
+func TestRunHooks_NoHooksForStage(t *testing.T) {
+	config := &TemplateConfig{Hooks: map[string][]HookStep{}}
+	err := RunHooks(config, "post_gen", t.TempDir(), map[string]interface{}{})
+	assert.NoError(t, err)
+}
+
+func TestRunHooksWithOptions_WhenSkipsFalsyHook(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "go version", When: "{{ .EnableCI }}"},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{"EnableCI": false}, HookRunOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "skipped", results[0].Status)
+}
+
+func TestRunHooksWithOptions_WhenRunsTruthyHook(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "go version", When: "{{ .EnableCI }}"},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{"EnableCI": true}, HookRunOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "ok", results[0].Status)
+}
+
+func TestRunHooksWithOptions_InvalidTimeoutErrors(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Command: "go version", Timeout: "not-a-duration"},
+			},
+		},
+	}
+
+	_, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{}, HookRunOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not-a-duration")
+}
+
+func TestRunHooksWithOptions_CmdArgvDispatch(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"post_gen": {
+				{Cmd: []string{"go", "version"}},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "post_gen", t.TempDir(), map[string]interface{}{}, HookRunOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "ok", results[0].Status)
+	assert.Equal(t, "go version", results[0].Command)
+}
+
+func TestRunHooksWithOptions_SkipByName(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"validate": {
+				{Name: "go-build", Cmd: []string{"go", "version"}},
+				{Name: "go-test", Cmd: []string{"go", "version"}},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "validate", t.TempDir(), map[string]interface{}{}, HookRunOptions{Skip: []string{"go-test"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "ok", results[0].Status)
+	assert.Equal(t, "skipped", results[1].Status)
+}
+
+func TestRunHooksWithOptions_OnlyTakesPriorityOverSkip(t *testing.T) {
+	config := &TemplateConfig{
+		Hooks: map[string][]HookStep{
+			"validate": {
+				{Name: "go-build", Cmd: []string{"go", "version"}},
+				{Name: "go-test", Cmd: []string{"go", "version"}},
+			},
+		},
+	}
+
+	results, err := RunHooksWithOptions(config, "validate", t.TempDir(), map[string]interface{}{}, HookRunOptions{
+		Skip: []string{"go-test"},
+		Only: []string{"go-test"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "skipped", results[0].Status)
+	assert.Equal(t, "ok", results[1].Status)
+}
+
+func TestHookSelected_UnnamedStepAlwaysRuns(t *testing.T) {
+	assert.True(t, hookSelected(HookStep{}, HookRunOptions{Skip: []string{"anything"}, Only: []string{"something-else"}}))
+}
+
+func TestDefaultTemplateConfig_SeedsValidateHooks(t *testing.T) {
+	config := defaultTemplateConfig()
+	validateHooks := config.Hooks["validate"]
+	require.Len(t, validateHooks, 3)
+	for _, step := range validateHooks {
+		assert.True(t, step.ContinueOnError, "default validate hook %q should not block generation", step.Name)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/getporter/skeletor/pkg/hooks"
+)
+
+// describeDeclaredHook renders a hooks.yaml Hook for --dry-run output: its
+// name, the command it actually runs (Run if set, else whichever built-in
+// Name resolves to, else "via --hook-plugin-dir" for a name pkg/hooks.Run
+// would have to resolve against a plugin directory), and its When guard if
+// it has one.
+func describeDeclaredHook(h hooks.Hook) string {
+	command := h.Run
+	if command == "" {
+		if builtin, ok := hooks.Builtins[h.Name]; ok {
+			command = fmt.Sprintf("%v", builtin)
+		} else {
+			command = "via --hook-plugin-dir"
+		}
+	}
+	desc := fmt.Sprintf("%s (%s)", h.Name, command)
+	if h.When != "" {
+		desc += fmt.Sprintf(" when %s", h.When)
+	}
+	return desc
+}
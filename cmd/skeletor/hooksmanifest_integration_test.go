@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateMixin_Integration_HooksYamlPluginDir generates from a
+// --template-dir shipping a hooks.yaml that names a hook not in pkg/hooks'
+// Builtins, resolves it against a --hook-plugin-dir executable, and asserts
+// the executable actually ran (with SKELETOR_OUTPUT_DIR set to the
+// generated project's root) by having it write a marker file there.
+func TestCreateMixin_Integration_HooksYamlPluginDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode.")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("shell fixture assumes a POSIX shell")
+	}
+
+	binaryPath := getGeneratorBinaryPath(t)
+
+	localTemplateDir := t.TempDir()
+	templateJSONContent := `{
+		"name": "Hooks.yaml Test",
+		"variables": {
+			"MixinName": {"type": "string", "required": true}
+		}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(localTemplateDir, "template.json"), []byte(templateJSONContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localTemplateDir, "test.txt.tmpl"), []byte("{{ .MixinName }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localTemplateDir, "hooks.yaml"), []byte(`
+hooks:
+  - name: record-output-dir
+`), 0644))
+
+	pluginDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "record-output-dir"),
+		[]byte("#!/bin/sh\necho -n \"$SKELETOR_OUTPUT_DIR\" > \"$SKELETOR_OUTPUT_DIR/hook-ran.txt\"\n"), 0o700))
+
+	mixinName := "hooks-yaml-mixin"
+	args := []string{
+		"--name", mixinName,
+		"--author", "Hooks Tester",
+		"--module", "example.com/getporter/" + mixinName,
+		"--template-dir", localTemplateDir,
+		"--hook-plugin-dir", pluginDir,
+	}
+
+	outputDir, err := runGeneratorCreate(t, binaryPath, args...)
+	require.NoError(t, err, "generator create failed with a hooks.yaml plugin-dir hook")
+	defer os.RemoveAll(outputDir)
+
+	markerPath := filepath.Join(outputDir, "hook-ran.txt")
+	got, err := os.ReadFile(markerPath)
+	require.NoError(t, err, "hooks.yaml's record-output-dir hook did not run")
+	require.Equal(t, outputDir, string(got), "hook saw the wrong SKELETOR_OUTPUT_DIR")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/getporter/skeletor/pkg"
+)
+
+// TemplateKind selects which embedded template tree `skeletor create`
+// scaffolds from: a Porter mixin (the historical default) or a Porter
+// plugin. Everything downstream of getTemplateSource -- calculateDestPath,
+// the generation walk, hooks, policy gates -- is kind-agnostic; only the
+// embedded filesystem and its root path change.
+type TemplateKind string
+
+const (
+	// TemplateKindMixin scaffolds a Porter mixin from pkg.MixinTemplateFS,
+	// rooted at "template" (plus its named flavors, e.g. "template/minimal").
+	TemplateKindMixin TemplateKind = "mixin"
+	// TemplateKindPlugin scaffolds a Porter plugin implementing the Porter
+	// plugin gRPC interface, from pkg.PluginTemplateFS rooted at
+	// "template-plugin".
+	TemplateKindPlugin TemplateKind = "plugin"
+)
+
+// parseTemplateKind parses the --kind flag value, defaulting to
+// TemplateKindMixin for an empty string.
+func parseTemplateKind(s string) (TemplateKind, error) {
+	switch TemplateKind(s) {
+	case "", TemplateKindMixin:
+		return TemplateKindMixin, nil
+	case TemplateKindPlugin:
+		return TemplateKindPlugin, nil
+	default:
+		return "", fmt.Errorf("unknown --kind value %q (expected mixin or plugin)", s)
+	}
+}
+
+// builtinTemplateFS returns the embedded filesystem kind scaffolds from.
+func builtinTemplateFS(kind TemplateKind) fs.FS {
+	if kind == TemplateKindPlugin {
+		return pkg.PluginTemplateFS
+	}
+	return pkg.MixinTemplateFS
+}
+
+// builtinTemplateBase returns kind's top-level root within its embedded
+// filesystem, before a named flavor (e.g. "minimal") is joined on.
+func builtinTemplateBase(kind TemplateKind) string {
+	if kind == TemplateKindPlugin {
+		return "template-plugin"
+	}
+	return "template"
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplateKind(t *testing.T) {
+	kind, err := parseTemplateKind("")
+	require.NoError(t, err)
+	assert.Equal(t, TemplateKindMixin, kind)
+
+	kind, err = parseTemplateKind("mixin")
+	require.NoError(t, err)
+	assert.Equal(t, TemplateKindMixin, kind)
+
+	kind, err = parseTemplateKind("plugin")
+	require.NoError(t, err)
+	assert.Equal(t, TemplateKindPlugin, kind)
+
+	_, err = parseTemplateKind("bundle")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"bundle"`)
+}
+
+func TestBuiltinTemplateBase(t *testing.T) {
+	assert.Equal(t, "template", builtinTemplateBase(TemplateKindMixin))
+	assert.Equal(t, "template-plugin", builtinTemplateBase(TemplateKindPlugin))
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// LicensePolicy is one `Licenses` entry of TemplateConfig: every generated
+// destination under Root carries SPDX as its license, rendered from Header,
+// with AllowList naming other SPDX IDs `skeletor license verify` should
+// still accept there (e.g. a vendored file the template ships as-is).
+// Policies are matched in declaration order, first match wins -- list a
+// narrower Root (e.g. "pkg/enterprise/**") before a broader one that would
+// otherwise also match (e.g. "**") the same files.
+type LicensePolicy struct {
+	Root      string   `json:"root"`                 // doublestar glob matched against a destination's template-relative path
+	SPDX      string   `json:"spdx"`                 // e.g. "Apache-2.0"
+	Header    string   `json:"header"`               // Go template rendered with the file's data plus {"SPDX": SPDX}; author supplies correct comment syntax for the target language
+	AllowList []string `json:"allow_list,omitempty"` // additional SPDX IDs license verify accepts for a file matched by Root
+}
+
+// matchLicensePolicy returns the first policy (in declaration order) whose
+// Root matches destRelPath, or nil if none do.
+func matchLicensePolicy(policies []LicensePolicy, destRelPath string) (*LicensePolicy, error) {
+	for i := range policies {
+		matched, err := doublestar.Match(policies[i].Root, destRelPath)
+		if err != nil {
+			return nil, fmt.Errorf("license policy root %q: invalid pattern: %w", policies[i].Root, err)
+		}
+		if matched {
+			return &policies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// renderLicenseHeader renders policy.Header as a Go template against data
+// with an added "SPDX" key, the same FuncMap every other file is rendered
+// with.
+func renderLicenseHeader(policy *LicensePolicy, data map[string]interface{}, funcs template.FuncMap) (string, error) {
+	headerData := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		headerData[k] = v
+	}
+	headerData["SPDX"] = policy.SPDX
+
+	tmpl, err := template.New("licenseHeader").Funcs(funcs).Option("missingkey=error").Parse(policy.Header)
+	if err != nil {
+		return "", fmt.Errorf("license policy %q: failed to parse header template: %w", policy.Root, wrapUnknownFunctionError(err, funcs))
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, headerData); err != nil {
+		return "", fmt.Errorf("license policy %q: failed to execute header template: %w", policy.Root, err)
+	}
+	return buf.String(), nil
+}
+
+// spdxLineRE finds an existing "SPDX-License-Identifier: <id>" line,
+// capturing whatever precedes and follows the identifier itself (the
+// comment marker and any trailing comment close token) so it can be
+// rewritten in place without disturbing the rest of the line.
+var spdxLineRE = regexp.MustCompile(`(?m)^(.*SPDX-License-Identifier:\s*)(\S+)(.*)$`)
+
+// injectLicenseHeader applies policy's rendered header to content: if
+// content already carries an SPDX-License-Identifier line (e.g. a prior
+// generation pass already inserted one), only that line's identifier is
+// rewritten to spdx, leaving the rest of the existing header untouched;
+// otherwise the full rendered header is inserted at the top of the file,
+// after any shebang line and any leading Go build-constraint comment block
+// (//go:build or // +build, plus the blank line convention requires after
+// them) so the header doesn't break either.
+func injectLicenseHeader(content, header, spdx string) string {
+	if loc := spdxLineRE.FindStringSubmatchIndex(content); loc != nil {
+		prefix := content[loc[2]:loc[3]]
+		suffix := content[loc[6]:loc[7]]
+		return content[:loc[0]] + prefix + spdx + suffix + content[loc[1]:]
+	}
+	if header == "" {
+		return content
+	}
+	return insertLeadingHeader(content, header)
+}
+
+// insertLeadingHeader inserts header immediately after any shebang line and
+// any leading Go build-constraint comment block. This is a best-effort
+// line scan, not a real Go parser, but it covers the shapes
+// createMixin's own templates produce.
+func insertLeadingHeader(content, header string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	insertAt := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+		insertAt = 1
+	}
+
+	sawBuildTag := false
+scan:
+	for insertAt < len(lines) {
+		trimmed := strings.TrimSpace(lines[insertAt])
+		switch {
+		case strings.HasPrefix(trimmed, "//go:build") || strings.HasPrefix(trimmed, "// +build"):
+			sawBuildTag = true
+			insertAt++
+		case trimmed == "" && sawBuildTag:
+			sawBuildTag = false
+			insertAt++
+		default:
+			break scan
+		}
+	}
+
+	prefix := strings.Join(lines[:insertAt], "")
+	rest := strings.Join(lines[insertAt:], "")
+	return prefix + header + rest
+}
+
+// licenseManifestEntry is one LicensePolicy's on-disk record, written to
+// <outputDir>/licenses/policies.yaml during createMixin so `skeletor
+// license verify` -- which only has the generated mixin to work from, not
+// the original template.json -- knows what to check each file against.
+type licenseManifestEntry struct {
+	Root      string   `yaml:"root"`
+	SPDX      string   `yaml:"spdx"`
+	AllowList []string `yaml:"allow_list,omitempty"`
+}
+
+// licenseManifestFilename is, relative to a generated mixin's root, where
+// writeLicenseManifest/loadLicenseManifest keep the license policy record.
+const licenseManifestFilename = "licenses/policies.yaml"
+
+// writeLicenseManifest records policies' Root/SPDX/AllowList (not Header,
+// which is an authoring-time template and of no use to verify) so a later
+// `skeletor license verify` run can recheck them. It is a no-op, writing
+// nothing, if policies is empty.
+func writeLicenseManifest(policies []LicensePolicy, outputDir string) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	entries := make([]licenseManifestEntry, len(policies))
+	for i, p := range policies {
+		entries[i] = licenseManifestEntry{Root: p.Root, SPDX: p.SPDX, AllowList: p.AllowList}
+	}
+
+	raw, err := yaml.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal license manifest: %w", err)
+	}
+
+	path := filepath.Join(outputDir, licenseManifestFilename)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// matchLicenseManifestEntry is matchLicensePolicy's counterpart for
+// license verify, which only has the manifest's Root/SPDX/AllowList to
+// work from, not the original LicensePolicy with its Header template.
+func matchLicenseManifestEntry(entries []licenseManifestEntry, relPath string) (*licenseManifestEntry, error) {
+	for i := range entries {
+		matched, err := doublestar.Match(entries[i].Root, relPath)
+		if err != nil {
+			return nil, fmt.Errorf("license policy root %q: invalid pattern: %w", entries[i].Root, err)
+		}
+		if matched {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// loadLicenseManifest reads a generated mixin's licenses/policies.yaml.
+func loadLicenseManifest(path string) ([]licenseManifestEntry, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is built from a --project-dir flag under operator control
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var entries []licenseManifestEntry
+	if err := yaml.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// licenseVerifyStatus is one checked file's outcome.
+type licenseVerifyStatus string
+
+const (
+	licenseStatusOK       licenseVerifyStatus = "ok"
+	licenseStatusMissing  licenseVerifyStatus = "missing"
+	licenseStatusMismatch licenseVerifyStatus = "mismatch"
+)
+
+// licenseVerifyResult is one file license verify checked against a
+// matching policy.
+type licenseVerifyResult struct {
+	Path   string
+	SPDX   string // the SPDX ID actually found in the file, if any
+	Status licenseVerifyStatus
+}
+
+// verifyFileLicense checks content against entry: content must contain an
+// SPDX-License-Identifier line whose value is either entry.SPDX or one of
+// entry.AllowList.
+func verifyFileLicense(path, content string, entry licenseManifestEntry) licenseVerifyResult {
+	match := spdxLineRE.FindStringSubmatch(content)
+	if match == nil {
+		return licenseVerifyResult{Path: path, Status: licenseStatusMissing}
+	}
+	found := match[2]
+
+	if found == entry.SPDX {
+		return licenseVerifyResult{Path: path, SPDX: found, Status: licenseStatusOK}
+	}
+	for _, allowed := range entry.AllowList {
+		if found == allowed {
+			return licenseVerifyResult{Path: path, SPDX: found, Status: licenseStatusOK}
+		}
+	}
+	return licenseVerifyResult{Path: path, SPDX: found, Status: licenseStatusMismatch}
+}
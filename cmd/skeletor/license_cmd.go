@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildLicenseCommand returns the `skeletor license` command group.
+func buildLicenseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "license",
+		Short: "License header commands for generated mixins",
+	}
+	cmd.AddCommand(buildLicenseVerifyCommand())
+	return cmd
+}
+
+// buildLicenseVerifyCommand walks an already-generated mixin and checks
+// every file matched by licenses/policies.yaml (written by createMixin;
+// see writeLicenseManifest) for a correct SPDX-License-Identifier header.
+func buildLicenseVerifyCommand() *cobra.Command {
+	var projectDir string
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check every file in a generated mixin against the license policies in licenses/policies.yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestPath := filepath.Join(projectDir, licenseManifestFilename)
+			entries, err := loadLicenseManifest(manifestPath)
+			if err != nil {
+				return err
+			}
+
+			results, err := verifyProjectLicenses(projectDir, entries)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "STATUS\tFILE\tSPDX")
+			var missing, mismatched int
+			for _, result := range results {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", result.Status, result.Path, result.SPDX)
+				switch result.Status {
+				case licenseStatusMissing:
+					missing++
+				case licenseStatusMismatch:
+					mismatched++
+				}
+			}
+			w.Flush()
+
+			if missing > 0 || mismatched > 0 {
+				return fmt.Errorf("license verify: %d file(s) missing a header, %d file(s) with a mismatched SPDX ID", missing, mismatched)
+			}
+			fmt.Printf("license verify: %d file(s) checked, all OK\n", len(results))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&projectDir, "project-dir", ".", "Generated mixin directory containing licenses/policies.yaml")
+	return cmd
+}
+
+// verifyProjectLicenses walks projectDir, matching each regular file
+// against entries (in order, first Root match wins, mirroring
+// matchLicensePolicy) and checking its SPDX-License-Identifier header. A
+// file matched by no policy is not reported at all -- license verify only
+// holds templates accountable for the subtrees they actually claimed.
+func verifyProjectLicenses(projectDir string, entries []licenseManifestEntry) ([]licenseVerifyResult, error) {
+	var results []licenseVerifyResult
+
+	walkErr := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == licenseManifestFilename {
+			return nil
+		}
+
+		entry, matchErr := matchLicenseManifestEntry(entries, relPath)
+		if matchErr != nil {
+			return matchErr
+		}
+		if entry == nil {
+			return nil
+		}
+
+		content, readErr := os.ReadFile(path) // #nosec G304 -- path comes from walking --project-dir, under operator control
+		if readErr != nil {
+			return fmt.Errorf("failed to read %s: %w", path, readErr)
+		}
+		results = append(results, verifyFileLicense(relPath, string(content), *entry))
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", projectDir, walkErr)
+	}
+	return results, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLicensePolicies() []LicensePolicy {
+	return []LicensePolicy{
+		{
+			Root:   "pkg/enterprise/**",
+			SPDX:   "Commercial",
+			Header: "// Copyright {{ .MixinName }}\n// SPDX-License-Identifier: {{ .SPDX }}\n",
+		},
+		{
+			Root:   "**",
+			SPDX:   "Apache-2.0",
+			Header: "// SPDX-License-Identifier: {{ .SPDX }}\n",
+		},
+	}
+}
+
+func TestMatchLicensePolicy_FirstRootWins(t *testing.T) {
+	policies := testLicensePolicies()
+
+	policy, err := matchLicensePolicy(policies, "pkg/enterprise/widget.go")
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, "Commercial", policy.SPDX)
+
+	policy, err = matchLicensePolicy(policies, "pkg/other/widget.go")
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, "Apache-2.0", policy.SPDX)
+}
+
+func TestMatchLicensePolicy_NoMatch(t *testing.T) {
+	policy, err := matchLicensePolicy([]LicensePolicy{{Root: "pkg/enterprise/**", SPDX: "Commercial"}}, "README.md")
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestMatchLicensePolicy_InvalidPattern(t *testing.T) {
+	_, err := matchLicensePolicy([]LicensePolicy{{Root: "[", SPDX: "Apache-2.0"}}, "README.md")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pattern")
+}
+
+func TestRenderLicenseHeader(t *testing.T) {
+	policy := &LicensePolicy{
+		Root:   "**",
+		SPDX:   "Apache-2.0",
+		Header: "// Copyright {{ .MixinName }}\n// SPDX-License-Identifier: {{ .SPDX }}\n",
+	}
+	header, err := renderLicenseHeader(policy, map[string]interface{}{"MixinName": "my-mixin"}, template.FuncMap{})
+	require.NoError(t, err)
+	assert.Equal(t, "// Copyright my-mixin\n// SPDX-License-Identifier: Apache-2.0\n", header)
+}
+
+func TestRenderLicenseHeader_MissingKeyErrors(t *testing.T) {
+	policy := &LicensePolicy{Root: "**", SPDX: "Apache-2.0", Header: "{{ .Undefined }}"}
+	_, err := renderLicenseHeader(policy, map[string]interface{}{}, template.FuncMap{})
+	require.Error(t, err)
+}
+
+func TestInjectLicenseHeader_PlainFile(t *testing.T) {
+	got := injectLicenseHeader("package main\n", "// SPDX-License-Identifier: Apache-2.0\n", "Apache-2.0")
+	assert.Equal(t, "// SPDX-License-Identifier: Apache-2.0\npackage main\n", got)
+}
+
+func TestInjectLicenseHeader_AfterShebang(t *testing.T) {
+	content := "#!/bin/sh\necho hi\n"
+	got := injectLicenseHeader(content, "# SPDX-License-Identifier: Apache-2.0\n", "Apache-2.0")
+	assert.Equal(t, "#!/bin/sh\n# SPDX-License-Identifier: Apache-2.0\necho hi\n", got)
+}
+
+func TestInjectLicenseHeader_AfterBuildTag(t *testing.T) {
+	content := "//go:build linux\n\npackage main\n"
+	got := injectLicenseHeader(content, "// SPDX-License-Identifier: Apache-2.0\n", "Apache-2.0")
+	assert.Equal(t, "//go:build linux\n\n// SPDX-License-Identifier: Apache-2.0\npackage main\n", got)
+}
+
+func TestInjectLicenseHeader_RewritesExistingSPDXLine(t *testing.T) {
+	content := "// SPDX-License-Identifier: MIT\npackage main\n"
+	got := injectLicenseHeader(content, "// SPDX-License-Identifier: Apache-2.0\n", "Apache-2.0")
+	assert.Equal(t, "// SPDX-License-Identifier: Apache-2.0\npackage main\n", got)
+}
+
+func TestInjectLicenseHeader_EmptyHeaderIsNoOp(t *testing.T) {
+	content := "package main\n"
+	got := injectLicenseHeader(content, "", "Apache-2.0")
+	assert.Equal(t, content, got)
+}
+
+func TestWriteLoadLicenseManifest_RoundTrip(t *testing.T) {
+	outputDir := t.TempDir()
+	policies := []LicensePolicy{
+		{Root: "pkg/enterprise/**", SPDX: "Commercial", Header: "ignored at verify time", AllowList: []string{"MIT"}},
+	}
+	require.NoError(t, writeLicenseManifest(policies, outputDir))
+
+	entries, err := loadLicenseManifest(filepath.Join(outputDir, licenseManifestFilename))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "pkg/enterprise/**", entries[0].Root)
+	assert.Equal(t, "Commercial", entries[0].SPDX)
+	assert.Equal(t, []string{"MIT"}, entries[0].AllowList)
+}
+
+func TestWriteLicenseManifest_NoOpWhenEmpty(t *testing.T) {
+	outputDir := t.TempDir()
+	require.NoError(t, writeLicenseManifest(nil, outputDir))
+
+	_, err := os.Stat(filepath.Join(outputDir, licenseManifestFilename))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMatchLicenseManifestEntry(t *testing.T) {
+	entries := []licenseManifestEntry{
+		{Root: "pkg/enterprise/**", SPDX: "Commercial"},
+		{Root: "**", SPDX: "Apache-2.0"},
+	}
+	entry, err := matchLicenseManifestEntry(entries, "pkg/enterprise/widget.go")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "Commercial", entry.SPDX)
+
+	entry, err = matchLicenseManifestEntry(entries, "README.md")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "Apache-2.0", entry.SPDX)
+}
+
+func TestVerifyFileLicense(t *testing.T) {
+	entry := licenseManifestEntry{Root: "**", SPDX: "Apache-2.0", AllowList: []string{"MIT"}}
+
+	result := verifyFileLicense("a.go", "// SPDX-License-Identifier: Apache-2.0\npackage main\n", entry)
+	assert.Equal(t, licenseStatusOK, result.Status)
+
+	result = verifyFileLicense("b.go", "// SPDX-License-Identifier: MIT\npackage main\n", entry)
+	assert.Equal(t, licenseStatusOK, result.Status, "allow-listed SPDX ID is accepted")
+
+	result = verifyFileLicense("c.go", "// SPDX-License-Identifier: GPL-3.0\npackage main\n", entry)
+	assert.Equal(t, licenseStatusMismatch, result.Status)
+
+	result = verifyFileLicense("d.go", "package main\n", entry)
+	assert.Equal(t, licenseStatusMissing, result.Status)
+}
+
+func TestCreateMixin_InjectsLicenseHeaderAndWritesManifest(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	config.Licenses = []LicensePolicy{
+		{
+			Root:   "fresh.txt",
+			SPDX:   "Apache-2.0",
+			Header: "# SPDX-License-Identifier: {{ .SPDX }}\n",
+		},
+	}
+	outputDir := t.TempDir()
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "fresh.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "# SPDX-License-Identifier: Apache-2.0\nfresh content for my-mixin", string(content))
+
+	manifest, err := loadLicenseManifest(filepath.Join(outputDir, licenseManifestFilename))
+	require.NoError(t, err)
+	require.Len(t, manifest, 1)
+	assert.Equal(t, "fresh.txt", manifest[0].Root)
+	assert.Equal(t, "Apache-2.0", manifest[0].SPDX)
+}
+
+func TestVerifyProjectLicenses(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "ok.go"), []byte("// SPDX-License-Identifier: Apache-2.0\npackage main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "missing.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "unmatched.md"), []byte("# docs\n"), 0600))
+
+	entries := []licenseManifestEntry{{Root: "*.go", SPDX: "Apache-2.0"}}
+
+	results, err := verifyProjectLicenses(projectDir, entries)
+	require.NoError(t, err)
+	require.Len(t, results, 2, "unmatched.md is not claimed by any policy and should not be reported")
+
+	byPath := map[string]licenseVerifyResult{}
+	for _, r := range results {
+		byPath[r.Path] = r
+	}
+	assert.Equal(t, licenseStatusOK, byPath["ok.go"].Status)
+	assert.Equal(t, licenseStatusMissing, byPath["missing.go"].Status)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildListTemplatesCommand returns the `skeletor list-templates` command,
+// enumerating every built-in template flavor embedded for the selected
+// --kind (see listBuiltinTemplates/builtinTemplateRoot) and printing each
+// one's template.json description, so a user picking a `skeletor create
+// [--kind=...] <template-name>` selector knows what's available without
+// unpacking the binary.
+func buildListTemplatesCommand() *cobra.Command {
+	var kindRaw string
+
+	cmd := &cobra.Command{
+		Use:   "list-templates",
+		Short: "List the built-in template flavors available to `skeletor create <template-name>`",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := parseTemplateKind(kindRaw)
+			if err != nil {
+				return err
+			}
+
+			names, err := listBuiltinTemplates(kind)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				fmt.Printf("No built-in %s templates found.\n", kind)
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tDESCRIPTION")
+			for _, name := range names {
+				root, err := builtinTemplateRoot(name, kind)
+				if err != nil {
+					return err
+				}
+				config, err := LoadTemplateConfig(builtinTemplateFS(kind), root)
+				if err != nil {
+					return fmt.Errorf("failed to load template config from %s: %w", path.Join(root, "template.json"), err)
+				}
+				fmt.Fprintf(w, "%s\t%s\n", name, config.Description)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&kindRaw, "kind", string(TemplateKindMixin), "Built-in template tree to list: mixin (the default) or plugin")
+	return cmd
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTemplateRoot_DefaultsToBareTemplateRoot(t *testing.T) {
+	root, err := builtinTemplateRoot("", TemplateKindMixin)
+	require.NoError(t, err)
+	assert.Equal(t, "template", root)
+
+	root, err = builtinTemplateRoot("default", TemplateKindMixin)
+	require.NoError(t, err)
+	assert.Equal(t, "template", root)
+}
+
+func TestBuiltinTemplateRoot_UnknownNameListsAvailable(t *testing.T) {
+	_, err := builtinTemplateRoot("does-not-exist", TemplateKindMixin)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown built-in mixin template "does-not-exist"`)
+}
+
+func TestListBuiltinTemplates_IncludesDefault(t *testing.T) {
+	names, err := listBuiltinTemplates(TemplateKindMixin)
+	require.NoError(t, err)
+	assert.Contains(t, names, "default")
+}
+
+func TestBuiltinTemplateRoot_PluginKindUsesSeparateRoot(t *testing.T) {
+	root, err := builtinTemplateRoot("default", TemplateKindPlugin)
+	require.NoError(t, err)
+	assert.Equal(t, "template-plugin", root)
+}
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+// Location pinpoints where a config value came from: a template.json (or
+// .toml/.yaml/.yml) line/column, or a synthesized position like
+// "--var[3]:MyBool=maybe" for a value supplied on the command line, where
+// Line and Column are left zero.
+type Location struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String renders loc the way an IDE/CI problem matcher expects:
+// "file:line:column", or just the file when no line/column is known.
+func (l Location) String() string {
+	if l.Line == 0 && l.Column == 0 {
+		return l.File
+	}
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// ConfigError wraps an error with the Location it was detected at, so
+// callers (IDE integrations, CI annotations) can report exactly where a
+// template.json problem is instead of just what it is.
+type ConfigError struct {
+	Loc Location
+	Err error
+}
+
+// NewConfigError wraps err with loc, or returns nil if err is nil.
+func NewConfigError(loc Location, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConfigError{Loc: loc, Err: err}
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Loc, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Location returns the position e was raised at.
+func (e *ConfigError) Location() Location {
+	return e.Loc
+}
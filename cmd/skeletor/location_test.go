@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocation_String(t *testing.T) {
+	assert.Equal(t, "template.json:14:5", Location{File: "template.json", Line: 14, Column: 5}.String())
+	assert.Equal(t, "--var[3]:MyBool=maybe", Location{File: "--var[3]:MyBool=maybe"}.String())
+}
+
+func TestConfigError(t *testing.T) {
+	inner := errors.New("invalid boolean value")
+	err := NewConfigError(Location{File: "template.json", Line: 14, Column: 5}, inner)
+
+	require.Error(t, err)
+	assert.Equal(t, "template.json:14:5: invalid boolean value", err.Error())
+
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, Location{File: "template.json", Line: 14, Column: 5}, configErr.Location())
+	assert.ErrorIs(t, err, inner)
+}
+
+func TestNewConfigError_NilError(t *testing.T) {
+	assert.Nil(t, NewConfigError(Location{File: "template.json"}, nil))
+}
+
+func TestTemplateConfig_LocationOf(t *testing.T) {
+	config := &TemplateConfig{
+		sourceFile: "template.json",
+		rawSource: []byte(`{
+  "variables": {
+    "MyBool": {"type": "bool"}
+  }
+}`),
+	}
+
+	loc := config.locationOf("MyBool")
+	assert.Equal(t, "template.json", loc.File)
+	assert.Equal(t, 3, loc.Line)
+
+	// Unknown variable name: falls back to a file-only Location.
+	assert.Equal(t, Location{File: "template.json"}, config.locationOf("NoSuchVar"))
+
+	// A TemplateConfig built by hand (not via LoadTemplateConfig) has no
+	// source file at all.
+	assert.Equal(t, Location{}, (&TemplateConfig{}).locationOf("MyBool"))
+}
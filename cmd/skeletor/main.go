@@ -4,20 +4,25 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp" // Ensure regexp is imported
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"text/template" // Import text/template
 	"time"          // Import time package
 
+	"github.com/getporter/skeletor/pkg/hooks"
 	"github.com/spf13/cobra"
-
-	"github.com/getporter/skeletor/pkg" // Import the local pkg
+	"go.uber.org/multierr"
+	"golang.org/x/sync/errgroup"
 )
 
 // Version information (set by build flags)
@@ -59,10 +64,30 @@ func buildRootCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "skeletor",
 		Short: "Create new Porter mixins easily",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Env vars rank below the CLI --feature-gates flag and above
+			// template.json/built-in defaults, regardless of load order.
+			return DefaultFeatureGate.LoadFromEnv()
+		},
 	}
 
+	cmd.PersistentFlags().Var(DefaultFeatureGate, "feature-gates",
+		"A set of key=value pairs that describe feature gates for alpha/experimental features. "+
+			"Options are:\n"+DefaultFeatureGate.KnownFeatures())
+
 	cmd.AddCommand(buildCreateCommand())
 	cmd.AddCommand(buildVersionCommand())
+	cmd.AddCommand(buildFeaturesCommand())
+	cmd.AddCommand(buildComplianceCommand())
+	cmd.AddCommand(buildSourcesCommand())
+	cmd.AddCommand(buildSchemaCommand())
+	cmd.AddCommand(buildLicenseCommand())
+	cmd.AddCommand(buildListTemplatesCommand())
+	cmd.AddCommand(buildPluginCommand())
+	cmd.AddCommand(buildCacheCommand())
+	cmd.AddCommand(buildRenderCommand())
+	cmd.AddCommand(buildTemplateCommand())
+	cmd.AddCommand(buildVerifyCommand())
 	return cmd
 }
 
@@ -78,6 +103,25 @@ func buildVersionCommand() *cobra.Command {
 	}
 }
 
+// buildFeaturesCommand prints the feature gate registry as a table, similar
+// to `cscli features list`: name, stage, default, current value, the source
+// that set it, and its description.
+func buildFeaturesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "features",
+		Short: "List feature gates and their current values",
+		Run: func(cmd *cobra.Command, args []string) {
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSTAGE\tDEFAULT\tCURRENT\tSOURCE\tDESCRIPTION")
+			for _, status := range DefaultFeatureGate.GetEnabledFeatures() {
+				fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\t%s\n",
+					status.Name, status.Stage, status.Default, status.Current, status.Source, status.Description)
+			}
+			w.Flush()
+		},
+	}
+}
+
 func buildCreateCommand() *cobra.Command {
 	var (
 		name                  string
@@ -85,10 +129,16 @@ func buildCreateCommand() *cobra.Command {
 		modulePath            string
 		outputDir             string
 		nonInteractive        bool
+		interactiveModeRaw    string
+		kindRaw               string
 		templateUrl           string
+		templateRef           string
+		noCache               bool
+		refreshCache          bool
+		verifySignature       bool
+		cosignKey             string
 		templateDir           string
 		extraVars             []string
-		dryRun                bool   // Add dryRun variable
 		complianceLevel       string // Declare complianceLevel
 		enableSecurity        bool
 		enableCompliance      bool
@@ -98,36 +148,240 @@ func buildCreateCommand() *cobra.Command {
 		complianceFrameworks  string
 		authFeatures          string
 		observabilityFeatures string
+		configSources         []string
+		configOverrides       []string
+		explain               bool
+		failFastHooks         bool
+		hooksJSONSummary      bool
+		skipHooksRaw          string
+		onlyHooksRaw          string
+		templateSource        string
+		parallelism           int
+		onConflict            string
+		allowHooks            string
+		hookTrust             string
+		valuesFile            string
+		dryRun                string
+		policyDirs            []string
+		noBuiltinPolicy       bool
+		policyReportFile      string
+		authModel             string
+		authAdapter           string
+		authPolicySeed        []string
+		watch                 bool
+		live                  bool
+		liveTemplatePath      string
+		emitProvenance        bool
+		templateChecksum      string
+		fromLock              string
+		hookPluginDirs        []string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "create",
+		Use:   "create [template-name]",
 		Short: "Create a new Porter mixin",
+		Long:  "Create a new Porter mixin. template-name selects a named built-in flavor (see `skeletor list-templates`), defaulting to the standard built-in template; it is ignored when --template-dir/--template-url/--template-source picks an external source instead.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Determine the template source (embed, url, local dir)
-			tmplFS, rootDirForWalk, cleanupDir, err := getTemplateSource(templateUrl, templateDir)
+			var templateName string
+			if len(args) > 0 {
+				templateName = args[0]
+			}
+
+			// --live reads straight from disk instead of the embedded FS, so a
+			// template author iterating on cmd/skeletor/template doesn't have
+			// to rebuild the binary to see an edit take effect.
+			if live && templateDir == "" {
+				templateDir = liveTemplatePath
+			}
+			// --watch re-runs create on every change to the watched tree; the
+			// SKELETOR_LIVE_TEMPLATES=1 env var is equivalent, for an
+			// authoring shell profile that always wants it on.
+			watch = watch || os.Getenv("SKELETOR_LIVE_TEMPLATES") == "1"
+			if watch && templateDir == "" {
+				return fmt.Errorf("--watch (or SKELETOR_LIVE_TEMPLATES=1) requires --template-dir or --live to point at the template tree to watch")
+			}
+
+			kind, err := parseTemplateKind(kindRaw)
+			if err != nil {
+				return err
+			}
+
+			// --from-lock reproduces a prior `skeletor template lock` run:
+			// whichever template-selection flag wasn't given explicitly on
+			// this invocation is filled in from the lock, and the lock's
+			// checksum is enforced below once the template resolves, so a
+			// floating --template-url ref that moved on since locking is
+			// caught instead of silently generating from different content.
+			var lock *templateLock
+			if fromLock != "" {
+				loaded, err := readTemplateLock(fromLock)
+				if err != nil {
+					return fmt.Errorf("failed to read --from-lock %s: %w", fromLock, err)
+				}
+				lock = &loaded
+				if templateUrl == "" && templateSource == "" && templateDir == "" {
+					templateUrl = lock.TemplateURL
+					templateSource = lock.TemplateSource
+					templateDir = lock.TemplateDir
+				}
+				if templateRef == "" {
+					templateRef = lock.TemplateRef
+				}
+				if templateChecksum == "" {
+					templateChecksum = lock.TemplateChecksum
+				}
+			}
+
+			allowHooksLevel, err := parseAllowHooksLevel(allowHooks)
+			if err != nil {
+				return err
+			}
+			// A remote source (fetched over the network) is never
+			// "trusted" for AllowHooksTrusted purposes, unlike the
+			// embedded templates or a local --template-dir the user
+			// pointed at directly.
+			isRemoteTemplateSource := templateUrl != "" || templateSource != ""
+			verifySignature = verifySignature || cosignKey != ""
+			skipHooks := splitHookNames(skipHooksRaw)
+			onlyHooks := splitHookNames(onlyHooksRaw)
+
+			// Determine the template source (embed, url, local dir, or --template-source)
+			tmplFS, rootDirForWalk, cleanupDir, err := getTemplateSource(templateSource, templateUrl, templateDir, templateRef, templateName, kind, noCache, refreshCache, verifySignature, cosignKey)
 			if err != nil {
 				return err
 			}
-			// If cloned from URL, ensure cleanup
+
+			// If cloned from URL, ensure cleanup -- registered before the
+			// checksum check below so a mismatch still cleans up the
+			// freshly cloned/pulled temp directory instead of leaking it.
 			if cleanupDir != "" {
 				defer os.RemoveAll(cleanupDir)
 			}
 
+			// Hash the resolved template tree once, uniformly across every
+			// source (embedded, --template-dir, --template-url, or
+			// --template-source) so generated projects can record exactly
+			// which template content they came from, regardless of how it
+			// was fetched.
+			templateDigest, err := computeTreeDigest(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to compute template tree digest: %w", err)
+			}
+			if templateChecksum != "" && templateDigest != templateChecksum {
+				return fmt.Errorf("template checksum mismatch: expected %s, got %s (the resolved template ref no longer points at the content --template-checksum/--from-lock pinned)", templateChecksum, templateDigest)
+			}
+
 			// Load template configuration from the determined source
 			config, err := LoadTemplateConfig(tmplFS, rootDirForWalk) // Use rootDirForWalk here
 			if err != nil {
 				return fmt.Errorf("failed to load template config from %s: %w", rootDirForWalk, err)
 			}
+			if err := DefaultFeatureGate.SetFromTemplate(config.FeatureToggles); err != nil {
+				return fmt.Errorf("failed to apply feature toggles from %s: %w", rootDirForWalk, err)
+			}
+
+			// Layer org-wide/per-repo config files and CLI --config-override
+			// snippets on top of template.json, later sources winning.
+			if len(configSources) > 0 || len(configOverrides) > 0 {
+				layered, err := LoadLayeredConfig(LoadOpts{
+					DefaultConfig: config,
+					Sources:       parseConfigFileSources(configSources),
+					Overrides:     parseConfigOverrideSources(configOverrides),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to apply layered config: %w", err)
+				}
+				for _, warning := range layered.Warnings {
+					fmt.Printf("Warning: %s\n", warning)
+				}
+				config = layered.Config
+				if err := DefaultFeatureGate.SetFromTemplate(config.FeatureToggles); err != nil {
+					return fmt.Errorf("failed to apply feature toggles from layered config: %w", err)
+				}
+			}
+
+			// Evaluate each feature category's PolicyExpr (if any) against the
+			// template's variables and the process environment, recording a
+			// decision trace accessible via config.FeatureToggles.Decisions().
+			if config.FeatureToggles != nil {
+				config.FeatureToggles.SetPolicyInput(buildPolicyInput(config))
+				config.FeatureToggles.GetEnabledFeatures()
+			}
+			if explain {
+				printPolicyDecisions(config.FeatureToggles)
+				return nil
+			}
+
+			var values map[string]interface{}
+			if valuesFile != "" {
+				values, err = loadValuesFile(valuesFile)
+				if err != nil {
+					return err
+				}
+			}
+			// Lock-pinned variables fill in anything --values-file/--var
+			// didn't already supply on this invocation, so an explicit flag
+			// on the reproducing `create --from-lock` call still wins.
+			if lock != nil && len(lock.Variables) > 0 {
+				if values == nil {
+					values = make(map[string]interface{}, len(lock.Variables))
+				}
+				for k, v := range lock.Variables {
+					if _, exists := values[k]; !exists {
+						values[k] = v
+					}
+				}
+			}
+
+			// Load the template's own features.yaml, if it ships one, and
+			// enable any flag a --var/--values-file entry names truthy.
+			flags, err := LoadFeatureFlagSet(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load features.yaml from %s: %w", rootDirForWalk, err)
+			}
+			if err := applyFeatureFlagOverrides(flags, extraVars, values); err != nil {
+				return err
+			}
+
+			interactiveMode, err := parseInteractiveMode(interactiveModeRaw)
+			if err != nil {
+				return err
+			}
+			if interactiveMode == InteractiveModeTUI {
+				if nonInteractive {
+					return fmt.Errorf("--interactive=tui cannot be combined with --non-interactive")
+				}
+				rawVars, rawVarIndex, err := parseRawVars(extraVars)
+				if err != nil {
+					return err
+				}
+				wizardValues, err := runWizard(config, rawVars, rawVarIndex, extraVars, values)
+				if err != nil {
+					return err
+				}
+				if values == nil {
+					values = make(map[string]interface{}, len(wizardValues))
+				}
+				for varName, value := range wizardValues {
+					values[varName] = value
+				}
+				// The wizard already gathered every variable --var/env/
+				// --values-file left unresolved, so buildTemplateData's own
+				// sequential prompt fallback has nothing left to ask.
+				nonInteractive = true
+			}
 
 			// Create template data from config and flags
 			// Pass complianceLevel and feature toggles to buildTemplateData
-			data, err := buildTemplateDataWithFeatures(config, name, author, modulePath, outputDir, complianceLevel, nonInteractive, extraVars,
+			data, err := buildTemplateDataWithFeatures(config, name, author, modulePath, outputDir, complianceLevel, nonInteractive, extraVars, values,
+				flags,
 				enableSecurity, enableCompliance, enableAuth, enableObservability,
 				securityFeatures, complianceFrameworks, authFeatures, observabilityFeatures)
 			if err != nil {
 				return err
 			}
+			data["TemplateDigest"] = templateDigest
 
 			// Get the final output directory path from the template data
 			finalOutputDir, ok := data["OutputDir"].(string)
@@ -142,43 +396,243 @@ func buildCreateCommand() *cobra.Command {
 				fmt.Printf("Warning: OutputDir not found in template data, defaulting to %s\n", finalOutputDir)
 			}
 
+			// Sub-template composition: each config.Dependencies entry is
+			// resolved and generated into its own folder under
+			// finalOutputDir before the parent renders, so a parent
+			// template can reference .Dependencies.<name> (e.g. to check
+			// whether a composed "observability" sub-template contributed
+			// OpenTelemetry wiring) and so the files actually exist by the
+			// time anything in the parent tree might refer to them.
+			dependencies, err := resolveDependencies(&Engine{}, tmplFS, rootDirForWalk, config, data, finalOutputDir, complianceLevel, dryRun, parallelism, onConflict, nil)
+			if err != nil {
+				return err
+			}
+			if len(dependencies) > 0 {
+				data["Dependencies"] = dependencies
+			}
+
+			policyRules, err := loadPolicyRules(tmplFS, rootDirForWalk, config, policyDirs, !noBuiltinPolicy)
+			if err != nil {
+				return err
+			}
+			gate := &PolicyGateOptions{Rules: policyRules, ReportPath: policyReportFile}
+
+			// hooks.yaml is the declarative, CEL-`When`-gated sibling of the
+			// template.json-declared post_gen/validate hooks above; same
+			// trust gate (hooksAllowed), a separate manifest so a template
+			// author can use whichever shape fits.
+			declaredHooks, err := hooks.LoadManifest(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load %s from %s: %w", hooks.ManifestFilename, rootDirForWalk, err)
+			}
+
+			// Plugin pre_gen hooks (see pkg/plugin) run before the parent
+			// template writes anything (Dependencies sub-templates above are
+			// already generated by this point), same as the template's own config-declared hooks would
+			// if createMixin had a pre_gen stage today.
+			if dryRun == "" {
+				if err := runPluginHooks("pre_gen", finalOutputDir, data); err != nil {
+					return fmt.Errorf("plugin pre_gen hooks failed: %w", err)
+				}
+			}
+
+			// RBAC scaffolding: when auth-features includes rbac, resolve the
+			// Casbin model/adapter/seed (prompting for model and adapter in
+			// interactive mode, same as any other unset variable) and validate
+			// them up front so a bad combination fails before anything is
+			// written.
+			var authConfig *AuthConfig
+			if hasFeature(authFeatures, "rbac") {
+				if !nonInteractive {
+					authModel = promptStringWithDefault("Casbin auth model (RBAC, RBAC_with_domains, ABAC): ", authModel)
+					authAdapter = promptStringWithDefault("Casbin policy adapter (file, gorm, memory): ", authAdapter)
+				}
+				policySeed, err := parsePolicySeed(AuthModel(authModel), authPolicySeed)
+				if err != nil {
+					return err
+				}
+				authConfig = &AuthConfig{Model: AuthModel(authModel), Adapter: AuthAdapter(authAdapter), PolicySeed: policySeed}
+				if err := ValidateAuthConfig(authConfig); err != nil {
+					return err
+				}
+			}
+
 			// Create mixin from template using the determined source FS and root
 			// Pass dryRun variable and the finalOutputDir
-			if err := createMixin(data, tmplFS, rootDirForWalk, finalOutputDir, config, dryRun); err != nil {
+			if err := createMixin(data, tmplFS, rootDirForWalk, finalOutputDir, config, dryRun, parallelism, onConflict, gate); err != nil {
 				return err
 			}
 
 			// Run post-generation hooks or simulate if dry run
-			if dryRun {
+			if dryRun != "" {
+				if dryRun == string(DryRunJSON) {
+					return nil // The JSON plan is CreateMixin's whole dry-run output; nothing else to print.
+				}
 				fmt.Println("\n[Dry Run] Skipping post-generation hooks.")
 				if hooks, exists := config.Hooks["post_gen"]; exists && len(hooks) > 0 {
 					fmt.Println("[Dry Run] Would run the following hooks:")
-					for _, hookCmd := range hooks {
+					for _, hookStep := range hooks {
 						// Attempt to render hook command for better output, ignore errors
-						tmpl, err := template.New("hook-dry-run").Parse(hookCmd)
-						renderedCmd := hookCmd // Default to raw if template fails
+						tmpl, err := template.New("hook-dry-run").Parse(hookStep.Command)
+						renderedCmd := hookStep.Command // Default to raw if template fails
 						if err == nil {
 							var buf bytes.Buffer
 							if tmpl.Execute(&buf, data) == nil {
 								renderedCmd = buf.String()
 							}
 						}
-						fmt.Printf("  - %s\n", renderedCmd)
+						if hookStep.Image != "" {
+							fmt.Printf("  - %s (image: %s)\n", renderedCmd, hookStep.Image)
+						} else {
+							fmt.Printf("  - %s\n", renderedCmd)
+						}
+					}
+				}
+				if len(declaredHooks) > 0 {
+					fmt.Printf("[Dry Run] Would run the following %s hooks:\n", hooks.ManifestFilename)
+					for _, h := range declaredHooks {
+						fmt.Printf("  - %s\n", describeDeclaredHook(h))
 					}
 				}
 				// Final dry run message moved to end of createMixin when dryRun is true
 				return nil // Exit successfully after dry run simulation in createMixin
 			} else {
-				// Only run hooks if not a dry run
-				// Use finalOutputDir for running hooks
-				if err := RunHooks(config, "post_gen", finalOutputDir, data); err != nil {
-					return err // Return hook errors if they occur
+				// Only run hooks if not a dry run, and only for a source
+				// --allow-hooks trusts (see AllowHooksLevel).
+				var hookResults []HookResult
+				postGenHooks := config.Hooks["post_gen"]
+				validateHooks := config.Hooks["validate"]
+				// --hook-trust pins hookManifestDigest, which hashes all of
+				// config.Hooks -- both stages -- so it has to be checked
+				// once before either stage runs. Checking it only inside
+				// the post_gen branch (as a prior version of this did)
+				// meant a template defining validate hooks but no post_gen
+				// ones (the common case -- defaultTemplateConfig itself
+				// only populates validate) skipped the check entirely.
+				if (len(postGenHooks) > 0 || len(validateHooks) > 0) && hooksAllowed(allowHooksLevel, isRemoteTemplateSource) {
+					digest, err := verifyHookTrust(config, hookTrust)
+					if err != nil {
+						return err
+					}
+					if hookTrust == "" {
+						fmt.Printf("hook manifest digest: %s (pass --hook-trust %s next time to pin it)\n", digest, digest)
+					}
+				}
+
+				if hooksAllowed(allowHooksLevel, isRemoteTemplateSource) {
+					// Use finalOutputDir for running hooks
+					hookResults, err = RunHooksWithOptions(config, "post_gen", finalOutputDir, data, HookRunOptions{
+						FailFast:    failFastHooks,
+						JSONSummary: hooksJSONSummary,
+						Skip:        skipHooks,
+						Only:        onlyHooks,
+					})
+					if err != nil {
+						return err // Return hook errors if they occur
+					}
+				} else if len(postGenHooks) > 0 {
+					fmt.Printf("Skipping post_gen hooks (--allow-hooks=%s, template source is not trusted)\n", allowHooksLevel)
+				}
+
+				// Plugin post_gen hooks (see pkg/plugin) run regardless of
+				// --allow-hooks -- a plugin is something the user installed
+				// locally, not code shipped by the (possibly untrusted)
+				// template, so the template-trust gate above doesn't apply.
+				if err := runPluginHooks("post_gen", finalOutputDir, data); err != nil {
+					return fmt.Errorf("plugin post_gen hooks failed: %w", err)
+				}
+
+				// "validate" hooks are the declarative replacement for the
+				// old hardcoded go mod tidy/build/test sequence (see
+				// defaultTemplateConfig's default set); same trust gate as
+				// post_gen since a validate hook is just as capable of
+				// running arbitrary commands. --hook-trust was already
+				// checked above, covering this stage too.
+				if hooksAllowed(allowHooksLevel, isRemoteTemplateSource) {
+					validateResults, err := RunHooksWithOptions(config, "validate", finalOutputDir, data, HookRunOptions{
+						FailFast:    failFastHooks,
+						JSONSummary: hooksJSONSummary,
+						Skip:        skipHooks,
+						Only:        onlyHooks,
+					})
+					if err != nil {
+						return err
+					}
+					hookResults = append(hookResults, validateResults...)
+				} else if len(validateHooks) > 0 {
+					fmt.Printf("Skipping validate hooks (--allow-hooks=%s, template source is not trusted)\n", allowHooksLevel)
+				}
+
+				// hooks.yaml's declarative hooks run last, after the
+				// template.json-declared post_gen/validate stages above --
+				// same trust gate, so a hooks.yaml shipped by an untrusted
+				// remote template can't run arbitrary commands either.
+				if len(declaredHooks) > 0 {
+					if hooksAllowed(allowHooksLevel, isRemoteTemplateSource) {
+						runner := hooks.Runner{OutputDir: finalOutputDir, PluginDirs: hookPluginDirs}
+						declaredResults, err := runner.Run(cmd.Context(), declaredHooks, data)
+						for _, result := range declaredResults {
+							switch {
+							case result.Skipped:
+								fmt.Printf("Skipping %s hook %q (when condition was false)\n", hooks.ManifestFilename, result.Name)
+							case result.ExitCode != 0:
+								fmt.Printf("%s hook %q failed (exit %d)\n", hooks.ManifestFilename, result.Name, result.ExitCode)
+							default:
+								fmt.Printf("Ran %s hook %q\n", hooks.ManifestFilename, result.Name)
+							}
+						}
+						if err != nil {
+							return err
+						}
+					} else {
+						fmt.Printf("Skipping %s hooks (--allow-hooks=%s, template source is not trusted)\n", hooks.ManifestFilename, allowHooksLevel)
+					}
+				}
+
+				// Compliance-as-code: map enabled frameworks' PolicyConfig
+				// rules to controls, render their narratives, and link
+				// post_gen hook output back to each control as evidence.
+				manifest := BuildComplianceManifest(config, nil)
+				if err := WriteComplianceManifest(manifest, finalOutputDir); err != nil {
+					return err
+				}
+				if err := RenderComplianceNarratives(tmplFS, rootDirForWalk, config, finalOutputDir, data); err != nil {
+					return err
 				}
+				if err := CollectEvidence(manifest, hookResults, finalOutputDir); err != nil {
+					return err
+				}
+
+				if authConfig != nil {
+					if err := GenerateAuthScaffold(authConfig, finalOutputDir); err != nil {
+						return err
+					}
+				}
+
+				// --emit-provenance runs last, after every other step has
+				// finished writing to finalOutputDir, so the attestation's
+				// file digests cover the project exactly as it ships.
+				if emitProvenance {
+					origin := resolvedTemplateOrigin(templateSource, templateUrl, templateDir, kind)
+					stmt, err := buildProjectProvenance(finalOutputDir, origin, templateDigest, complianceLevel, data)
+					if err != nil {
+						return fmt.Errorf("failed to build provenance attestation: %w", err)
+					}
+					if err := writeProvenanceAttestation(stmt, finalOutputDir); err != nil {
+						return err
+					}
+					fmt.Printf("Wrote provenance attestation to %s\n", filepath.Join(finalOutputDir, provenanceAttestationPath))
+				}
+
 				// Print next steps only on successful non-dry run
 				fmt.Println("\nNext steps:")
 				fmt.Println("1. cd", finalOutputDir) // Use finalOutputDir here too
 				fmt.Println("2. Review the generated code and customize as needed.")
 				fmt.Println("3. Run 'mage build test' or 'go run ./ci' for further verification.")
+
+				if watch {
+					return watchAndRegenerate(templateDir, finalOutputDir, data, parallelism, onConflict, gate)
+				}
 				return nil
 			}
 		},
@@ -190,12 +644,24 @@ func buildCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&modulePath, "module", "", "Go module path (default: github.com/getporter/<name>)")
 	cmd.Flags().StringVar(&outputDir, "output", "", "Output directory (default: ./<name>)")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Run in non-interactive mode")
-	cmd.Flags().StringVar(&templateUrl, "template-url", "", "URL to a git repository containing the template")
+	cmd.Flags().StringVar(&interactiveModeRaw, "interactive", string(InteractiveModePrompt), "Front end for variables --var/SKELETOR_VAR_<NAME>/--values-file didn't already resolve: prompt (sequential, the default) or tui (full-screen wizard)")
+	cmd.Flags().StringVar(&kindRaw, "kind", string(TemplateKindMixin), "Built-in template tree to scaffold from: mixin (the default) or plugin; ignored when --template-dir/--template-url/--template-source picks an external source instead")
+	cmd.Flags().StringVar(&templateUrl, "template-url", "", "URL to a git repository containing the template, e.g. https://github.com/foo/bar.git, git+https://github.com/foo/bar.git@v1.0 (ref pinned inline), or a bare/oci:// OCI ref such as oci://ghcr.io/foo/template:v1.2.0")
+	cmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch, tag, or commit to check out from --template-url (default: the repository's default branch, or the ref embedded in a git+...@<ref> URL)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-clone --template-url into a temp dir instead of reusing the persistent template cache")
+	cmd.Flags().BoolVar(&refreshCache, "refresh", false, "Bypass a cached --template-url entry and re-fetch it even if the resolved commit is already cached")
+	cmd.Flags().StringVar(&templateChecksum, "template-checksum", "", "Expected template tree digest (sha256:...); fails generation if the resolved template doesn't match, e.g. because a --template-url ref moved")
+	cmd.Flags().StringVar(&fromLock, "from-lock", "", "Path to a skeletor.lock.yaml (see `skeletor template lock`) pinning the template origin, checksum, and variables; unset --template-url/--template-source/--template-dir/--template-ref/--template-checksum fall back to the lock's values")
 	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Local directory containing the template")
-	cmd.Flags().StringArrayVar(&extraVars, "var", []string{}, "Extra variables in KEY=VALUE format")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Simulate generation without writing files") // Add dry-run flag
+	cmd.Flags().StringVar(&templateSource, "template-source", "", "Template source URI, e.g. oci://ghcr.io/foo/template:v1.2.0, git://github.com/foo/bar.git#v1.0, http://example.com/template.tar.gz#sha256:..., dir:///local/path; takes priority over --template-dir/--template-url")
+	cmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "Require a valid cosign signature before using an oci://, docker://, or bare registry-ref --template-url (keyless Fulcio/Rekor verification unless --cosign-key is also given)")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "Public key file cosign should verify against; implies --verify-signature")
+	cmd.Flags().StringArrayVar(&extraVars, "var", []string{}, "Extra variables in KEY=VALUE format (outranks a SKELETOR_VAR_<NAME> environment variable or --values-file entry for the same variable)")
+	cmd.Flags().StringVar(&dryRun, "dry-run", "", "Simulate generation without writing files; bare --dry-run prints a plain-text plan (with unified diffs against any existing files), --dry-run=json prints a machine-readable plan instead")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = string(DryRunPlain)
 	// Use "basic" as default to match template.json, ensure choices match template.json
 	cmd.Flags().StringVar(&complianceLevel, "compliance-level", "basic", "Compliance level (basic, slsa-l1, slsa-l3)")
+	cmd.Flags().BoolVar(&emitProvenance, "emit-provenance", false, "Write an in-toto SLSA provenance statement for the generated project to attestations/provenance.intoto.jsonl; verify it later with `skeletor verify`")
 
 	// Enterprise feature toggle flags
 	cmd.Flags().BoolVar(&enableSecurity, "enable-security", false, "Enable enterprise security features")
@@ -207,14 +673,82 @@ func buildCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&complianceFrameworks, "compliance-frameworks", "", "Comma-separated list of compliance frameworks (soc2,gdpr,hipaa,pci_dss)")
 	cmd.Flags().StringVar(&authFeatures, "auth-features", "", "Comma-separated list of auth features (rbac,ldap,sso,mfa,vault,session_management)")
 	cmd.Flags().StringVar(&observabilityFeatures, "observability-features", "", "Comma-separated list of observability features (apm,infrastructure,custom_metrics,health_checks,opentelemetry,audit_logging,tracing)")
+	cmd.Flags().StringVar(&authModel, "auth-model", string(AuthModelRBAC), "Casbin model for the RBAC scaffold generated when auth-features includes rbac: RBAC, RBAC_with_domains, or ABAC")
+	cmd.Flags().StringVar(&authAdapter, "auth-adapter", string(AuthAdapterFile), "Where the RBAC scaffold's Casbin enforcer persists policy: file, gorm, or memory (ABAC is incompatible with file)")
+	cmd.Flags().StringArrayVar(&authPolicySeed, "auth-policy-seed", nil, "Seed policy.csv row as sub,obj,act (or sub,dom,obj,act for --auth-model=RBAC_with_domains); repeatable")
+
+	cmd.Flags().StringArrayVar(&configSources, "config-source", []string{}, "Additional config file to layer on top of template.json (format inferred from extension: .json, .hcl, .yaml); later sources win")
+	cmd.Flags().StringArrayVar(&configOverrides, "config-override", []string{}, "Inline config snippet to layer last, prefixed with its format, e.g. 'json:{\"compliance_level\":\"slsa-l3\"}'")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Print the policy decision trace for each feature category and exit without generating anything")
+	cmd.Flags().BoolVar(&failFastHooks, "fail-fast", false, "Stop the post_gen/validate hook chain at the first failure instead of running every hook and reporting a combined error")
+	cmd.Flags().BoolVar(&hooksJSONSummary, "hooks-json-summary", false, "Print a JSON summary of every post_gen/validate hook's status to stderr")
+	cmd.Flags().StringVar(&skipHooksRaw, "skip-hooks", "", "Comma-separated hook names (HookStep.Name) to skip instead of running")
+	cmd.Flags().StringVar(&onlyHooksRaw, "only-hooks", "", "Comma-separated hook names (HookStep.Name) to run exclusively, skipping every other hook; takes priority over --skip-hooks")
+	cmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of files to render concurrently (default: number of CPUs)")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", string(ConflictOverwrite), "What to do when a generated file already exists in the output directory: fail, overwrite, skip, merge, or prompt")
+	cmd.Flags().StringVar(&allowHooks, "allow-hooks", string(AllowHooksTrusted), "Which templates are allowed to run post_gen, validate and hooks.yaml hooks: none, trusted (embedded templates or --template-dir only), or all")
+	cmd.Flags().StringVar(&hookTrust, "hook-trust", "", "Require the template's hook manifest (every post_gen and validate hook in its config) to hash to this sha256 digest before running it (the digest is printed on a run that doesn't pin one)")
+	cmd.Flags().StringVar(&valuesFile, "values-file", "", "YAML or JSON file pre-populating variable values (outranked by a SKELETOR_VAR_<NAME> environment variable or --var for the same variable), validated against the template's variable schema just like any other source")
+	cmd.Flags().StringArrayVar(&policyDirs, "policy-dir", nil, "Directory of a policy.yaml manifest plus .rego modules (see pkg/policy) to gate generation with, on top of any template.json policy_rule_dirs; repeatable")
+	cmd.Flags().BoolVar(&noBuiltinPolicy, "no-builtin-policy", false, "Skip the bundled SOC2/GDPR/HIPAA policy rules, using only template.json policy_rule_dirs and --policy-dir")
+	cmd.Flags().StringVar(&policyReportFile, "policy-report-file", "", "File to append dryrun-scoped policy violations to, instead of discarding them")
+	cmd.Flags().StringArrayVar(&hookPluginDirs, "hook-plugin-dir", nil, "Directory to search for an executable matching a hooks.yaml hook's name when that name isn't one of pkg/hooks' built-ins (go-mod-tidy, gofmt, golangci-lint, git-init, goreleaser-check); repeatable")
+	cmd.Flags().BoolVar(&watch, "watch", false, "After generating, watch --template-dir (or --live-template-path with --live) with fsnotify and regenerate into the output directory on every change, printing a diff summary first; also enabled by SKELETOR_LIVE_TEMPLATES=1")
+	cmd.Flags().BoolVar(&live, "live", false, "Read the template from --live-template-path on disk instead of the embedded FS, even though one is present, so authors don't need to rebuild the binary between edits")
+	cmd.Flags().StringVar(&liveTemplatePath, "live-template-path", "template", "On-disk template directory --live reads from when --template-dir isn't also set")
 
 	return cmd
 }
 
+// buildPolicyInput assembles the input map a feature category's PolicyExpr
+// is evaluated against: each template variable's default value, then the
+// process environment (which wins on key collisions, since env vars are
+// normally used to override a variable's default at generation time).
+func buildPolicyInput(config *TemplateConfig) map[string]interface{} {
+	input := make(map[string]interface{}, len(config.Variables))
+	for name, v := range config.Variables {
+		input[name] = v.Default
+	}
+	for _, kv := range os.Environ() {
+		if key, value, found := strings.Cut(kv, "="); found {
+			input[key] = value
+		}
+	}
+	return input
+}
+
+// printPolicyDecisions prints the decision trace recorded by the most recent
+// GetEnabledFeatures call as a table, for `create --explain`.
+func printPolicyDecisions(ft *FeatureToggles) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tFEATURE\tENABLED\tEXPR\tREASON")
+	if ft != nil {
+		for _, decision := range ft.Decisions() {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n",
+				decision.Category, decision.Feature, decision.Enabled, decision.Expr, decision.Reason)
+		}
+	}
+	w.Flush()
+}
+
 // getTemplateSource determines the source filesystem and the root path for walking.
 // Returns fs.FS, root path for WalkDir, path to cleanup (if any), error.
-func getTemplateSource(templateUrl, templateDir string) (fs.FS, string, string, error) {
-	// Priority: Local Directory > URL > Embedded
+func getTemplateSource(templateSource, templateUrl, templateDir, templateRef, templateName string, kind TemplateKind, noCache, refreshCache, verifySignature bool, cosignKey string) (fs.FS, string, string, error) {
+	// Priority: --template-source > --template-dir > --template-url > named
+	// built-in (the create [template-name] positional arg) > default built-in.
+	if templateSource != "" {
+		// Every built-in TemplateSource caches under ~/.skeletor/sources and
+		// is meant to be reused across runs (see `skeletor sources`), so
+		// none of them return a cleanup func today -- unlike templateDir's
+		// cloned-from-URL path below, there's nothing for the caller to
+		// remove once generation finishes.
+		fsys, root, _, err := ResolveTemplateSourceWithOptions(templateSource, TemplateSourceOptions{VerifySignature: verifySignature, CosignKey: cosignKey})
+		if err != nil {
+			return nil, "", "", err
+		}
+		return fsys, root, "", nil
+	}
+
 	if templateDir != "" {
 		fileInfo, err := os.Stat(templateDir)
 		if err != nil {
@@ -231,30 +765,94 @@ func getTemplateSource(templateUrl, templateDir string) (fs.FS, string, string,
 	}
 
 	if templateUrl != "" {
-		tempDir, err := os.MkdirTemp("", "porter-template-*")
-		if err != nil {
-			return nil, "", "", fmt.Errorf("failed to create temp directory: %w", err)
+		if looksLikeOCITemplateRef(templateUrl) {
+			return resolveOCITemplateURL(templateUrl, noCache, refreshCache, verifySignature, cosignKey)
+		}
+		repoURL, inlineRef := parseGitPlusTemplateURL(templateUrl)
+		if inlineRef != "" && templateRef == "" {
+			templateRef = inlineRef
 		}
+		return resolveTemplateURL(repoURL, templateRef, noCache, refreshCache)
+	}
+
+	// Named built-in flavor, or the default built-in if templateName is empty.
+	root, err := builtinTemplateRoot(templateName, kind)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if templateName == "" || templateName == "default" {
+		fmt.Printf("Using embedded %s templates.\n", kind)
+	} else {
+		fmt.Printf("Using embedded %s template %q.\n", kind, templateName)
+	}
+	return builtinTemplateFS(kind), root, "", nil
+}
 
-		fmt.Printf("Fetching template from %s...\n", templateUrl)
-		// #nosec G204 -- URL is from user flag, tempDir is generated, command is allow-listed
-		cmd := createCommand("git", "clone", "--depth=1", templateUrl, tempDir)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			// Attempt to clean up temp dir even on clone failure, but ignore cleanup error
-			_ = os.RemoveAll(tempDir)
-			return nil, "", "", fmt.Errorf("failed to clone template repository: %w", err)
+// builtinTemplateRoot returns kind's embedded root path for the named
+// built-in template flavor, e.g. "template/minimal" for "minimal" under
+// TemplateKindMixin. The original single mixin flavor ships directly at
+// "template/template.json" with no "default" subdirectory, so an empty or
+// "default" name resolves to the bare base root when "<base>/default"
+// doesn't itself exist.
+func builtinTemplateRoot(name string, kind TemplateKind) (string, error) {
+	if name == "" {
+		name = "default"
+	}
+
+	tmplFS := builtinTemplateFS(kind)
+	base := builtinTemplateBase(kind)
+
+	namedRoot := path.Join(base, name)
+	if _, err := fs.Stat(tmplFS, path.Join(namedRoot, "template.json")); err == nil {
+		return namedRoot, nil
+	}
+	if name == "default" {
+		if _, err := fs.Stat(tmplFS, path.Join(base, "template.json")); err == nil {
+			return base, nil
 		}
-		fmt.Println("Using cloned template repository.")
-		// Return the OS FS rooted at the temp dir, root is ".", cleanup path is tempDir
-		return os.DirFS(tempDir), ".", tempDir, nil
 	}
 
-	// Default to the embedded templates
-	fmt.Println("Using embedded templates.")
-	// Return the embedded FS and specify "template" as the root directory within it
-	return pkg.MixinTemplateFS, "template", "", nil
+	names, listErr := listBuiltinTemplates(kind)
+	if listErr != nil || len(names) == 0 {
+		return "", fmt.Errorf("unknown built-in %s template %q", kind, name)
+	}
+	return "", fmt.Errorf("unknown built-in %s template %q (available: %s)", kind, name, strings.Join(names, ", "))
+}
+
+// listBuiltinTemplates enumerates every named flavor embedded under kind's
+// base root, each identified by its own "<base>/<name>/template.json", plus
+// "default" for the historical bare "<base>/template.json" flavor if present
+// and not itself also named as a subdirectory.
+func listBuiltinTemplates(kind TemplateKind) ([]string, error) {
+	tmplFS := builtinTemplateFS(kind)
+	base := builtinTemplateBase(kind)
+
+	entries, err := fs.ReadDir(tmplFS, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded %s templates: %w", kind, err)
+	}
+
+	seenDefault := false
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, statErr := fs.Stat(tmplFS, path.Join(base, entry.Name(), "template.json")); statErr == nil {
+			names = append(names, entry.Name())
+			if entry.Name() == "default" {
+				seenDefault = true
+			}
+		}
+	}
+	if !seenDefault {
+		if _, statErr := fs.Stat(tmplFS, path.Join(base, "template.json")); statErr == nil {
+			names = append(names, "default")
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
 }
 
 // Define custom template functions
@@ -265,6 +863,8 @@ var funcMap = template.FuncMap{
 	"splitFeatures":  splitFeatures,
 	"joinFeatures":   joinFeatures,
 	"featureEnabled": featureEnabled,
+	"enforcementFor": enforcementFor,
+	"hasEnforcement": hasEnforcement,
 	"default":        defaultValue,
 }
 
@@ -339,6 +939,25 @@ func featureEnabled(data map[string]interface{}, category, feature string) bool
 	return false
 }
 
+// enforcementFor returns the enforcement actions configured for
+// category.feature at scope (as strings, for easy use in templates), reading
+// the FeatureToggles stashed in template data by buildTemplateDataWithFeatures.
+func enforcementFor(data map[string]interface{}, category, feature, scope string) []string {
+	toggles, _ := data["FeatureToggles"].(*FeatureToggles)
+	actions := toggles.GetEnforcement(category, feature, EnforcementScope(scope))
+	result := make([]string, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, string(action))
+	}
+	return result
+}
+
+// hasEnforcement reports whether category.feature has any enforcement
+// action configured for scope.
+func hasEnforcement(data map[string]interface{}, category, feature, scope string) bool {
+	return len(enforcementFor(data, category, feature, scope)) > 0
+}
+
 // defaultValue provides a default value if the input is empty or nil
 func defaultValue(defaultVal, value interface{}) interface{} {
 	if value == nil {
@@ -359,25 +978,109 @@ func defaultValue(defaultVal, value interface{}) interface{} {
 
 // --- Refactored createMixin and Helper Functions ---
 
-// createMixin generates the mixin files from the template source or simulates if dryRun is true
-func createMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, outputDir string, config *TemplateConfig, dryRun bool) error {
-	if dryRun {
-		fmt.Println("[Dry Run] Simulating file generation...")
-	} else {
-		// Use 0750 permission as recommended by gosec G301
-		if err := os.MkdirAll(outputDir, 0750); err != nil {
-			return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
-		}
-		fmt.Println("Generating mixin files...")
+// createMixin generates the mixin files from the template source or
+// simulates it per dryRun (see DryRunMode). It is a thin wrapper around a
+// zero-value Engine's CreateMixin, for callers that don't need
+// RegisterFunc; embedders that do should construct their own Engine
+// instead.
+func createMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, outputDir string, config *TemplateConfig, dryRun string, parallelism int, onConflict string, gate *PolicyGateOptions) error {
+	return (&Engine{}).CreateMixin(data, tmplFS, templateRoot, outputDir, config, dryRun, parallelism, onConflict, gate)
+}
+
+// genWorkItem is one file or directory the template walk decided to produce,
+// queued up for the execute phase below rather than acted on immediately.
+type genWorkItem struct {
+	sourcePath string
+	destPath   string
+	info       fs.FileInfo
+	isDir      bool
+
+	// forceOverwrite is set by a matched PathRule carrying the "overwrite"
+	// capability. resolvePlanConflicts treats it as ConflictOverwrite for
+	// this item regardless of the run's global ConflictPolicy.
+	forceOverwrite bool
+
+	// destRelPath is destPath relative to outputDir (i.e. before it was
+	// joined on), the form LicensePolicy.Root patterns are matched against.
+	destRelPath string
+}
+
+// CreateMixin generates the mixin files from the template source or
+// simulates it per dryRun (see DryRunMode), parsing every .tmpl file (both
+// filenames and content) and conditional-path expression with e's merged
+// FuncMap: the package's built-in funcMap, config.Functions' built-in
+// helpers, and whatever e has had registered via RegisterFunc.
+//
+// Generation happens in two phases: fs.WalkDir only plans, building an
+// ordered list of genWorkItem entries (it still resolves conditional paths,
+// destination-path templating, and build constraints, since those decide
+// what belongs in the plan at all); then the plan is executed, creating
+// directories serially (fs.WalkDir visits a directory before its children,
+// so the plan order is already safe for this) followed by rendering files
+// across a bounded worker pool, parallelism wide, so the first failure
+// cancels the rest via errgroup. parallelism <= 0 defaults to
+// runtime.NumCPU().
+//
+// onConflict (see ConflictPolicy) governs what happens to a planned file
+// that already exists in outputDir, for re-running generation against a
+// mixin the user has since hand-edited ("cookiecutter-update"); an empty
+// string defaults to ConflictOverwrite, the historical behavior.
+// templateRoot's .mixingenignore file, if present, augments config.Ignore
+// with patterns that are never written even when the template ships them.
+//
+// gate, if non-nil, runs its Rego rules twice (see pkg/policy): once against
+// data itself before any planning happens, and once against the full
+// rendered file list just before that plan would be executed, so a deny
+// aborts before anything is written even if it only depends on a file's
+// rendered content rather than the input variables.
+func (e *Engine) CreateMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, outputDir string, config *TemplateConfig, dryRun string, parallelism int, onConflict string, gate *PolicyGateOptions) error {
+	conflictPolicy, err := parseConflictPolicy(onConflict)
+	if err != nil {
+		return err
+	}
+
+	dryRunMode, err := parseDryRunMode(dryRun)
+	if err != nil {
+		return err
+	}
+
+	if err := runPolicyGate(gate, data); err != nil {
+		return err
+	}
+
+	mixinGenIgnore, err := loadMixinGenIgnore(tmplFS, templateRoot)
+	if err != nil {
+		return err
+	}
+	ignorePatterns := append(append([]string{}, config.Ignore...), mixinGenIgnore...)
+
+	funcs, err := e.FuncMap(config)
+	if err != nil {
+		return err
+	}
+	// include reads another template file relative to templateRoot and
+	// renders it with the same data, gomplate-style; it's added here rather
+	// than to funcMap/builtinHelperFuncs because it needs tmplFS/templateRoot,
+	// which aren't available where those are built.
+	funcs["include"] = func(includePath string) (string, error) {
+		return renderInclude(tmplFS, templateRoot, includePath, data, funcs)
 	}
 
-	err := fs.WalkDir(tmplFS, templateRoot, func(path string, d fs.DirEntry, walkErr error) error {
+	pathRules, err := compileConfigPathPolicy(config)
+	if err != nil {
+		return err
+	}
+	fileConditionRules := compileFileConditions(config.FileConditions)
+
+	var plan []genWorkItem
+
+	walkErr := fs.WalkDir(tmplFS, templateRoot, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return fmt.Errorf("error walking template source at %s: %w", path, walkErr)
 		}
 
 		// Calculate destination path and check if the file/dir should be skipped
-		destRelPath, skip := calculateDestPath(path, templateRoot, config.Ignore)
+		destRelPath, skip := calculateDestPath(path, templateRoot, ignorePatterns)
 		if skip {
 			// Special case: if this is the template root directory itself, don't return fs.SkipDir
 			// because that would skip the entire tree. Just continue to the next iteration.
@@ -390,8 +1093,31 @@ func createMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, output
 			return nil // Skip ignored files
 		}
 
+		// config.SkipPatterns is the doublestar-powered successor to
+		// ignorePatterns' filepath.Match globs: a directory match prunes the
+		// whole subtree, a leaf match just drops that file.
+		if _, matched := evaluateSkipPatterns(config.SkipPatterns, destRelPath); matched {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		// config.FileConditions lets a template declare a Go template
+		// expression per glob pattern (e.g. "pkg/security/**": "{{
+		// .EnableSecurity }}") that's evaluated here, without needing a
+		// matching conditional_paths entry.
+		if _, keep, matched, condErr := evaluateFileCondition(fileConditionRules, destRelPath, data, funcs); condErr != nil {
+			return condErr
+		} else if matched && !keep {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
 		// Determine the actual source path and file info, handling conditional logic
-		sourcePath, info, skip, err := determineSourcePath(tmplFS, path, destRelPath, templateRoot, config.ConditionalPaths, data)
+		sourcePath, info, skip, err := determineSourcePath(tmplFS, path, destRelPath, templateRoot, config.ConditionalPaths, data, funcs)
 		if err != nil {
 			return err // Propagate errors from conditional path processing
 		}
@@ -402,8 +1128,38 @@ func createMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, output
 			return nil
 		}
 
+		// Consult the compiled path_policy matcher, which can further skip,
+		// rename, or force-overwrite a destination that survived the legacy
+		// ConditionalPaths check above.
+		forceOverwrite := false
+		if pathRules != nil {
+			toggles, _ := data["FeatureToggles"].(*FeatureToggles)
+			rule, matchErr := pathRules.Match(destRelPath, toggles, data)
+			if matchErr != nil {
+				return matchErr
+			}
+			if rule != nil {
+				if rule.HasCapability(CapabilitySkip) {
+					if info.IsDir() {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				if rule.HasCapability(CapabilityRename) {
+					renamed, renameErr := ResolveRenameDestination(rule, data, funcs)
+					if renameErr != nil {
+						return renameErr
+					}
+					destRelPath = renamed
+				}
+				if rule.HasCapability(CapabilityOverwrite) {
+					forceOverwrite = true
+				}
+			}
+		}
+
 		// Process the final destination path using template data
-		finalDestPath, err := processDestPath(destRelPath, outputDir, data)
+		finalDestPath, err := processDestPath(destRelPath, outputDir, data, funcs)
 		if err != nil {
 			return err
 		}
@@ -414,36 +1170,95 @@ func createMixin(data map[string]interface{}, tmplFS fs.FS, templateRoot, output
 			return nil
 		}
 
-		// Handle directory or file processing
-		if dryRun {
-			if info.IsDir() {
-				// Print for all directories except the root being walked (which is skipped earlier)
-				fmt.Printf("[Dry Run] Would create directory: %s\n", finalDestPath)
-			} else {
-				fmt.Printf("[Dry Run] Would write file: %s (from source %s)\n", finalDestPath, sourcePath)
+		// Evaluate any build-constraint expression gating this .tmpl file
+		// (sidecar ".constraints" file or a config.Constraints entry) before
+		// it's written or listed in dry-run output.
+		if !info.IsDir() && strings.HasSuffix(sourcePath, ".tmpl") {
+			expr, err := resolveFileConstraint(tmplFS, path, destRelPath, config.Constraints)
+			if err != nil {
+				return err
+			}
+			if expr != "" {
+				matched, err := EvaluateConstraint(expr, fileConstraintTags(data))
+				if err != nil {
+					return fmt.Errorf("constraint for %s: %w", destRelPath, err)
+				}
+				if !matched {
+					return nil
+				}
 			}
-			return nil // Skip actual processing in dry run mode
 		}
 
-		// Actual processing if not dry run
-		if info.IsDir() {
-			return processDirectory(finalDestPath, info)
-		} else {
-			return processTemplateFile(tmplFS, sourcePath, finalDestPath, info, data)
-		}
+		plan = append(plan, genWorkItem{sourcePath: sourcePath, destPath: finalDestPath, info: info, isDir: info.IsDir(), forceOverwrite: forceOverwrite, destRelPath: destRelPath})
+		return nil
 	})
+	if walkErr != nil {
+		return walkErr
+	}
 
-	if err != nil {
+	if gate != nil && len(gate.Rules) > 0 {
+		files := make([]map[string]interface{}, 0, len(plan))
+		for _, item := range plan {
+			if item.isDir {
+				continue
+			}
+			rendered, err := renderTemplateFileContent(tmplFS, item.sourcePath, item.destPath, data, funcs, config.Licenses, item.destRelPath)
+			if err != nil {
+				return err
+			}
+			files = append(files, map[string]interface{}{"path": item.destPath, "content": rendered})
+		}
+		if err := runPolicyGate(gate, map[string]interface{}{"files": files}); err != nil {
+			return err
+		}
+	}
+
+	if dryRunMode != DryRunOff {
+		return runDryRunPlan(tmplFS, plan, data, funcs, conflictPolicy, dryRunMode, config.Licenses)
+	}
+
+	// Use 0750 permission as recommended by gosec G301
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+	if err := writeLicenseManifest(config.Licenses, outputDir); err != nil {
 		return err
 	}
+	fmt.Println("Generating mixin files...")
+
+	// Directories first and serially, in walk order (parent before child), to
+	// avoid concurrent MkdirAll calls racing on the same intermediate paths.
+	for _, item := range plan {
+		if item.isDir {
+			if err := processDirectory(item.destPath, item.info); err != nil {
+				return err
+			}
+		}
+	}
 
-	// Run post-generation validation if not a dry run
-	if !dryRun {
-		return runPostGenerationValidation(outputDir)
+	resolvedPlan, err := resolvePlanConflicts(plan, conflictPolicy)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("\n[Dry Run] Simulation complete.")
-	return nil
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	group, groupCtx := errgroup.WithContext(context.Background())
+	group.SetLimit(parallelism)
+	for _, item := range resolvedPlan {
+		if item.isDir {
+			continue
+		}
+		if groupCtx.Err() != nil {
+			break // An earlier file failed; stop scheduling new work.
+		}
+		item := item
+		group.Go(func() error {
+			return processTemplateFile(tmplFS, item.sourcePath, item.destPath, item.info, data, funcs, config.Licenses, item.destRelPath)
+		})
+	}
+	return group.Wait()
 }
 
 // calculateDestPath determines the relative destination path and if it should be skipped.
@@ -460,8 +1275,21 @@ func calculateDestPath(originalPath, templateRoot string, ignorePatterns []strin
 		destRelPath = originalPath
 	}
 
-	// Skip template.json (check against original path within FS)
-	if originalPath == filepath.Join(templateRoot, "template.json") {
+	// Skip the template config file itself, whichever of
+	// templateConfigFilenames it is (check against original path within FS)
+	for _, filename := range templateConfigFilenames {
+		if originalPath == path.Join(templateRoot, filename) {
+			return "", true
+		}
+	}
+
+	// Skip .tmpl.constraints sidecar files -- metadata for resolveFileConstraint, not output
+	if strings.HasSuffix(originalPath, ".constraints") {
+		return "", true
+	}
+
+	// Skip the .mixingenignore file itself -- it augments ignorePatterns, it isn't output
+	if originalPath == path.Join(templateRoot, mixinGenIgnoreFilename) {
 		return "", true
 	}
 
@@ -482,7 +1310,7 @@ func calculateDestPath(originalPath, templateRoot string, ignorePatterns []strin
 }
 
 // determineSourcePath finds the correct source path and info, handling conditional logic.
-func determineSourcePath(tmplFS fs.FS, originalPath, destRelPath, templateRoot string, conditionalPaths map[string]string, data map[string]interface{}) (sourcePath string, fileInfo fs.FileInfo, skip bool, err error) {
+func determineSourcePath(tmplFS fs.FS, originalPath, destRelPath, templateRoot string, conditionalPaths map[string]ConditionalPath, data map[string]interface{}, funcs template.FuncMap) (sourcePath string, fileInfo fs.FileInfo, skip bool, err error) {
 	sourcePath = originalPath // Default source is the original path walked
 
 	// Get initial FileInfo using the original path
@@ -495,55 +1323,76 @@ func determineSourcePath(tmplFS fs.FS, originalPath, destRelPath, templateRoot s
 	fileInfo = initialFileInfo // Use this unless overridden by conditional logic
 
 	// Check conditional paths (key is relative to template structure root, which matches destRelPath)
-	if sourceTemplatePathTmplStr, exists := conditionalPaths[destRelPath]; exists {
-		sourceTemplatePathTmpl, parseErr := template.New("sourcePathCondition").Parse(sourceTemplatePathTmplStr)
-		if parseErr != nil {
-			err = fmt.Errorf("failed to parse conditional source path template for destination %s: %w", destRelPath, parseErr)
-			return
-		}
+	cond, exists := conditionalPaths[destRelPath]
+	if !exists {
+		return
+	}
 
-		var sourcePathBuf bytes.Buffer
-		if execErr := sourceTemplatePathTmpl.Execute(&sourcePathBuf, data); execErr != nil {
-			err = fmt.Errorf("failed to execute conditional source path template for destination %s: %w", destRelPath, execErr)
+	// Structured {"when": ...} form: a boolean DSL expression gates the
+	// destination as-is (a directory key gates its whole subtree) rather
+	// than redirecting to another source path.
+	if cond.When != "" {
+		toggles, _ := data["FeatureToggles"].(*FeatureToggles)
+		keep, condErr := EvaluateCondition(cond.When, toggles, data)
+		if condErr != nil {
+			err = fmt.Errorf("failed to evaluate condition for destination %s: %w", destRelPath, condErr)
 			return
 		}
-		evaluatedSourceRelPath := sourcePathBuf.String()
-
-		if evaluatedSourceRelPath == "" {
-			fmt.Printf("  Skipping destination %s (conditional source path evaluated to empty)\n", destRelPath)
+		if !keep {
+			fmt.Printf("  Skipping destination %s (condition %q is false)\n", destRelPath, cond.When)
 			skip = true
-			return // Return original fileInfo in case caller needs to check IsDir for fs.SkipDir
 		}
+		return
+	}
 
-		// Construct the actual source path within the FS
-		if templateRoot != "." {
-			sourcePath = filepath.Join(templateRoot, evaluatedSourceRelPath)
-		} else {
-			// When templateRoot is ".", evaluatedSourceRelPath is relative to the embedded root,
-			// but needs to be prefixed with "template/" to match the actual embedded path.
-			// This assumes conditional paths always resolve to something inside "template/".
-			sourcePath = filepath.Join("template", evaluatedSourceRelPath)
-		}
+	// Legacy form: cond.Template is a Go template that evaluates to the
+	// relative source path to use (or to empty, to skip).
+	sourceTemplatePathTmpl, parseErr := template.New("sourcePathCondition").Funcs(funcs).Option("missingkey=error").Parse(cond.Template)
+	if parseErr != nil {
+		err = fmt.Errorf("failed to parse conditional source path template for destination %s: %w", destRelPath, wrapUnknownFunctionError(parseErr, funcs))
+		return
+	}
 
-		// Stat the *actual* source path
-		newInfo, statErr := fs.Stat(tmplFS, sourcePath)
-		if statErr != nil {
-			fmt.Printf("  Warning: Conditional source path %s (evaluated from %s) for destination %s does not exist in FS. Skipping.\n", sourcePath, evaluatedSourceRelPath, destRelPath)
-			skip = true
-			err = nil // Treat as skip, not error
-			return    // Return initialFileInfo
-		}
-		fileInfo = newInfo // Update fileInfo ONLY if conditional path is valid
+	var sourcePathBuf bytes.Buffer
+	if execErr := sourceTemplatePathTmpl.Execute(&sourcePathBuf, data); execErr != nil {
+		err = fmt.Errorf("failed to execute conditional source path template for destination %s: %w", destRelPath, execErr)
+		return
+	}
+	evaluatedSourceRelPath := sourcePathBuf.String()
+
+	if evaluatedSourceRelPath == "" {
+		fmt.Printf("  Skipping destination %s (conditional source path evaluated to empty)\n", destRelPath)
+		skip = true
+		return // Return original fileInfo in case caller needs to check IsDir for fs.SkipDir
 	}
-	// If no conditional path matched or was processed, fileInfo remains initialFileInfo
+
+	// Construct the actual source path within the FS
+	if templateRoot != "." {
+		sourcePath = path.Join(templateRoot, evaluatedSourceRelPath)
+	} else {
+		// When templateRoot is ".", evaluatedSourceRelPath is relative to the embedded root,
+		// but needs to be prefixed with "template/" to match the actual embedded path.
+		// This assumes conditional paths always resolve to something inside "template/".
+		sourcePath = path.Join("template", evaluatedSourceRelPath)
+	}
+
+	// Stat the *actual* source path
+	newInfo, statErr := fs.Stat(tmplFS, sourcePath)
+	if statErr != nil {
+		fmt.Printf("  Warning: Conditional source path %s (evaluated from %s) for destination %s does not exist in FS. Skipping.\n", sourcePath, evaluatedSourceRelPath, destRelPath)
+		skip = true
+		err = nil // Treat as skip, not error
+		return    // Return initialFileInfo
+	}
+	fileInfo = newInfo // Update fileInfo ONLY if conditional path is valid
 	return
 }
 
 // processDestPath processes the relative destination path with template data.
-func processDestPath(destRelPath, outputDir string, data map[string]interface{}) (string, error) {
-	destPathTemplate, err := template.New("destPath").Parse(destRelPath)
+func processDestPath(destRelPath, outputDir string, data map[string]interface{}, funcs template.FuncMap) (string, error) {
+	destPathTemplate, err := template.New("destPath").Funcs(funcs).Option("missingkey=error").Parse(destRelPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse destination path template for %s: %w", destRelPath, err)
+		return "", fmt.Errorf("failed to parse destination path template for %s: %w", destRelPath, wrapUnknownFunctionError(err, funcs))
 	}
 	var destPathBuf bytes.Buffer
 	if err := destPathTemplate.Execute(&destPathBuf, data); err != nil {
@@ -571,25 +1420,50 @@ func processDirectory(destPath string, info fs.FileInfo) error {
 
 // processTemplateFile handles reading, templating, and writing a single file.
 // Removed dryRun parameter
-func processTemplateFile(tmplFS fs.FS, sourcePath, destPath string, info fs.FileInfo, data map[string]interface{}) error {
+func processTemplateFile(tmplFS fs.FS, sourcePath, destPath string, info fs.FileInfo, data map[string]interface{}, funcs template.FuncMap, licensePolicies []LicensePolicy, destRelPath string) error {
+	processedContent, err := renderTemplateFileContent(tmplFS, sourcePath, destPath, data, funcs, licensePolicies, destRelPath)
+	if err != nil {
+		return err
+	}
+
+	// Write the final content
+	// Use 0600 for files as recommended by gosec G306 (owner rw only)
+	if err := os.WriteFile(destPath, []byte(processedContent), 0600); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", destPath, err)
+	}
+	return nil
+}
+
+// renderTemplateFileContent reads sourcePath from tmplFS and renders it the
+// same way processTemplateFile would write it -- feature blocks expanded,
+// templated if sourcePath ends in .tmpl, Go-specific replacements applied --
+// without touching disk, so a dry run can diff it against destPath's
+// current content.
+func renderTemplateFileContent(tmplFS fs.FS, sourcePath, destPath string, data map[string]interface{}, funcs template.FuncMap, licensePolicies []LicensePolicy, destRelPath string) (string, error) {
 	// Read source content
 	content, err := fs.ReadFile(tmplFS, sourcePath)
 	if err != nil {
-		return fmt.Errorf("failed to read source file %s from FS: %w", sourcePath, err)
+		return "", fmt.Errorf("failed to read source file %s from FS: %w", sourcePath, err)
 	}
 
 	processedContent := string(content)
 	destRelPathForTemplateName := filepath.Base(destPath) // Use filename part for template name
 
+	// Expand {{#if feature "..."}}...{{/if}} content blocks before the Go
+	// template below ever sees them -- that syntax isn't valid text/template
+	// and would otherwise fail to parse.
+	toggles, _ := data["FeatureToggles"].(*FeatureToggles)
+	processedContent = preprocessFeatureBlocks(processedContent, toggles)
+
 	// Process as template only if it had .tmpl extension
 	if strings.HasSuffix(sourcePath, ".tmpl") { // Check original source path for .tmpl
-		tmpl, parseErr := template.New(destRelPathForTemplateName).Funcs(funcMap).Parse(string(content))
+		tmpl, parseErr := template.New(destRelPathForTemplateName).Funcs(funcs).Option("missingkey=error").Parse(processedContent)
 		if parseErr != nil {
-			return fmt.Errorf("failed to parse content template for %s (source %s): %w", destRelPathForTemplateName, sourcePath, parseErr)
+			return "", fmt.Errorf("failed to parse content template for %s (source %s): %w", destRelPathForTemplateName, sourcePath, wrapUnknownFunctionError(parseErr, funcs))
 		}
 		var templatedContentBuf bytes.Buffer
 		if execErr := tmpl.Execute(&templatedContentBuf, data); execErr != nil {
-			return fmt.Errorf("failed to execute content template for %s (source %s): %w", destRelPathForTemplateName, sourcePath, execErr)
+			return "", fmt.Errorf("failed to execute content template for %s (source %s): %w", destRelPathForTemplateName, sourcePath, execErr)
 		}
 		processedContent = templatedContentBuf.String()
 	} // End of if strings.HasSuffix(sourcePath, ".tmpl")
@@ -597,12 +1471,46 @@ func processTemplateFile(tmplFS fs.FS, sourcePath, destPath string, info fs.File
 	// Apply Go-specific replacements (use destRelPathForTemplateName which is just the filename)
 	processedContent = applyGoSpecificReplacements(processedContent, destRelPathForTemplateName, data)
 
-	// Write the final content
-	// Use 0600 for files as recommended by gosec G306 (owner rw only)
-	if err := os.WriteFile(destPath, []byte(processedContent), 0600); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", destPath, err)
+	// Inject this destination's license header, if a LicensePolicy's Root
+	// matches it, after every other rendering step and before the content
+	// is written or diffed.
+	if len(licensePolicies) > 0 {
+		policy, err := matchLicensePolicy(licensePolicies, destRelPath)
+		if err != nil {
+			return "", err
+		}
+		if policy != nil {
+			header, err := renderLicenseHeader(policy, data, funcs)
+			if err != nil {
+				return "", err
+			}
+			processedContent = injectLicenseHeader(processedContent, header, policy.SPDX)
+		}
 	}
-	return nil
+
+	return processedContent, nil
+}
+
+// renderInclude backs the "include" template function: it reads includePath
+// (relative to templateRoot, same as any other source file) out of tmplFS
+// and renders it as a template against data, so a template can factor out
+// a shared snippet instead of repeating it, gomplate-style.
+func renderInclude(tmplFS fs.FS, templateRoot, includePath string, data map[string]interface{}, funcs template.FuncMap) (string, error) {
+	fullPath := path.Join(templateRoot, includePath)
+	content, err := fs.ReadFile(tmplFS, fullPath)
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", includePath, err)
+	}
+
+	tmpl, err := template.New(path.Base(includePath)).Funcs(funcs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", includePath, wrapUnknownFunctionError(err, funcs))
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("include %q: %w", includePath, err)
+	}
+	return buf.String(), nil
 }
 
 // applyGoSpecificReplacements performs string replacements specific to Go files.
@@ -639,28 +1547,7 @@ func applyGoSpecificReplacements(content, destRelPath string, data map[string]in
 	return content
 }
 
-// runPostGenerationValidation executes validation commands in the output directory.
-func runPostGenerationValidation(outputDir string) error {
-	fmt.Println("\nRunning post-generation validation...")
-	commands := [][]string{
-		{"go", "mod", "tidy"},
-		{"go", "build", "./..."},
-		{"go", "test", "./..."},
-	}
-
-	for _, cmdArgs := range commands {
-		if err := runCommandInDir(outputDir, cmdArgs[0], cmdArgs[1:]...); err != nil {
-			// Log warning but continue validation
-			fmt.Printf("Warning: '%s' failed: %v\n", strings.Join(cmdArgs, " "), err)
-		} else {
-			fmt.Printf("  - %s: OK\n", strings.Join(cmdArgs, " "))
-		}
-	}
-	fmt.Println("\nValidation complete.")
-	return nil // Don't return error from validation failures, just warn
-}
-
-// --- Other helper functions (promptString, promptStringWithDefault, capitalize, runCommandInDir, buildTemplateData) remain the same ---
+// --- Other helper functions (promptString, promptStringWithDefault, capitalize, buildTemplateData) remain the same ---
 
 func promptString(prompt string) string {
 	reader := bufio.NewReader(os.Stdin)
@@ -687,32 +1574,35 @@ func capitalize(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-func runCommandInDir(dir string, command string, args ...string) error {
-	cmd := exec.Command(command, args...)
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	fmt.Printf("  Running '%s %s' in %s...\n", command, strings.Join(args, " "), dir)
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("command '%s %s' failed: %w", command, strings.Join(args, " "), err)
+// buildTemplateData resolves every declared variable from its sources, in
+// order: the dedicated name/author/modulePath CLI flags, then (via
+// resolveVariable) --var, a SKELETOR_VAR_<NAME> environment variable, and
+// values pre-populated from --values-file. When none of those produced a
+// value, an interactive prompt (offering a declared Default as its
+// pre-filled answer) is shown if nonInteractive is false; otherwise the
+// declared Default is used.
+func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputDir, complianceLevel string, nonInteractive bool, extraVars []string, values map[string]interface{}) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	for k, v := range values {
+		data[k] = v // ad-hoc values-file entries flow through as-is, same as ad-hoc --var entries below
 	}
-	return nil
-}
 
-// Update buildTemplateData signature and logic
-func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputDir, complianceLevel string, nonInteractive bool, extraVars []string) (map[string]interface{}, error) {
-	data := make(map[string]interface{})
+	// Needed to render {{ ... }} default values below with the same FuncMap
+	// (and missingkey=error typo safety) createMixin renders files with.
+	funcs, err := (&Engine{}).FuncMap(config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add compliance level first so it can be used in default value templates
 	data["ComplianceLevel"] = complianceLevel
 
-	for _, varDef := range extraVars {
-		parts := strings.SplitN(varDef, "=", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid variable format: %s (expected KEY=VALUE)", varDef)
-		}
-		data[parts[0]] = parts[1]
+	rawVars, rawVarIndex, err := parseRawVars(extraVars)
+	if err != nil {
+		return nil, err
+	}
+	for varName, raw := range rawVars {
+		data[varName] = raw // ad-hoc vars not declared in config.Variables still flow through as-is
 	}
 	if name != "" {
 		data["MixinName"] = name
@@ -724,16 +1614,84 @@ func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputD
 		data["ModulePath"] = modulePath
 	}
 
-	for varName, varConfig := range config.Variables {
-		if _, exists := data[varName]; exists {
+	// flagShortcut reports whether varName was supplied via a dedicated CLI
+	// flag (name/author/modulePath) rather than --var/interactive/default;
+	// those flags always win and, like before schema validation existed,
+	// aren't validated against the variable's schema.
+	flagShortcut := func(varName string) bool {
+		switch varName {
+		case "MixinName":
+			return name != ""
+		case "AuthorName":
+			return author != ""
+		case "ModulePath":
+			return modulePath != ""
+		default:
+			return false
+		}
+	}
+
+	// skipped records variables OnlyIf/SkipIf gated out of data, so the
+	// required-variable sweep below doesn't flag them as missing.
+	skipped := make(map[string]bool)
+	// errs accumulates every variable's failure instead of aborting on the
+	// first, so --non-interactive runs (and template authors testing a
+	// values-file) see every problem at once rather than fixing them one at
+	// a time across repeated invocations.
+	var errs error
+
+	// Variables are walked in sorted name order rather than config.Variables'
+	// map order, so a variable's OnlyIf/SkipIf/Validation.Expr can reliably
+	// reference another variable resolved earlier in the same run instead of
+	// depending on Go's randomized map iteration.
+	varNames := make([]string, 0, len(config.Variables))
+	for varName := range config.Variables {
+		varNames = append(varNames, varName)
+	}
+	sort.Strings(varNames)
+
+	for _, varName := range varNames {
+		varConfig := config.Variables[varName]
+		if flagShortcut(varName) {
+			continue
+		}
+
+		if skip, err := variableSkipped(varConfig, data); err != nil {
+			errs = multierr.Append(errs, NewConfigError(config.locationOf(varName), err))
+			continue
+		} else if skip {
+			skipped[varName] = true
+			continue
+		}
+
+		schema := effectiveVariableSchema(varConfig)
+
+		if source := resolveVariable(varName, rawVars, rawVarIndex, extraVars, values); source.Found {
+			value := source.Typed
+			err := error(nil)
+			if !source.IsTyped {
+				value, err = coerceToSchema(varName, schema, source.Raw)
+			}
+			if err == nil {
+				err = validateAgainstSchema(varName, schema, value)
+			}
+			if err == nil {
+				err = validateVariableExtra(varName, varConfig.Validation, value, data)
+			}
+			if err != nil {
+				errs = multierr.Append(errs, NewConfigError(source.Loc, err))
+				continue
+			}
+			data[varName] = value
 			continue
 		}
+
 		if !nonInteractive {
 			var defaultValue string
 			if varConfig.Default != nil {
 				defaultValue = fmt.Sprintf("%v", varConfig.Default)
 				if strings.Contains(defaultValue, "{{") {
-					tmpl, err := template.New("default").Parse(defaultValue)
+					tmpl, err := template.New("default").Funcs(funcs).Option("missingkey=error").Parse(defaultValue)
 					if err == nil {
 						var buf bytes.Buffer
 						if err := tmpl.Execute(&buf, data); err == nil {
@@ -744,8 +1702,8 @@ func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputD
 			}
 			for {
 				prompt := varConfig.Description
-				if varConfig.Choices != nil && len(varConfig.Choices) > 0 {
-					prompt = fmt.Sprintf("%s %v", prompt, varConfig.Choices)
+				if len(schema.Enum) > 0 {
+					prompt = fmt.Sprintf("%s %v", prompt, schema.Enum)
 				}
 				var rawValue string
 				if defaultValue != "" {
@@ -753,51 +1711,38 @@ func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputD
 				} else {
 					rawValue = promptString(prompt + ": ")
 				}
-				if varConfig.Choices != nil && len(varConfig.Choices) > 0 {
-					isValidChoice := false
-					for _, choice := range varConfig.Choices {
-						if rawValue == choice {
-							isValidChoice = true
-							break
-						}
-					}
-					if !isValidChoice {
-						fmt.Printf("  Error: Invalid choice. Please select one of %v\n", varConfig.Choices)
-						continue
-					}
+				value, err := coerceToSchema(varName, schema, rawValue)
+				if err == nil {
+					err = validateAgainstSchema(varName, schema, value)
 				}
-				var validatedValue interface{}
-				var validationErr error
-				switch strings.ToLower(varConfig.Type) {
-				case "bool", "boolean":
-					validatedValue, validationErr = strconv.ParseBool(rawValue)
-					if validationErr != nil {
-						validationErr = fmt.Errorf("invalid boolean value (try true/false, 1/0)")
-					}
-				case "int", "integer":
-					validatedValue, validationErr = strconv.Atoi(rawValue)
-					if validationErr != nil {
-						validationErr = fmt.Errorf("invalid integer value")
-					}
-				case "string", "":
-					validatedValue = rawValue
-				default:
-					fmt.Printf("  Warning: Unknown variable type '%s' for '%s', treating as string.\n", varConfig.Type, varName)
-					validatedValue = rawValue
-				}
-				if validationErr != nil {
-					fmt.Printf("  Error: %v\n", validationErr)
+				if err == nil {
+					err = validateVariableExtra(varName, varConfig.Validation, value, data)
+				}
+				if err != nil {
+					fmt.Printf("  Error: %v\n", err)
 					continue
 				}
-				data[varName] = validatedValue
+				data[varName] = value
 				break
 			}
 		} else if varConfig.Default != nil {
-			data[varName] = varConfig.Default
-		} else if varConfig.Required {
-			return nil, fmt.Errorf("required variable %s is not provided", varName)
+			value := varConfig.Default
+			err := validateAgainstSchema(varName, schema, value)
+			if err == nil {
+				err = validateVariableExtra(varName, varConfig.Validation, value, data)
+			}
+			if err != nil {
+				errs = multierr.Append(errs, NewConfigError(config.locationOf(varName), err))
+				continue
+			}
+			data[varName] = value
+		} else if schema.Required {
+			errs = multierr.Append(errs, NewConfigError(config.locationOf(varName), fmt.Errorf("required variable %s is not provided", varName)))
 		}
 	}
+	if errs != nil {
+		return nil, errs
+	}
 	if mixinName, ok := data["MixinName"].(string); ok {
 		sanitizedName := strings.ReplaceAll(mixinName, "-", "")
 		data["MixinName"] = mixinName              // Keep original for paths and module name
@@ -813,9 +1758,12 @@ func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputD
 		}
 	}
 	for varName, varConfig := range config.Variables {
+		if skipped[varName] {
+			continue
+		}
 		if varConfig.Required {
 			if _, exists := data[varName]; !exists || data[varName] == "" {
-				return nil, fmt.Errorf("required variable %s is not provided", varName)
+				return nil, NewConfigError(config.locationOf(varName), fmt.Errorf("required variable %s is not provided", varName))
 			}
 		}
 	}
@@ -851,13 +1799,226 @@ func buildTemplateData(config *TemplateConfig, name, author, modulePath, outputD
 	return data, nil
 }
 
+// effectiveVariableSchema returns varConfig.Schema if set, or else a
+// compatibility shim synthesized from its legacy Type/Choices/Required
+// fields, so template.json files written before "schema" existed validate
+// identically.
+func effectiveVariableSchema(varConfig Variable) VariableSchema {
+	if varConfig.Schema != nil {
+		schema := *varConfig.Schema
+		if varConfig.Required {
+			schema.Required = true
+		}
+		return schema
+	}
+	schema := VariableSchema{Type: varConfig.Type, Required: varConfig.Required}
+	for _, choice := range varConfig.Choices {
+		schema.Enum = append(schema.Enum, choice)
+	}
+	return schema
+}
+
+// coerceToSchema parses raw (a --var or interactively entered string) into
+// the Go type schema.Type declares -- bool/boolean, int/integer, number,
+// array, object, or string (the default) -- the same primitive types
+// cnab-go's bundle/definition package coerces bundle parameters to before
+// validating them.
+func coerceToSchema(varName string, schema VariableSchema, raw string) (interface{}, error) {
+	switch strings.ToLower(schema.Type) {
+	case "bool", "boolean":
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid boolean value (try true/false, 1/0)", varName)
+		}
+		return value, nil
+	case "int", "integer":
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid integer value", varName)
+		}
+		return value, nil
+	case "number":
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid number value", varName)
+		}
+		return value, nil
+	case "array":
+		var value []interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("variable %s: invalid array value, expected JSON (e.g. [\"a\",\"b\"]): %w", varName, err)
+		}
+		return value, nil
+	case "object":
+		var value map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("variable %s: invalid object value, expected JSON (e.g. {\"k\":\"v\"}): %w", varName, err)
+		}
+		return value, nil
+	case "list":
+		return coerceListValue(varName, raw)
+	case "map":
+		return coerceMapValue(varName, raw)
+	case "string", "":
+		return raw, nil
+	default:
+		fmt.Printf("  Warning: Unknown variable type '%s' for '%s', treating as string.\n", schema.Type, varName)
+		return raw, nil
+	}
+}
+
+// coerceListValue parses raw as a "list" variable: JSON (e.g. ["a","b"])
+// when it looks like a JSON array, otherwise a comma-separated list of
+// scalars (e.g. "a,b,c") -- the latter is the form a --var or an
+// interactive prompt is actually going to see most of the time.
+func coerceListValue(varName, raw string) (interface{}, error) {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") {
+		var value []interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("variable %s: invalid list value, expected a JSON array or comma-separated entries: %w", varName, err)
+		}
+		return value, nil
+	}
+	if trimmed == "" {
+		return []interface{}{}, nil
+	}
+	parts := strings.Split(trimmed, ",")
+	value := make([]interface{}, len(parts))
+	for i, part := range parts {
+		value[i] = strings.TrimSpace(part)
+	}
+	return value, nil
+}
+
+// coerceMapValue parses raw as a "map" variable: JSON (e.g. {"k":"v"}) when
+// it looks like a JSON object, otherwise comma-separated key=value pairs
+// (e.g. "k1=v1,k2=v2") -- the latter is the form a --var or an interactive
+// prompt is actually going to see most of the time.
+func coerceMapValue(varName, raw string) (interface{}, error) {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") {
+		var value map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("variable %s: invalid map value, expected a JSON object or comma-separated key=value pairs: %w", varName, err)
+		}
+		return value, nil
+	}
+	value := map[string]interface{}{}
+	if trimmed == "" {
+		return value, nil
+	}
+	for _, pair := range strings.Split(trimmed, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("variable %s: invalid map entry %q, expected key=value", varName, pair)
+		}
+		value[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return value, nil
+}
+
+// validateEnumMember reports whether value matches one of enum's entries by
+// its string form, the same loose comparison validateAgainstSchema always
+// used, now shared between a scalar value and each element of a "list"
+// value.
+func validateEnumMember(varName string, enum []interface{}, value interface{}) error {
+	for _, choice := range enum {
+		if fmt.Sprintf("%v", choice) == fmt.Sprintf("%v", value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("variable %s: invalid choice %v, must be one of %v", varName, value, enum)
+}
+
+// validateAgainstSchema checks value -- already coerced to its declared Go
+// type -- against schema's enum/pattern/minimum/maximum/minLength/maxLength
+// keywords, surfacing the variable name and offending keyword in the error,
+// e.g. "variable MyInt: value 999 exceeds maximum 100".
+func validateAgainstSchema(varName string, schema VariableSchema, value interface{}) error {
+	if len(schema.Enum) > 0 {
+		// A "list"-typed value's elements are checked individually against
+		// Enum -- list+Choices means "choose any of these", not "the whole
+		// list must itself be a single declared choice".
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				if err := validateEnumMember(varName, schema.Enum, v); err != nil {
+					return err
+				}
+			}
+		} else if err := validateEnumMember(varName, schema.Enum, value); err != nil {
+			return err
+		}
+	}
+
+	if schema.Pattern != "" {
+		if str, ok := value.(string); ok {
+			matched, err := regexp.MatchString(schema.Pattern, str)
+			if err != nil {
+				return fmt.Errorf("variable %s: invalid schema pattern %q: %w", varName, schema.Pattern, err)
+			}
+			if !matched {
+				return fmt.Errorf("variable %s: value %q does not match pattern %q", varName, str, schema.Pattern)
+			}
+		}
+	}
+
+	if num, ok := schemaNumericValue(value); ok {
+		if schema.Minimum != nil && num < *schema.Minimum {
+			return fmt.Errorf("variable %s: value %v is below minimum %v", varName, value, *schema.Minimum)
+		}
+		if schema.Maximum != nil && num > *schema.Maximum {
+			return fmt.Errorf("variable %s: value %v exceeds maximum %v", varName, value, *schema.Maximum)
+		}
+	}
+
+	if length, ok := schemaLength(value); ok {
+		if schema.MinLength != nil && length < *schema.MinLength {
+			return fmt.Errorf("variable %s: length %d is below minLength %d", varName, length, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			return fmt.Errorf("variable %s: length %d exceeds maxLength %d", varName, length, *schema.MaxLength)
+		}
+	}
+
+	return nil
+}
+
+// schemaNumericValue extracts a float64 from value if it's an int or
+// float64 (the two types coerceToSchema and JSON config defaults produce
+// for numeric variables), for minimum/maximum checks.
+func schemaNumericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// schemaLength extracts a length from value if it's a string, array or map, for
+// minLength/maxLength checks.
+func schemaLength(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return len(v), true
+	case []interface{}:
+		return len(v), true
+	case map[string]interface{}:
+		return len(v), true
+	}
+	return 0, false
+}
+
 // buildTemplateDataWithFeatures creates template data with enterprise feature toggles
-func buildTemplateDataWithFeatures(config *TemplateConfig, name, author, modulePath, outputDir, complianceLevel string, nonInteractive bool, extraVars []string,
+func buildTemplateDataWithFeatures(config *TemplateConfig, name, author, modulePath, outputDir, complianceLevel string, nonInteractive bool, extraVars []string, values map[string]interface{},
+	flags *FeatureFlagSet,
 	enableSecurity, enableCompliance, enableAuth, enableObservability bool,
 	securityFeatures, complianceFrameworks, authFeatures, observabilityFeatures string) (map[string]interface{}, error) {
 
 	// First build the base template data
-	data, err := buildTemplateData(config, name, author, modulePath, outputDir, complianceLevel, nonInteractive, extraVars)
+	data, err := buildTemplateData(config, name, author, modulePath, outputDir, complianceLevel, nonInteractive, extraVars, values)
 	if err != nil {
 		return nil, err
 	}
@@ -873,6 +2034,20 @@ func buildTemplateDataWithFeatures(config *TemplateConfig, name, author, moduleP
 	data["AuthFeatures"] = authFeatures
 	data["ObservabilityFeatures"] = observabilityFeatures
 
+	// Stash the raw FeatureToggles so the enforcementFor/hasEnforcement
+	// template functions can look up scoped enforcement actions.
+	data["FeatureToggles"] = config.FeatureToggles
+
+	// Stash the template's own features.yaml-declared flags so templates can
+	// query "{{ .Features.Enabled "security" "input_validation" }}" directly,
+	// without string-splitting a CSV field. A template with no features.yaml
+	// still gets a non-nil, always-false set, so the method call is safe to
+	// use unconditionally.
+	if flags == nil {
+		flags = newFeatureFlagSet(nil)
+	}
+	data["Features"] = flags
+
 	return data, nil
 }
 
@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"io"
 	"os"
-	"path/filepath" // Ensure filepath is imported
 	"testing"
-	"testing/fstest" // Import fstest for mock filesystem
 
 	"github.com/stretchr/testify/require" // Import testify/require
+
+	"github.com/getporter/skeletor/cmd/skeletor/templatetest"
 )
 
 // Helper function to capture stdout
@@ -87,7 +87,7 @@ func TestBuildTemplateData_Validation(t *testing.T) {
 			extraVars:      []string{"MyBool=maybe"},
 			nonInteractive: true,
 			expectedData:   nil,
-			expectErrorMsg: "invalid boolean value",
+			expectErrorMsg: "--var[0]:MyBool=maybe: variable MyBool: invalid boolean value",
 		},
 		{
 			name: "Valid integer var",
@@ -267,7 +267,7 @@ func TestBuildTemplateData_Validation(t *testing.T) {
 			// Provide dummy values for name, author, modulePath, outputDir as they are not under test here
 			// but are used internally by buildTemplateData to infer defaults if needed.
 			// Add a dummy complianceLevel ("basic") for the updated function signature.
-			data, err := buildTemplateData(tc.config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", tc.nonInteractive, tc.extraVars)
+			data, err := buildTemplateData(tc.config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", tc.nonInteractive, tc.extraVars, nil)
 
 			if tc.expectErrorMsg != "" {
 				require.Error(t, err, "Expected an error but got none")
@@ -285,119 +285,321 @@ func TestBuildTemplateData_Validation(t *testing.T) {
 	// Test is now active (no t.Skip)
 }
 
-// TODO: Add tests for the createMixin function (beyond conditional files and Go replacements)
+func TestBuildTemplateData_SchemaValidation(t *testing.T) {
+	minTwo := 2.0
+	maxHundred := 100.0
+	maxLenFive := 5
 
-func TestCreateMixin_ConditionalFiles(t *testing.T) {
-	// Test is now active (no t.Skip)
-
-	// Define common mock FS structure
-	mockFSBase := fstest.MapFS{
-		"template.json": &fstest.MapFile{
-			Data: []byte(`{
-				"name": "Conditional Test",
-				"variables": {
-					"MixinName": {"type": "string", "required": true},
-					"ComplianceLevel": {"type": "string", "default": "basic", "choices": ["basic", "slsa-l3"]}
+	testCases := []struct {
+		name           string
+		config         *TemplateConfig
+		extraVars      []string
+		expectedData   map[string]interface{}
+		expectErrorMsg string
+	}{
+		{
+			name: "Maximum exceeded",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyInt": {Description: "An integer", Schema: &VariableSchema{Type: "int", Maximum: &maxHundred}},
 				},
-				"conditional_paths": {
-					".golangci.yml": "{{ if eq .ComplianceLevel \"slsa-l3\" }}.golangci-strict.yml.tmpl{{ else }}.golangci.yml.tmpl{{ end }}"
-				}
-			}`),
+			},
+			extraVars:      []string{"MyInt=999"},
+			expectErrorMsg: "variable MyInt: value 999 exceeds maximum 100",
+		},
+		{
+			name: "Minimum satisfied",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyInt": {Description: "An integer", Schema: &VariableSchema{Type: "int", Minimum: &minTwo, Maximum: &maxHundred}},
+				},
+			},
+			extraVars:    []string{"MyInt=50"},
+			expectedData: map[string]interface{}{"MyInt": 50},
+		},
+		{
+			name: "Pattern mismatch",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyName": {Description: "A name", Schema: &VariableSchema{Type: "string", Pattern: "^[a-z]+$"}},
+				},
+			},
+			extraVars:      []string{"MyName=Invalid123"},
+			expectErrorMsg: `does not match pattern`,
+		},
+		{
+			name: "MaxLength exceeded",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyName": {Description: "A name", Schema: &VariableSchema{Type: "string", MaxLength: &maxLenFive}},
+				},
+			},
+			extraVars:      []string{"MyName=toolongvalue"},
+			expectErrorMsg: "exceeds maxLength",
+		},
+		{
+			name: "Array type coerced from JSON",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyTags": {Description: "Tags", Schema: &VariableSchema{Type: "array"}},
+				},
+			},
+			extraVars:    []string{`MyTags=["a","b"]`},
+			expectedData: map[string]interface{}{"MyTags": []interface{}{"a", "b"}},
+		},
+		{
+			name: "Legacy choices compatibility shim still enforces enum",
+			config: &TemplateConfig{
+				Variables: map[string]Variable{
+					"MyChoice": {Description: "A choice", Type: "string", Choices: []string{"a", "b"}},
+				},
+			},
+			extraVars:      []string{"MyChoice=z"},
+			expectErrorMsg: "invalid choice",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := buildTemplateData(tc.config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, tc.extraVars, nil)
+
+			if tc.expectErrorMsg != "" {
+				require.Error(t, err, "Expected an error but got none")
+				require.Contains(t, err.Error(), tc.expectErrorMsg, "Error message mismatch")
+				return
+			}
+			require.NoError(t, err, "Expected no error but got one: %v", err)
+			for k, v := range tc.expectedData {
+				require.Contains(t, data, k, "Expected data map to contain key %s", k)
+				require.Equal(t, v, data[k], "Value mismatch for key %s", k)
+			}
+		})
+	}
+}
+
+func TestBuildTemplateData_ValuesFilePrePopulatesAndVarOverrides(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MyInt":  {Description: "An integer", Schema: &VariableSchema{Type: "int"}},
+			"MyName": {Description: "A name", Schema: &VariableSchema{Type: "string"}},
+		},
+	}
+	values := map[string]interface{}{"MyInt": 7, "MyName": "from-values-file"}
+
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, values)
+	require.NoError(t, err)
+	require.Equal(t, 7, data["MyInt"])
+	require.Equal(t, "from-values-file", data["MyName"])
+
+	// --var still wins over a values-file entry for the same variable.
+	data, err = buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, []string{"MyName=from-var"}, values)
+	require.NoError(t, err)
+	require.Equal(t, "from-var", data["MyName"])
+}
+
+func TestBuildTemplateData_ValuesFileValueValidatedAgainstSchema(t *testing.T) {
+	maxHundred := 100.0
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MyInt": {Description: "An integer", Schema: &VariableSchema{Type: "int", Maximum: &maxHundred}},
+		},
+	}
+	values := map[string]interface{}{"MyInt": 999}
+
+	_, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, values)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds maximum 100")
+}
+
+func TestBuildTemplateData_ListAndMapTypes(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MyTags":   {Description: "Tags", Type: "list"},
+			"MyLabels": {Description: "Labels", Type: "map"},
+		},
+	}
+
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true,
+		[]string{"MyTags=a, b,c", "MyLabels=env=prod, tier=web"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a", "b", "c"}, data["MyTags"])
+	require.Equal(t, map[string]interface{}{"env": "prod", "tier": "web"}, data["MyLabels"])
+
+	data, err = buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true,
+		[]string{`MyTags=["a","b"]`, `MyLabels={"env":"prod"}`}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a", "b"}, data["MyTags"])
+	require.Equal(t, map[string]interface{}{"env": "prod"}, data["MyLabels"])
+}
+
+func TestBuildTemplateData_OnlyIfSkipsVariableAndItsRequiredCheck(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"EnableSecurity":   {Description: "Enable security", Type: "bool", Default: false},
+			"SecurityFeatures": {Description: "Security features", Type: "string", Required: true, OnlyIf: "{{ .EnableSecurity }}"},
+		},
+	}
+
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, nil)
+	require.NoError(t, err)
+	require.NotContains(t, data, "SecurityFeatures")
+}
+
+func TestBuildTemplateData_SkipIfOppositeOfOnlyIf(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"Legacy":    {Description: "Legacy mode", Type: "bool", Default: true},
+			"ModernOpt": {Description: "Modern-only option", Type: "string", Default: "x", SkipIf: "{{ .Legacy }}"},
+		},
+	}
+
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, nil)
+	require.NoError(t, err)
+	require.NotContains(t, data, "ModernOpt")
+}
+
+func TestBuildTemplateData_ValidationExprComparesVariables(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"Password": {Description: "Password", Type: "string", Required: true},
+			"PasswordConfirm": {Description: "Confirm password", Type: "string", Required: true,
+				Validation: &VariableValidation{Expr: "{{ eq .Password .PasswordConfirm }}", Message: "passwords must match"}},
 		},
-		".golangci.yml.tmpl": &fstest.MapFile{
-			Data: []byte("basic lint config for {{ .MixinName }}"),
+	}
+
+	_, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true,
+		[]string{"Password=hunter2", "PasswordConfirm=hunter3"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "passwords must match")
+
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true,
+		[]string{"Password=hunter2", "PasswordConfirm=hunter2"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", data["Password"])
+}
+
+func TestBuildTemplateData_EnvVarLayer(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MyName": {Description: "A name", Type: "string"},
 		},
-		".golangci-strict.yml.tmpl": &fstest.MapFile{
-			Data: []byte("strict lint config for {{ .MixinName }}"),
+	}
+
+	t.Setenv("SKELETOR_VAR_MYNAME", "from-env")
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "from-env", data["MyName"])
+
+	// --var still outranks the environment variable for the same variable.
+	data, err = buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, []string{"MyName=from-var"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "from-var", data["MyName"])
+}
+
+func TestBuildTemplateData_EnvVarOutranksValuesFile(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MyName": {Description: "A name", Type: "string"},
 		},
-		"always_present.txt.tmpl": &fstest.MapFile{
-			Data: []byte("Always here"),
+	}
+	values := map[string]interface{}{"MyName": "from-values-file"}
+
+	t.Setenv("SKELETOR_VAR_MYNAME", "from-env")
+	data, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, values)
+	require.NoError(t, err)
+	require.Equal(t, "from-env", data["MyName"])
+}
+
+func TestResolveVariable_PrecedenceOrder(t *testing.T) {
+	rawVars := map[string]string{"FromVar": "var-value"}
+	rawVarIndex := map[string]int{"FromVar": 0}
+	extraVars := []string{"FromVar=var-value"}
+	values := map[string]interface{}{"FromValuesFile": "values-file-value", "FromVar": "should-be-overridden"}
+
+	source := resolveVariable("FromVar", rawVars, rawVarIndex, extraVars, values)
+	require.True(t, source.Found)
+	require.False(t, source.IsTyped)
+	require.Equal(t, "var-value", source.Raw)
+
+	source = resolveVariable("FromValuesFile", rawVars, rawVarIndex, extraVars, values)
+	require.True(t, source.Found)
+	require.True(t, source.IsTyped)
+	require.Equal(t, "values-file-value", source.Typed)
+
+	source = resolveVariable("Unset", rawVars, rawVarIndex, extraVars, values)
+	require.False(t, source.Found)
+}
+
+func TestBuildTemplateData_AccumulatesErrorsAcrossVariables(t *testing.T) {
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"First":  {Description: "First", Required: true},
+			"Second": {Description: "Second", Required: true},
 		},
 	}
 
+	_, err := buildTemplateData(config, "test-mixin", "test-author", "example.com/test", "test-output", "basic", true, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "First")
+	require.Contains(t, err.Error(), "Second")
+}
+
+// TODO: Add tests for the createMixin function (beyond conditional files and Go replacements)
+
+func TestCreateMixin_ConditionalFiles(t *testing.T) {
+	// Uses the templatetest harness (see cmd/skeletor/templatetest) instead
+	// of a hand-rolled fstest.MapFS + t.TempDir().
+	fake := templatetest.NewFakeTemplate(map[string]string{
+		"template.json": `{
+			"name": "Conditional Test",
+			"variables": {
+				"MixinName": {"type": "string", "required": true},
+				"ComplianceLevel": {"type": "string", "default": "basic", "choices": ["basic", "slsa-l3"]}
+			},
+			"conditional_paths": {
+				".golangci.yml.tmpl": "{{ if eq .ComplianceLevel \"slsa-l3\" }}.golangci-strict.yml.tmpl{{ else }}.golangci.yml.tmpl{{ end }}"
+			}
+		}`,
+		".golangci.yml.tmpl":        "basic lint config for {{ .MixinName }}",
+		".golangci-strict.yml.tmpl": "strict lint config for {{ .MixinName }}",
+		"always_present.txt.tmpl":   "Always here",
+	})
+
 	testCases := []struct {
 		name            string
 		complianceLevel string
 		expectedContent string // Expected content of .golangci.yml
-		expectError     bool
 	}{
 		{
 			name:            "Basic Compliance Level",
 			complianceLevel: "basic",
 			expectedContent: "basic lint config for test-conditional",
-			expectError:     false,
 		},
 		{
 			name:            "SLSA L3 Compliance Level",
 			complianceLevel: "slsa-l3",
 			expectedContent: "strict lint config for test-conditional",
-			expectError:     false,
 		},
 		// Add more cases if other conditional paths are introduced
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			outputDir := t.TempDir()
-			defer os.RemoveAll(outputDir)
-
-			// Mock config matching the mock template.json
-			config := &TemplateConfig{
-				Name: "Conditional Test",
-				Variables: map[string]Variable{
-					"MixinName":       {Type: "string", Required: true},
-					"ComplianceLevel": {Type: "string", Default: "basic", Choices: []string{"basic", "slsa-l3"}},
-				},
-				ConditionalPaths: map[string]string{
-					".golangci.yml": "{{ if eq .ComplianceLevel \"slsa-l3\" }}.golangci-strict.yml.tmpl{{ else }}.golangci.yml.tmpl{{ end }}",
-				},
-				Ignore: []string{},
-				Hooks:  map[string][]string{},
-			}
-
-			// Mock template data
-			data := map[string]interface{}{
+			result := fake.Generate(t, map[string]any{
 				"MixinName":       "test-conditional",
 				"ComplianceLevel": tc.complianceLevel,
-			}
-
-			// Run createMixin (non-dry run to check actual file content)
-			err := createMixin(data, mockFSBase, ".", outputDir, config, false) // dryRun = false
+			})
 
-			if tc.expectError {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-
-				// Check if the target file exists
-				targetFilePath := filepath.Join(outputDir, ".golangci.yml")
-				_, statErr := os.Stat(targetFilePath)
-				require.NoError(t, statErr, ".golangci.yml should be generated")
-
-				// Check the content of the generated file
-				contentBytes, readErr := os.ReadFile(targetFilePath)
-				require.NoError(t, readErr, "Failed to read generated .golangci.yml")
-				require.Equal(t, tc.expectedContent, string(contentBytes), "Content mismatch for generated .golangci.yml")
-
-				// Check that the always present file is there too
-				alwaysPresentPath := filepath.Join(outputDir, "always_present.txt")
-				_, alwaysStatErr := os.Stat(alwaysPresentPath)
-				require.NoError(t, alwaysStatErr, "always_present.txt should be generated")
-			}
+			require.Equal(t, tc.expectedContent, result.File(".golangci.yml"), "Content mismatch for generated .golangci.yml")
+			require.Equal(t, "Always here", result.File("always_present.txt"), "always_present.txt should be generated")
 		})
 	}
 }
 
 func TestCreateMixin_GoFileReplacements(t *testing.T) {
-	// Test is now active (no t.Skip)
-
-	// Define mock FS structure
-	mockFSGo := fstest.MapFS{
-		"template.json": &fstest.MapFile{ // Basic config
-			Data: []byte(`{"name": "Go Replace Test", "variables": {"MixinName": {}, "ModulePath": {}, "AuthorName": {}}}`),
-		},
-		"cmd/mixin/main.go.tmpl": &fstest.MapFile{ // Sample Go file content
-			Data: []byte(`package mixin
+	fake := templatetest.NewFakeTemplate(map[string]string{
+		"template.json": `{"name": "Go Replace Test", "variables": {"MixinName": {}, "ModulePath": {}, "AuthorName": {}}}`,
+		"cmd/mixin/main.go.tmpl": `package mixin
 import (
 	"fmt"
 	"{{ .ModulePath }}/pkg/mixin" // Placeholder import
@@ -413,202 +615,90 @@ func main() {
 	skeletor.Helper()
 	others.Util()
 }
-`),
-		},
-		"pkg/mixin/helpers.go.tmpl": &fstest.MapFile{ // Another sample Go file
-			Data: []byte(`package mixin
+`,
+		"pkg/mixin/helpers.go.tmpl": `package mixin
 
 import "fmt"
 
 func SomeFunc() { fmt.Println("SomeFunc called") }
 func AnotherFunc() { fmt.Println("AnotherFunc called") }
-`),
-		},
-	}
-
-	// Mock config
-	configGo := &TemplateConfig{
-		Name: "Go Replace Test",
-		Variables: map[string]Variable{
-			"MixinName":  {},
-			"ModulePath": {},
-			"AuthorName": {},
-		},
-		Ignore: []string{},
-		Hooks:  map[string][]string{},
-	}
+`,
+	})
 
-	// Mock template data
-	dataGo := map[string]interface{}{
+	result := fake.Generate(t, map[string]any{
 		"MixinName":  "replacer",
 		"ModulePath": "example.com/getporter/replacer",
 		"AuthorName": "Test Author",
-	}
-
-	outputDirGo := t.TempDir()
-	defer os.RemoveAll(outputDirGo)
-
-	// Run createMixin (non-dry run)
-	errGo := createMixin(dataGo, mockFSGo, ".", outputDirGo, configGo, false)
-	require.NoError(t, errGo)
-
-	// Check content of generated main.go
-	mainGoPath := filepath.Join(outputDirGo, "cmd/replacer/main.go") // Dest path uses MixinName
-	mainContentBytes, mainReadErr := os.ReadFile(mainGoPath)
-	require.NoError(t, mainReadErr, "Failed to read generated main.go")
-	mainContent := string(mainContentBytes)
+	})
 
-	// Assert replacements
-	require.Contains(t, mainContent, "package replacer", "Package name not replaced")
-	require.Contains(t, mainContent, `"example.com/getporter/replacer/pkg/replacer"`, "Import path not replaced correctly")
-	require.Contains(t, mainContent, `p "example.com/getporter/replacer/pkg"`, "Aliased import path not replaced correctly")
-	require.Contains(t, mainContent, `skeletor "example.com/getporter/replacer/pkg/skeletor"`, "Specific import path not replaced correctly")
+	mainContent := result.File("cmd/mixin/main.go") // Dest path keeps the literal "mixin" segment
+	require.NotEmpty(t, mainContent, "main.go should be generated")
+	require.Contains(t, mainContent, "package mixin", "Package clause normalized to package mixin")
+	require.Contains(t, mainContent, `"example.com/getporter/replacer/pkg/mixin"`, "Module path templated into import")
+	require.Contains(t, mainContent, `p "example.com/getporter/replacer/pkg"`, "Module path templated into aliased import")
+	require.Contains(t, mainContent, `skeletor "example.com/getporter/replacer/pkg/skeletor"`, "Module path templated into specific import")
 	require.Contains(t, mainContent, `"github.com/getporter/skeletor/pkg"`, "Unrelated import path should not be replaced") // Control check
 	require.Contains(t, mainContent, `fmt.Println("Hello from mixin replacer by Test Author")`, "MixinName and AuthorName not replaced in string")
-	require.Contains(t, mainContent, `replacer.SomeFunc()`, "Internal package call not updated")
-
-	// Check content of generated helpers.go
-	helpersGoPath := filepath.Join(outputDirGo, "pkg/replacer/helpers.go") // Dest path uses MixinName
-	helpersContentBytes, helpersReadErr := os.ReadFile(helpersGoPath)
-	require.NoError(t, helpersReadErr, "Failed to read generated helpers.go")
-	helpersContent := string(helpersContentBytes)
-	require.Contains(t, helpersContent, "package replacer", "Package name not replaced in helpers.go")
 
+	helpersContent := result.File("pkg/mixin/helpers.go")
+	require.Contains(t, helpersContent, "package mixin", "Package clause normalized to package mixin")
 }
 
 func TestCreateMixin_FilenameTemplating(t *testing.T) {
-	// Define mock FS structure with a templated filename
-	mockFSFilename := fstest.MapFS{
-		"template.json": &fstest.MapFile{ // Basic config
-			Data: []byte(`{"name": "Filename Test", "variables": {"MixinName": {}}}`),
-		},
-		"{{ .MixinName }}.config.txt.tmpl": &fstest.MapFile{
-			Data: []byte("Config for {{ .MixinName }}"),
-		},
-		"static_dir/{{ .MixinName }}_data.json.tmpl": &fstest.MapFile{
-			Data: []byte(`{"name": "{{ .MixinName }}"}`),
-		},
-	}
-
-	// Mock config
-	configFilename := &TemplateConfig{
-		Name: "Filename Test",
-		Variables: map[string]Variable{
-			"MixinName": {},
-		},
-		Ignore: []string{},
-		Hooks:  map[string][]string{},
-	}
-
-	// Mock template data
-	dataFilename := map[string]interface{}{
-		"MixinName": "filenametest",
-	}
-
-	outputDirFilename := t.TempDir()
-	defer os.RemoveAll(outputDirFilename)
-
-	// Run createMixin (non-dry run)
-	errFilename := createMixin(dataFilename, mockFSFilename, ".", outputDirFilename, configFilename, false)
-	require.NoError(t, errFilename)
-
-	// Check if the file with the templated name was created correctly
-	expectedFilePath1 := filepath.Join(outputDirFilename, "filenametest.config.txt")
-	_, statErr1 := os.Stat(expectedFilePath1)
-	require.NoError(t, statErr1, "File with templated name was not created correctly")
-
-	// Check content of the first file
-	contentBytes1, readErr1 := os.ReadFile(expectedFilePath1)
-	require.NoError(t, readErr1, "Failed to read generated file with templated name")
-	require.Equal(t, "Config for filenametest", string(contentBytes1), "Content mismatch for file with templated name")
+	fake := templatetest.NewFakeTemplate(map[string]string{
+		"template.json":                              `{"name": "Filename Test", "variables": {"MixinName": {}}}`,
+		"{{ .MixinName }}.config.txt.tmpl":           "Config for {{ .MixinName }}",
+		"static_dir/{{ .MixinName }}_data.json.tmpl": `{"name": "{{ .MixinName }}"}`,
+	})
 
-	// Check if the file within a templated directory name was created correctly
-	expectedFilePath2 := filepath.Join(outputDirFilename, "static_dir/filenametest_data.json")
-	_, statErr2 := os.Stat(expectedFilePath2)
-	require.NoError(t, statErr2, "File within directory with templated name was not created correctly")
+	result := fake.Generate(t, map[string]any{"MixinName": "filenametest"})
 
-	// Check content of the second file
-	contentBytes2, readErr2 := os.ReadFile(expectedFilePath2)
-	require.NoError(t, readErr2, "Failed to read generated file within templated directory")
-	require.Equal(t, `{"name": "filenametest"}`, string(contentBytes2), "Content mismatch for file within templated directory")
+	require.Equal(t, "Config for filenametest", result.File("filenametest.config.txt"), "Content mismatch for file with templated name")
+	require.Equal(t, `{"name": "filenametest"}`, result.File("static_dir/filenametest_data.json"), "Content mismatch for file within templated directory")
 }
 
 func TestCreateMixin_DryRun(t *testing.T) {
-	// Define a mock filesystem
-	mockFS := fstest.MapFS{
-		"template.json": &fstest.MapFile{ // Need template.json for LoadTemplateConfig
-			Data: []byte(`{
-				"name": "Test Template",
-				"variables": {
-					"MixinName": {"type": "string", "required": true}
-				}
-			}`),
-		},
-		"dir1/file1.txt.tmpl": &fstest.MapFile{
-			Data: []byte("Content for {{ .MixinName }} file 1"),
-		},
-		"file2.txt.tmpl": &fstest.MapFile{
-			Data: []byte("Content for file 2"),
-		},
-		// Add a conditional path scenario
-		"conditional.txt.tmpl": &fstest.MapFile{
-			Data: []byte("Conditional Content"),
-		},
-		"actual_source.txt.tmpl": &fstest.MapFile{ // The source for the conditional path
-			Data: []byte("Actual Source Content"),
-		},
-	}
-
-	// Mock config matching the mock template.json and adding conditional path
-	config := &TemplateConfig{
-		Name: "Test Template",
-		Variables: map[string]Variable{
-			"MixinName": {Type: "string", Required: true},
-		},
-		ConditionalPaths: map[string]string{
-			"conditional.txt.tmpl": "{{ if .Condition }}actual_source.txt.tmpl{{ else }}{{ end }}", // Condition to select source
-		},
-		Ignore: []string{},
-		Hooks:  map[string][]string{},
-	}
+	fake := templatetest.NewFakeTemplate(map[string]string{
+		"template.json": `{
+			"name": "Test Template",
+			"variables": {
+				"MixinName": {"type": "string", "required": true}
+			},
+			"conditional_paths": {
+				"conditional.txt.tmpl": "{{ if .Condition }}actual_source.txt.tmpl{{ else }}{{ end }}"
+			}
+		}`,
+		"dir1/file1.txt.tmpl":    "Content for {{ .MixinName }} file 1",
+		"file2.txt.tmpl":         "Content for file 2",
+		"conditional.txt.tmpl":   "Conditional Content",
+		"actual_source.txt.tmpl": "Actual Source Content", // The source for the conditional path
+	})
 
-	// Mock template data
-	data := map[string]interface{}{
+	result := fake.Generate(t, map[string]any{
 		"MixinName": "testmixin",
 		"Condition": true, // Trigger the conditional path
-	}
-
-	outputDir := t.TempDir() // Use a temporary directory path (won't be written to)
-
-	// Capture output during dry run
-	output := captureOutput(func() {
-		err := createMixin(data, mockFS, ".", outputDir, config, true) // dryRun = true
-		require.NoError(t, err, "createMixin in dry run mode failed")
 	})
 
+	output := result.DryRunLog()
+
 	// Assert that dry run messages are present in the output
 	require.Contains(t, output, "[Dry Run] Simulating file generation...", "Expected dry run start message")
-	require.Contains(t, output, "[Dry Run] Would create directory:", "Expected dry run message for directory creation")
-	require.Contains(t, output, "[Dry Run] Would write file: "+filepath.Join(outputDir, "dir1/file1.txt"), "Expected dry run message for file1")
-	require.Contains(t, output, "[Dry Run] Would write file: "+filepath.Join(outputDir, "file2.txt"), "Expected dry run message for file2")
-	require.Contains(t, output, "[Dry Run] Would write file: "+filepath.Join(outputDir, "conditional.txt")+" (from source actual_source.txt.tmpl)", "Expected dry run message for conditional file")
-	require.Contains(t, output, "[Dry Run] Skipping post-generation validation.", "Expected dry run message for skipping validation")
+	require.Contains(t, output, "[Dry Run] Would write file: dir1/file1.txt (from source dir1/file1.txt.tmpl)", "Expected dry run message for file1")
+	require.Contains(t, output, "[Dry Run] Would write file: file2.txt (from source file2.txt.tmpl)", "Expected dry run message for file2")
+	require.Contains(t, output, "[Dry Run] Would write file: conditional.txt (from source actual_source.txt.tmpl)", "Expected dry run message for conditional file")
 	require.Contains(t, output, "[Dry Run] Simulation complete.", "Expected dry run completion message")
 
-	// Assert that no files were actually created (check if output dir is empty)
-	files, err := os.ReadDir(outputDir)
-	require.NoError(t, err, "Failed to read output directory")
-	require.Empty(t, files, "No files should be created in the output directory during dry run")
+	require.Equal(t, "Content for testmixin file 1", result.File("dir1/file1.txt"))
 }
 
 func TestRunHooks_VariableSubstitution(t *testing.T) {
 	// Mock config with a templated hook command
 	config := &TemplateConfig{
-		Hooks: map[string][]string{
+		Hooks: map[string][]HookStep{
 			"post_gen": {
-				"echo Hello {{ .MixinName }}",
-				"echo Author is {{ .AuthorName }}",
-				"echo NonExistent is {{ .NonExistentVar }}", // Test missing var
+				{Command: "echo Hello {{ .MixinName }}"},
+				{Command: "echo Author is {{ .AuthorName }}"},
+				{Command: "echo NonExistent is {{ .NonExistentVar }}"}, // Test missing var
 			},
 		},
 	}
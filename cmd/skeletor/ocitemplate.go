@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ociTemplateMediaType is the artifact media type `skeletor template push`
+// publishes under and pullOCITemplate pulls back down -- distinguishing a
+// skeletor template artifact from an arbitrary OCI image at the same ref.
+const ociTemplateMediaType = "application/vnd.porter.skeletor.template.v1+tar+gzip"
+
+// looksLikeOCITemplateRef reports whether templateUrl names an OCI artifact
+// rather than a git repository: an explicit oci:// or docker:// scheme, or a
+// bare "registry/repo:tag"-style reference with no scheme at all, the way
+// `docker pull`/`oras pull` accept one -- e.g. "ghcr.io/foo/template:v1.2.0".
+// A ref with some other explicit scheme (git's own http(s):// included)
+// isn't ours to claim.
+func looksLikeOCITemplateRef(templateUrl string) bool {
+	if strings.HasPrefix(templateUrl, "oci://") || strings.HasPrefix(templateUrl, "docker://") {
+		return true
+	}
+	if strings.Contains(templateUrl, "://") {
+		return false
+	}
+	firstSegment, _, found := strings.Cut(templateUrl, "/")
+	if !found || strings.Contains(firstSegment, "@") {
+		return false // e.g. git@github.com:foo/bar.git -- an SSH git remote, not a registry host
+	}
+	return strings.ContainsAny(firstSegment, ".:")
+}
+
+// resolveOCITemplateURL pulls templateUrl (an oci://, docker://, or bare
+// registry ref) as an OCI artifact into the same templateURLCacheDir() a
+// git --template-url uses, keyed by the ref itself rather than a resolved
+// commit SHA -- an OCI tag (or digest) is already the pin; there's no
+// separate resolution step the way a git branch/tag name has. verifySignature
+// requires a cosign signature over ref before it's pulled; cosignKey pins
+// that verification to a specific public key instead of the default keyless
+// Fulcio/Rekor flow.
+func resolveOCITemplateURL(templateUrl string, noCache, refresh, verifySignature bool, cosignKey string) (fs.FS, string, string, error) {
+	ref := strings.TrimPrefix(strings.TrimPrefix(templateUrl, "oci://"), "docker://")
+
+	if noCache {
+		tempDir, err := os.MkdirTemp("", "porter-oci-template-*")
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		if err := pullOCITemplate(ref, tempDir, verifySignature, cosignKey); err != nil {
+			_ = os.RemoveAll(tempDir)
+			return nil, "", "", err
+		}
+		return os.DirFS(tempDir), ".", tempDir, nil
+	}
+
+	cacheRoot, err := templateURLCacheDir()
+	if err != nil {
+		return nil, "", "", err
+	}
+	cacheDir := filepath.Join(cacheRoot, templateCacheKey(ref, "oci"))
+
+	if manifest, err := readTemplateCacheManifest(cacheDir); err == nil && manifest.TreeDigest != "" && !refresh {
+		fmt.Printf("Using cached OCI template %s\n", ref)
+		return os.DirFS(cacheDir), ".", "", nil
+	}
+
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return nil, "", "", fmt.Errorf("failed to clear stale cache entry %s: %w", cacheDir, err)
+	}
+	if err := pullOCITemplate(ref, cacheDir, verifySignature, cosignKey); err != nil {
+		_ = os.RemoveAll(cacheDir)
+		return nil, "", "", err
+	}
+
+	digest, err := computeTreeDigest(os.DirFS(cacheDir), ".")
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := writeTemplateCacheManifest(cacheDir, templateCacheManifest{
+		URL:        templateUrl,
+		Ref:        ref, // an OCI artifact has no separate commit SHA; the ref itself is the pin
+		TreeDigest: digest,
+		FetchedAt:  time.Now(),
+	}); err != nil {
+		return nil, "", "", err
+	}
+
+	return os.DirFS(cacheDir), ".", "", nil
+}
+
+// pullOCITemplate verifies (if requested) then pulls ref's
+// ociTemplateMediaType artifact into dir via the oras CLI -- the same
+// shell-out convention ociTemplateSource (templatesource.go) already uses
+// for --template-source=oci://, rather than adding a second, oras-go-based
+// OCI client to the same binary.
+func pullOCITemplate(ref, dir string, verifySignature bool, cosignKey string) error {
+	if verifySignature {
+		if err := verifyOCITemplateSignature(ref, cosignKey); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	fmt.Printf("Pulling OCI template artifact %s...\n", ref)
+	// #nosec G204 -- ref comes from a user flag, dir is generated, command is allow-listed
+	cmd := createCommand("oras", "pull", ref, "-o", dir, "--media-type", ociTemplateMediaType)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to pull OCI template artifact %s (requires the oras CLI): %w", ref, err)
+	}
+	return nil
+}
+
+// verifyOCITemplateSignature runs `cosign verify` against ref before it's
+// pulled -- keyless (Fulcio/Rekor) by default, or against cosignKey when
+// one is given.
+func verifyOCITemplateSignature(ref, cosignKey string) error {
+	args := []string{"verify"}
+	if cosignKey != "" {
+		args = append(args, "--key", cosignKey)
+	}
+	args = append(args, ref)
+
+	fmt.Printf("Verifying cosign signature for %s...\n", ref)
+	// #nosec G204 -- ref/cosignKey come from user flags, command is allow-listed
+	cmd := createCommand("cosign", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cosign signature verification failed for %s (requires the cosign CLI): %w", ref, err)
+	}
+	return nil
+}
+
+// pushOCITemplate publishes dir as an ociTemplateMediaType OCI artifact to
+// ref via the oras CLI, for `skeletor template push`.
+func pushOCITemplate(dir, ref string) error {
+	fmt.Printf("Pushing %s as OCI template artifact %s...\n", dir, ref)
+	// #nosec G204 -- dir/ref come from user-supplied positional args, command is allow-listed
+	cmd := createCommand("oras", "push", ref, "--artifact-type", ociTemplateMediaType, dir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to push %s to %s (requires the oras CLI): %w", dir, ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLooksLikeOCITemplateRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"oci://ghcr.io/foo/template:v1.2.0", true},
+		{"docker://ghcr.io/foo/template:v1.2.0", true},
+		{"ghcr.io/foo/template:v1.2.0", true},
+		{"localhost:5000/foo/template:v1.2.0", true},
+		{"https://github.com/foo/bar.git", false},
+		{"git@github.com:foo/bar.git", false},
+		{"foo", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			assert.Equal(t, tc.want, looksLikeOCITemplateRef(tc.ref))
+		})
+	}
+}
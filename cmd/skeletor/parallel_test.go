@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateMixin_Parallelism renders a template with more files than the
+// configured worker pool to exercise the bounded worker-pool path (and not
+// just the single-goroutine/empty-queue edge case).
+func TestCreateMixin_Parallelism(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{Data: []byte(`{
+			"name": "Parallel Test",
+			"variables": {"MixinName": {"type": "string", "required": true}}
+		}`)},
+	}
+	const fileCount = 20
+	for i := 0; i < fileCount; i++ {
+		mockFS[fmt.Sprintf("file%02d.txt.tmpl", i)] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf("file %d for {{ .MixinName }}", i)),
+		}
+	}
+	config := &TemplateConfig{
+		Name:      "Parallel Test",
+		Variables: map[string]Variable{"MixinName": {Type: "string", Required: true}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 2, "", nil))
+
+	for i := 0; i < fileCount; i++ {
+		content, err := os.ReadFile(filepath.Join(outputDir, fmt.Sprintf("file%02d.txt", i)))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("file %d for my-mixin", i), string(content))
+	}
+}
+
+// TestCreateMixin_ParallelismDefaultsToNumCPU exercises the parallelism <= 0
+// path (used by the --parallelism flag's zero value) alongside an explicit
+// positive value, asserting both still render everything correctly.
+func TestCreateMixin_ParallelismDefaultsToNumCPU(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{Data: []byte(`{
+			"name": "Parallel Default Test",
+			"variables": {"MixinName": {"type": "string", "required": true}}
+		}`)},
+		"a.txt.tmpl": &fstest.MapFile{Data: []byte("a for {{ .MixinName }}")},
+		"b.txt.tmpl": &fstest.MapFile{Data: []byte("b for {{ .MixinName }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Parallel Default Test",
+		Variables: map[string]Variable{"MixinName": {Type: "string", Required: true}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a for my-mixin", string(content))
+}
+
+// TestCreateMixin_ParallelismPropagatesFileError asserts that a failure
+// rendering one file (of several, across worker-pool goroutines) still
+// surfaces as an error from createMixin rather than being swallowed.
+func TestCreateMixin_ParallelismPropagatesFileError(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template.json": &fstest.MapFile{Data: []byte(`{
+			"name": "Parallel Error Test",
+			"variables": {"MixinName": {"type": "string", "required": true}}
+		}`)},
+		"good.txt.tmpl": &fstest.MapFile{Data: []byte("good for {{ .MixinName }}")},
+		"bad.txt.tmpl":  &fstest.MapFile{Data: []byte("{{ .MixinName | noSuchFunc }}")},
+	}
+	config := &TemplateConfig{
+		Name:      "Parallel Error Test",
+		Variables: map[string]Variable{"MixinName": {Type: "string", Required: true}},
+		Ignore:    []string{},
+		Hooks:     map[string][]HookStep{},
+	}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	outputDir := t.TempDir()
+	err := createMixin(data, mockFS, ".", outputDir, config, "", 2, "", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathPolicy_Basic(t *testing.T) {
+	rules, err := ParsePathPolicy(`
+path "internal/security/*" {
+  capabilities = ["render"]
+  required_features = ["security:input_validation"]
+}
+
+path "docs/compliance/soc2/**" {
+  capabilities       = ["render", "overwrite"]
+  denied_features    = ["compliance:gdpr_only"]
+}
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, "internal/security/*", rules[0].Pattern)
+	assert.Equal(t, []PathCapability{CapabilityRender}, rules[0].Capabilities)
+	assert.Equal(t, []string{"security:input_validation"}, rules[0].RequiredFeatures)
+
+	assert.Equal(t, "docs/compliance/soc2/**", rules[1].Pattern)
+	assert.True(t, rules[1].HasCapability(CapabilityOverwrite))
+	assert.Equal(t, []string{"compliance:gdpr_only"}, rules[1].DeniedFeatures)
+}
+
+func TestParsePathPolicy_DefaultsToRenderCapability(t *testing.T) {
+	rules, err := ParsePathPolicy(`path "README.md" {}`)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, []PathCapability{CapabilityRender}, rules[0].Capabilities)
+}
+
+func TestParsePathPolicy_RenameRequiresDestination(t *testing.T) {
+	_, err := ParsePathPolicy(`path "legacy/old.txt" { capabilities = ["rename"] }`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a destination attribute")
+}
+
+func TestParsePathPolicy_RenameWithDestination(t *testing.T) {
+	rules, err := ParsePathPolicy(`
+path "legacy/old.txt" {
+  capabilities = ["rename"]
+  destination  = "legacy/{{ .MixinName }}.txt"
+}
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "legacy/{{ .MixinName }}.txt", rules[0].Destination)
+}
+
+func TestParsePathPolicy_Comments(t *testing.T) {
+	rules, err := ParsePathPolicy(`
+# skip generated fixtures entirely
+path "testdata/**" {
+  capabilities = ["skip"] # never ship fixtures
+}
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.True(t, rules[0].HasCapability(CapabilitySkip))
+}
+
+func TestParsePathPolicy_Errors(t *testing.T) {
+	_, err := ParsePathPolicy(`path "broken" { capabilities = [ }`)
+	require.Error(t, err)
+
+	_, err = ParsePathPolicy(`path "broken" { bogus_attr = ["x"] }`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown attribute")
+
+	_, err = ParsePathPolicy(`path "unterminated" {`)
+	require.Error(t, err)
+}
+
+func TestPathPolicy_MatchPrefersMoreSpecificPattern(t *testing.T) {
+	rules, err := ParsePathPolicy(`
+path "internal/**" {
+  capabilities = ["skip"]
+}
+
+path "internal/security/*" {
+  capabilities = ["render"]
+}
+`)
+	require.NoError(t, err)
+	policy := CompilePathPolicy(rules)
+
+	rule, err := policy.Match("internal/security/validator.go", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+	assert.Equal(t, "internal/security/*", rule.Pattern)
+
+	rule, err = policy.Match("internal/other/thing.go", nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+	assert.Equal(t, "internal/**", rule.Pattern)
+}
+
+func TestPathPolicy_MatchGatesOnFeatures(t *testing.T) {
+	rules, err := ParsePathPolicy(`
+path "docs/compliance/**" {
+  capabilities = ["overwrite"]
+  required_features = ["compliance:soc2"]
+  denied_features = ["compliance:gdpr"]
+}
+`)
+	require.NoError(t, err)
+	policy := CompilePathPolicy(rules)
+
+	rule, err := policy.Match("docs/compliance/report.md", &FeatureToggles{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rule, "required_features not satisfied by empty toggles")
+
+	toggles := &FeatureToggles{Compliance: &ComplianceFeatures{Enabled: true, SOC2: true}}
+	rule, err = policy.Match("docs/compliance/report.md", toggles, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+
+	toggles.Compliance.GDPR = true
+	rule, err = policy.Match("docs/compliance/report.md", toggles, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rule, "denied_features satisfied should exclude the rule")
+}
+
+func TestPathPolicy_MatchNoRuleReturnsNil(t *testing.T) {
+	policy := CompilePathPolicy(nil)
+	rule, err := policy.Match("anything.txt", nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestPathPolicy_NilPolicyIsNoOp(t *testing.T) {
+	var policy *PathPolicy
+	rule, err := policy.Match("anything.txt", nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestResolveRenameDestination(t *testing.T) {
+	rule := &PathRule{Pattern: "legacy/old.txt", Destination: "legacy/{{ .MixinName }}.txt"}
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	resolved, err := ResolveRenameDestination(rule, data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy/my-mixin.txt", resolved)
+}
+
+func TestMigrateConditionalPaths_ConvertsWhenEntries(t *testing.T) {
+	legacy := map[string]ConditionalPath{
+		"docs/enterprise.md":    {When: `feature("compliance.soc2")`},
+		"legacy/source.go.tmpl": {Template: "legacy/source_oss.go.tmpl"},
+	}
+
+	rules := MigrateConditionalPaths(legacy)
+	require.Len(t, rules, 1, "only the structured when form is migrated")
+	assert.Equal(t, "docs/enterprise.md", rules[0].Pattern)
+	assert.True(t, rules[0].HasCapability(CapabilitySkip))
+	assert.Equal(t, `not(feature("compliance.soc2"))`, rules[0].When)
+
+	policy := CompilePathPolicy(rules)
+
+	rule, err := policy.Match("docs/enterprise.md", &FeatureToggles{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, rule, "condition false means the migrated rule fires and skips")
+
+	toggles := &FeatureToggles{Compliance: &ComplianceFeatures{Enabled: true, SOC2: true}}
+	rule, err = policy.Match("docs/enterprise.md", toggles, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rule, "condition true means the destination renders normally")
+}
+
+func TestCreateMixin_PathPolicySkipsMatchedDestination(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	config.PathPolicy = `
+path "fresh.txt.tmpl" {
+  capabilities = ["skip"]
+}
+`
+	outputDir := t.TempDir()
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	_, err := os.Stat(filepath.Join(outputDir, "fresh.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCreateMixin_PathPolicyRenamesMatchedDestination(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	config.PathPolicy = `
+path "fresh.txt.tmpl" {
+  capabilities = ["rename"]
+  destination  = "renamed-{{ .MixinName }}.txt"
+}
+`
+	outputDir := t.TempDir()
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "renamed-my-mixin.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "fresh content for my-mixin", string(content))
+
+	_, err = os.Stat(filepath.Join(outputDir, "fresh.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCreateMixin_PathPolicyOverwriteBeatsOnConflictFail(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	config.PathPolicy = `
+path "existing.txt.tmpl" {
+  capabilities = ["overwrite"]
+}
+`
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("old content"), 0600))
+
+	require.NoError(t, createMixin(data, mockFS, ".", outputDir, config, "", 0, "fail", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "existing.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new content for my-mixin", string(content))
+}
+
+func TestCompileConfigPathPolicy_NilAndEmpty(t *testing.T) {
+	policy, err := compileConfigPathPolicy(nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = compileConfigPathPolicy(&TemplateConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestCompileConfigPathPolicy_ParseError(t *testing.T) {
+	_, err := compileConfigPathPolicy(&TemplateConfig{PathPolicy: `path "broken" {`})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse path_policy")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/multierr"
+
+	"github.com/getporter/skeletor/pkg/plugin"
+)
+
+// runPluginHooks invokes every installed plugin's Run for stage ("pre_gen",
+// "post_gen" or "validate"), skipping plugins that didn't declare it,
+// alongside the template's own config-declared hooks (see RunHooksWithOptions).
+// A plugin failure is reported but doesn't stop the others from running,
+// mirroring RunHooksWithOptions' default (non-fail-fast) behavior; the
+// caller decides whether the combined error should abort generation.
+func runPluginHooks(stage, outputDir string, data map[string]interface{}) error {
+	plugins, err := plugin.LoadAll()
+	if err != nil {
+		return fmt.Errorf("failed to discover plugins: %w", err)
+	}
+
+	var combined error
+	for _, p := range plugins {
+		if !p.HasHook(stage) {
+			continue
+		}
+		fmt.Printf("Running plugin %q's %s hook...\n", p.Metadata.Name, stage)
+		out, runErr := p.Run(context.Background(), stage, outputDir, data)
+		if runErr != nil {
+			combined = multierr.Append(combined, runErr)
+			continue
+		}
+		if len(out) > 0 {
+			fmt.Print(string(out))
+		}
+	}
+	return combined
+}
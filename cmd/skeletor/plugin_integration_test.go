@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPluginHooks_SkipsNonMatchingAndRunsMatchingPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin fixture assumes a POSIX shell")
+	}
+
+	pluginsRoot := t.TempDir()
+	t.Setenv("SKELETOR_PLUGINS", pluginsRoot)
+
+	matched := filepath.Join(pluginsRoot, "matched")
+	require.NoError(t, os.Mkdir(matched, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(matched, "plugin.yaml"), []byte("name: matched\nversion: 0.1.0\ncommand: ./run.sh\nhooks: [post_gen]\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(matched, "run.sh"), []byte("#!/bin/sh\ncat > /dev/null\necho ran-matched\n"), 0o700))
+
+	unmatched := filepath.Join(pluginsRoot, "unmatched")
+	require.NoError(t, os.Mkdir(unmatched, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(unmatched, "plugin.yaml"), []byte("name: unmatched\nversion: 0.1.0\ncommand: ./run.sh\nhooks: [validate]\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(unmatched, "run.sh"), []byte("#!/bin/sh\ncat > /dev/null\necho ran-unmatched\n"), 0o700))
+
+	err := runPluginHooks("post_gen", t.TempDir(), map[string]interface{}{"MixinName": "foo"})
+	require.NoError(t, err)
+}
+
+func TestRunPluginHooks_NoPluginsIsNoOp(t *testing.T) {
+	t.Setenv("SKELETOR_PLUGINS", t.TempDir())
+	assert.NoError(t, runPluginHooks("post_gen", t.TempDir(), map[string]interface{}{}))
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/getporter/skeletor/pkg/plugin"
+)
+
+// buildPluginCommand returns the `skeletor plugin` command group for
+// discovering and managing plugins under $SKELETOR_PLUGINS and
+// ~/.skeletor/plugins (see pkg/plugin).
+func buildPluginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage skeletor plugins that extend createMixin's pre_gen/post_gen/validate hooks",
+	}
+	cmd.AddCommand(buildPluginListCommand())
+	cmd.AddCommand(buildPluginInstallCommand())
+	cmd.AddCommand(buildPluginRemoveCommand())
+	return cmd
+}
+
+func buildPluginListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List installed plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins, err := plugin.LoadAll()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tVERSION\tHOOKS\tDIR")
+			for _, p := range plugins {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Metadata.Name, p.Metadata.Version, strings.Join(p.Metadata.Hooks, ","), p.Dir)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func buildPluginInstallCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <path-or-git-url>",
+		Short: "Install a plugin into ~/.skeletor/plugins from a local directory or a git repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := args[0]
+
+			installRoot, err := plugin.DefaultInstallDir()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(installRoot, 0o750); err != nil {
+				return fmt.Errorf("failed to create %s: %w", installRoot, err)
+			}
+
+			var pluginDir string
+			if info, statErr := os.Stat(source); statErr == nil && info.IsDir() {
+				pluginDir, err = installPluginFromDir(source, installRoot)
+			} else {
+				pluginDir, err = installPluginFromGit(source, installRoot)
+			}
+			if err != nil {
+				return err
+			}
+
+			p, err := plugin.LoadPlugin(pluginDir)
+			if err != nil {
+				return fmt.Errorf("installed plugin failed to load: %w", err)
+			}
+			fmt.Printf("Installed plugin %q (%s) to %s\n", p.Metadata.Name, p.Metadata.Version, pluginDir)
+			return nil
+		},
+	}
+}
+
+// installPluginFromDir copies a local plugin directory (validated to carry a
+// plugin.yaml) into installRoot, named after its manifest's declared name
+// rather than the source directory's basename, so it's found under the same
+// name regardless of where it was copied from.
+func installPluginFromDir(source, installRoot string) (string, error) {
+	p, err := plugin.LoadPlugin(source)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a valid plugin: %w", source, err)
+	}
+
+	destDir := filepath.Join(installRoot, p.Metadata.Name)
+	if err := os.RemoveAll(destDir); err != nil {
+		return "", fmt.Errorf("failed to clear existing install at %s: %w", destDir, err)
+	}
+	if err := copyDir(source, destDir); err != nil {
+		return "", fmt.Errorf("failed to copy plugin into %s: %w", destDir, err)
+	}
+	return destDir, nil
+}
+
+// installPluginFromGit clones a git URL straight into installRoot/<repo
+// basename>, the same shallow-clone approach gitTemplateSource uses for
+// --template-source=git://.
+func installPluginFromGit(repoURL, installRoot string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(repoURL), ".git")
+	destDir := filepath.Join(installRoot, name)
+	if err := os.RemoveAll(destDir); err != nil {
+		return "", fmt.Errorf("failed to clear existing install at %s: %w", destDir, err)
+	}
+
+	fmt.Printf("Cloning %s into %s...\n", repoURL, destDir)
+	cmd := createCommand("git", "clone", "--depth=1", repoURL, destDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.RemoveAll(destDir)
+		return "", fmt.Errorf("failed to clone %s: %w", repoURL, err)
+	}
+	return destDir, nil
+}
+
+func buildPluginRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an installed plugin by name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			installRoot, err := plugin.DefaultInstallDir()
+			if err != nil {
+				return err
+			}
+			pluginDir := filepath.Join(installRoot, name)
+			if _, err := os.Stat(pluginDir); os.IsNotExist(err) {
+				return fmt.Errorf("no plugin named %q installed in %s", name, installRoot)
+			}
+			if err := os.RemoveAll(pluginDir); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", pluginDir, err)
+			}
+			fmt.Printf("Removed plugin %q\n", name)
+			return nil
+		},
+	}
+}
+
+// copyDir recursively copies source's contents into dest, creating dest if
+// necessary. Symlinks are skipped rather than followed or preserved, since a
+// plugin directory should be self-contained.
+func copyDir(source, dest string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(dest, rel)
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if info.IsDir() {
+			return os.MkdirAll(destPath, 0o750)
+		}
+		return copyFile(path, destPath, info.Mode())
+	})
+}
+
+func copyFile(source, dest string, mode os.FileMode) error {
+	in, err := os.Open(source) // #nosec G304 -- path comes from walking a plugin directory the user chose to install
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode) // #nosec G304 -- dest is built from the validated install root
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
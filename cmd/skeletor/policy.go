@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// PolicyEngine evaluates a boolean policy expression against input, the
+// pluggable interface behind each feature category's PolicyExpr. The
+// built-in engine is CEL (CELEngine); RegoEngine is an optional adapter for
+// shops standardized on OPA/Rego instead.
+type PolicyEngine interface {
+	Evaluate(expr string, input map[string]interface{}) (bool, error)
+}
+
+// DefaultPolicyEngine is the engine FeatureToggles.GetEnabledFeatures falls
+// back to when a FeatureToggles hasn't been given one via SetPolicyEngine.
+var DefaultPolicyEngine PolicyEngine = NewCELEngine()
+
+// CELEngine evaluates policy expressions written in Google's Common
+// Expression Language, e.g. `industry == 'health' && region in ['us','eu']`.
+type CELEngine struct{}
+
+// NewCELEngine returns a ready-to-use CEL engine. It holds no state: every
+// input's variable names form a fresh CEL environment, since the set of
+// available variables can differ between TemplateConfig instances.
+func NewCELEngine() *CELEngine {
+	return &CELEngine{}
+}
+
+// Evaluate compiles expr against input's keys (each exposed as a cel.DynType
+// variable) and evaluates it, requiring a bool result.
+func (e *CELEngine) Evaluate(expr string, input map[string]interface{}) (bool, error) {
+	opts := make([]cel.EnvOption, 0, len(input))
+	for name := range input {
+		opts = append(opts, cel.Variable(name, cel.DynType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return false, fmt.Errorf("failed to build CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return false, fmt.Errorf("invalid policy expression %q: %w", expr, issues.Err())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return false, fmt.Errorf("failed to build CEL program for %q: %w", expr, err)
+	}
+	out, _, err := program.Eval(input)
+	if err != nil {
+		return false, fmt.Errorf("policy expression %q failed: %w", expr, err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("policy expression %q did not evaluate to a bool, got %T", expr, out.Value())
+	}
+	return result, nil
+}
+
+// RegoEngine is an optional PolicyEngine adapter for shops standardized on
+// OPA/Rego instead of CEL. expr is evaluated as the body of an `allow` rule.
+type RegoEngine struct{}
+
+// NewRegoEngine returns a ready-to-use Rego engine.
+func NewRegoEngine() *RegoEngine {
+	return &RegoEngine{}
+}
+
+// Evaluate wraps expr in a `package skeletor; default allow = false; allow {
+// <expr> }` module and queries data.skeletor.allow.
+func (e *RegoEngine) Evaluate(expr string, input map[string]interface{}) (bool, error) {
+	module := fmt.Sprintf("package skeletor\n\ndefault allow = false\n\nallow {\n\t%s\n}\n", expr)
+	query, err := rego.New(
+		rego.Query("data.skeletor.allow"),
+		rego.Module("policy.rego", module),
+		rego.Input(input),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("invalid rego policy %q: %w", expr, err)
+	}
+
+	results, err := query.Eval(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("rego policy %q failed: %w", expr, err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return false, fmt.Errorf("rego policy %q produced no result", expr)
+	}
+	allowed, ok := results[0].Expressions[0].Value.(bool)
+	if !ok {
+		return false, fmt.Errorf("rego policy %q did not evaluate to a bool", expr)
+	}
+	return allowed, nil
+}
+
+// Decision records why a feature category's policy evaluation enabled or
+// disabled its features, one entry per feature, for the --explain CLI mode
+// and other audit output.
+type Decision struct {
+	Category string
+	Feature  string
+	Enabled  bool
+	Expr     string
+	Reason   string
+}
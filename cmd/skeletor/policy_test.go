@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePolicyEngine lets tests control PolicyEngine.Evaluate's outcome without
+// depending on real CEL/Rego semantics.
+type fakePolicyEngine struct {
+	allow bool
+	err   error
+}
+
+func (e *fakePolicyEngine) Evaluate(expr string, input map[string]interface{}) (bool, error) {
+	return e.allow, e.err
+}
+
+func TestFeatureToggles_EvaluateCategoryPolicy_NoExpr(t *testing.T) {
+	toggles := &FeatureToggles{}
+	toggles.SetPolicyEngine(&fakePolicyEngine{allow: false})
+
+	assert.True(t, toggles.evaluateCategoryPolicy("security", "", []string{"input_validation"}))
+	require.Len(t, toggles.Decisions(), 1)
+	assert.Equal(t, Decision{
+		Category: "security",
+		Feature:  "input_validation",
+		Enabled:  true,
+		Reason:   "static toggle, no policy expression",
+	}, toggles.Decisions()[0])
+}
+
+func TestFeatureToggles_EvaluateCategoryPolicy_Allowed(t *testing.T) {
+	toggles := &FeatureToggles{}
+	toggles.SetPolicyEngine(&fakePolicyEngine{allow: true})
+
+	assert.True(t, toggles.evaluateCategoryPolicy("compliance", "industry == 'health'", []string{"hipaa"}))
+	require.Len(t, toggles.Decisions(), 1)
+	assert.Equal(t, "hipaa", toggles.Decisions()[0].Feature)
+	assert.True(t, toggles.Decisions()[0].Enabled)
+	assert.Equal(t, "industry == 'health'", toggles.Decisions()[0].Expr)
+}
+
+func TestFeatureToggles_EvaluateCategoryPolicy_Denied(t *testing.T) {
+	toggles := &FeatureToggles{}
+	toggles.SetPolicyEngine(&fakePolicyEngine{allow: false})
+
+	assert.False(t, toggles.evaluateCategoryPolicy("compliance", "industry == 'health'", []string{"hipaa"}))
+	assert.False(t, toggles.Decisions()[0].Enabled)
+}
+
+func TestFeatureToggles_EvaluateCategoryPolicy_EngineErrorFailsClosed(t *testing.T) {
+	toggles := &FeatureToggles{}
+	toggles.SetPolicyEngine(&fakePolicyEngine{allow: true, err: errors.New("boom")})
+
+	assert.False(t, toggles.evaluateCategoryPolicy("compliance", "industry == 'health'", []string{"hipaa"}))
+	require.Len(t, toggles.Decisions(), 1)
+	assert.False(t, toggles.Decisions()[0].Enabled)
+	assert.Contains(t, toggles.Decisions()[0].Reason, "boom")
+}
+
+func TestFeatureToggles_GetEnabledFeatures_PolicyGated(t *testing.T) {
+	toggles := &FeatureToggles{
+		Compliance: &ComplianceFeatures{
+			Enabled:    true,
+			HIPAA:      true,
+			PolicyExpr: "industry == 'health'",
+		},
+	}
+	toggles.SetPolicyEngine(&fakePolicyEngine{allow: false})
+
+	enabled := toggles.GetEnabledFeatures()
+	assert.Nil(t, enabled["compliance"])
+	require.Len(t, toggles.Decisions(), 1)
+	assert.Equal(t, "hipaa", toggles.Decisions()[0].Feature)
+}
+
+func TestFeatureToggles_GetEnabledFeatures_NoPolicyExprUnaffected(t *testing.T) {
+	toggles := &FeatureToggles{
+		Security: &SecurityFeatures{
+			Enabled:         true,
+			InputValidation: true,
+		},
+	}
+
+	enabled := toggles.GetEnabledFeatures()
+	assert.Equal(t, []string{"input_validation"}, enabled["security"])
+}
+
+func TestCELEngine_Evaluate(t *testing.T) {
+	engine := NewCELEngine()
+
+	allowed, err := engine.Evaluate("region in ['us', 'eu']", map[string]interface{}{"region": "us"})
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = engine.Evaluate("region in ['us', 'eu']", map[string]interface{}{"region": "ap"})
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestCELEngine_Evaluate_NonBoolResult(t *testing.T) {
+	engine := NewCELEngine()
+
+	_, err := engine.Evaluate("region", map[string]interface{}{"region": "us"})
+	assert.Error(t, err)
+}
+
+func TestCELEngine_Evaluate_InvalidExpression(t *testing.T) {
+	engine := NewCELEngine()
+
+	_, err := engine.Evaluate("region ===", map[string]interface{}{"region": "us"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/getporter/skeletor/pkg/policy"
+)
+
+// PolicyGateOptions configures the Rego policy gate CreateMixin runs the
+// assembled template data through before rendering, and the final rendered
+// file list through before writing. A nil *PolicyGateOptions (or one with no
+// Rules) skips the gate entirely, so existing callers that never heard of
+// policy rules behave exactly as before.
+type PolicyGateOptions struct {
+	Rules      []policy.Rule
+	ReportPath string // where "dryrun"-scoped violations are appended, if any fire
+}
+
+// loadPolicyRules assembles the full rule set for a generation run: the
+// bundled SOC2/GDPR/HIPAA defaults (unless disabled), any directories the
+// template itself declares via config.PolicyRuleDirs, and any --policy-dir
+// the user passed on the CLI, in that order.
+func loadPolicyRules(tmplFS fs.FS, templateRoot string, config *TemplateConfig, extraDirs []string, includeBuiltin bool) ([]policy.Rule, error) {
+	var rules []policy.Rule
+
+	if includeBuiltin {
+		builtin, err := policy.LoadRules(policy.BuiltinRulesFS, "builtin")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load builtin policy rules: %w", err)
+		}
+		rules = append(rules, builtin...)
+	}
+
+	for _, dir := range config.PolicyRuleDirs {
+		loaded, err := policy.LoadRules(tmplFS, path.Join(templateRoot, dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policy rules from template dir %s: %w", dir, err)
+		}
+		rules = append(rules, loaded...)
+	}
+
+	for _, dir := range extraDirs {
+		loaded, err := policy.LoadRules(os.DirFS(dir), ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policy rules from --policy-dir %s: %w", dir, err)
+		}
+		rules = append(rules, loaded...)
+	}
+
+	return rules, nil
+}
+
+// runPolicyGate evaluates gate's rules against input and handles each
+// resulting Violation per its scoped action: any deny is joined into the
+// returned error (aborting generation), warn violations are printed and
+// otherwise ignored, and dryrun violations are appended to gate.ReportPath.
+// A nil gate, or one with no rules, is a no-op.
+func runPolicyGate(gate *PolicyGateOptions, input map[string]interface{}) error {
+	if gate == nil || len(gate.Rules) == 0 {
+		return nil
+	}
+
+	violations, err := policy.NewEvaluator(gate.Rules).Evaluate(context.Background(), input)
+	if err != nil {
+		return fmt.Errorf("policy evaluation failed: %w", err)
+	}
+
+	deny, warn, dryRun := policy.Partition(violations)
+	for _, v := range warn {
+		fmt.Printf("Warning: policy %q: %s\n", v.Rule, v.Message)
+	}
+	if len(dryRun) > 0 && gate.ReportPath != "" {
+		if err := appendPolicyReport(gate.ReportPath, dryRun); err != nil {
+			return err
+		}
+	}
+	if len(deny) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%d policy violation(s) denied generation:\n", len(deny))
+	for _, v := range deny {
+		msg += fmt.Sprintf("  - %s: %s\n", v.Rule, v.Message)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// appendPolicyReport appends each dryrun-scoped violation to path, one line
+// per violation, creating the file if it doesn't exist yet.
+func appendPolicyReport(path string, violations []policy.Violation) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- path comes from the trusted --policy-report-file flag
+	if err != nil {
+		return fmt.Errorf("failed to open policy report %s: %w", path, err)
+	}
+	defer f.Close()
+
+	for _, v := range violations {
+		if _, err := fmt.Fprintf(f, "[dryrun] %s: %s\n", v.Rule, v.Message); err != nil {
+			return fmt.Errorf("failed to write policy report %s: %w", path, err)
+		}
+	}
+	return nil
+}
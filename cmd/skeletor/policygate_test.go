@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/getporter/skeletor/pkg/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func denyRule(name, triggerKey string) policy.Rule {
+	return policy.Rule{
+		Name:          name,
+		ScopedActions: []policy.ScopedAction{policy.ActionDeny},
+		Module: `package skeletor.policy
+
+deny[msg] {
+	input.` + triggerKey + `
+	msg := "` + name + ` denied it"
+}
+`,
+	}
+}
+
+func TestRunPolicyGate_NilGateIsNoOp(t *testing.T) {
+	require.NoError(t, runPolicyGate(nil, map[string]interface{}{"Trigger": true}))
+}
+
+func TestRunPolicyGate_DenyAbortsWithCombinedMessage(t *testing.T) {
+	gate := &PolicyGateOptions{Rules: []policy.Rule{denyRule("rule_a", "Trigger")}}
+	err := runPolicyGate(gate, map[string]interface{}{"Trigger": true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule_a denied it")
+}
+
+func TestRunPolicyGate_WarnDoesNotAbort(t *testing.T) {
+	rule := denyRule("rule_a", "Trigger")
+	rule.ScopedActions = []policy.ScopedAction{policy.ActionWarn}
+	gate := &PolicyGateOptions{Rules: []policy.Rule{rule}}
+	require.NoError(t, runPolicyGate(gate, map[string]interface{}{"Trigger": true}))
+}
+
+func TestRunPolicyGate_DryRunAppendsToReportFile(t *testing.T) {
+	rule := denyRule("rule_a", "Trigger")
+	rule.ScopedActions = []policy.ScopedAction{policy.ActionDryRun}
+	reportPath := filepath.Join(t.TempDir(), "policy-report.log")
+	gate := &PolicyGateOptions{Rules: []policy.Rule{rule}, ReportPath: reportPath}
+
+	require.NoError(t, runPolicyGate(gate, map[string]interface{}{"Trigger": true}))
+
+	content, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "rule_a denied it")
+}
+
+func TestLoadPolicyRules_TemplateDirAndBuiltin(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"template/policy/policy.yaml": &fstest.MapFile{Data: []byte(`
+- name: custom_rule
+  file: custom.rego
+  scoped_actions: ["deny"]
+`)},
+		"template/policy/custom.rego": &fstest.MapFile{Data: []byte(`package skeletor.policy
+
+deny[msg] {
+	input.Custom
+	msg := "custom rule fired"
+}
+`)},
+	}
+	config := &TemplateConfig{PolicyRuleDirs: []string{"policy"}}
+
+	rules, err := loadPolicyRules(mockFS, "template", config, nil, false)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "custom_rule", rules[0].Name)
+
+	withBuiltin, err := loadPolicyRules(mockFS, "template", config, nil, true)
+	require.NoError(t, err)
+	assert.Greater(t, len(withBuiltin), len(rules))
+}
+
+func TestCreateMixin_PolicyGateDeniesOnAssembledData(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+	data["MixinName"] = "blocked-mixin"
+
+	gate := &PolicyGateOptions{Rules: []policy.Rule{{
+		Name:          "no_blocked_name",
+		ScopedActions: []policy.ScopedAction{policy.ActionDeny},
+		Module: `package skeletor.policy
+
+deny[msg] {
+	input.MixinName == "blocked-mixin"
+	msg := "blocked-mixin is not allowed"
+}
+`,
+	}}}
+
+	err := (&Engine{}).CreateMixin(data, mockFS, ".", outputDir, config, "", 0, "", gate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked-mixin is not allowed")
+
+	_, statErr := os.Stat(filepath.Join(outputDir, "fresh.txt"))
+	assert.True(t, os.IsNotExist(statErr), "a pre-render deny must stop generation before anything is written")
+}
+
+func TestCreateMixin_PolicyGateDeniesOnRenderedContent(t *testing.T) {
+	mockFS, config, data := conflictTestFixture()
+	outputDir := t.TempDir()
+
+	gate := &PolicyGateOptions{Rules: []policy.Rule{{
+		Name:          "no_fresh_content",
+		ScopedActions: []policy.ScopedAction{policy.ActionDeny},
+		Module: `package skeletor.policy
+
+deny[msg] {
+	some f
+	input.files[f].content == "fresh content for my-mixin"
+	msg := "rendered content not allowed"
+}
+`,
+	}}}
+
+	err := (&Engine{}).CreateMixin(data, mockFS, ".", outputDir, config, "", 0, "", gate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rendered content not allowed")
+
+	_, statErr := os.Stat(filepath.Join(outputDir, "fresh.txt"))
+	assert.True(t, os.IsNotExist(statErr), "a post-render deny must stop generation before anything is written")
+}
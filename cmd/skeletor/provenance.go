@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// provenanceAttestationPath is where --emit-provenance writes its in-toto
+// statement, relative to the generated project's output directory, and
+// where verify reads it back from.
+const provenanceAttestationPath = "attestations/provenance.intoto.jsonl"
+
+// provenanceStatement is the in-toto v1 Statement layer --emit-provenance
+// writes, mirroring the shape ci/main.go's Dagger release pipeline and
+// release.go (see magefile.go's Publish) attach to skeletor's own release
+// artifacts -- here describing a generated project instead of a build.
+type provenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []provenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     provenancePredicate `json:"predicate"`
+}
+
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenancePredicate struct {
+	Builder         provenanceBuilder  `json:"builder"`
+	BuildType       string             `json:"buildType"`
+	BuildDefinition provenanceBuildDef `json:"buildDefinition"`
+}
+
+type provenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+// provenanceBuildDef captures what a consumer needs to reproduce or audit
+// this generation: which template tree was used (by origin URI and content
+// digest), at what compliance level, and with which effective variables.
+type provenanceBuildDef struct {
+	TemplateSource  string                 `json:"templateSource"`
+	TemplateDigest  string                 `json:"templateDigest"`
+	ComplianceLevel string                 `json:"complianceLevel"`
+	Variables       map[string]interface{} `json:"variables"`
+}
+
+// resolvedTemplateOrigin picks whichever of --template-source/--template-url/
+// --template-dir selected the template that produced this project, falling
+// back to "embed://<kind>" for the default built-in template.
+func resolvedTemplateOrigin(templateSource, templateUrl, templateDir string, kind TemplateKind) string {
+	switch {
+	case templateSource != "":
+		return templateSource
+	case templateUrl != "":
+		return templateUrl
+	case templateDir != "":
+		return templateDir
+	default:
+		return "embed://" + string(kind)
+	}
+}
+
+// provenanceExcludedVars are buildTemplateData's own bookkeeping keys, not
+// variables a template author or consumer supplied.
+var provenanceExcludedVars = map[string]bool{
+	"OutputDir":      true,
+	"TemplateDigest": true,
+	"Dependencies":   true,
+}
+
+// provenanceVariables strips buildTemplateData's internal bookkeeping keys
+// from data, leaving the effective variable map the predicate records.
+func provenanceVariables(data map[string]interface{}) map[string]interface{} {
+	vars := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if provenanceExcludedVars[k] {
+			continue
+		}
+		vars[k] = v
+	}
+	return vars
+}
+
+// hashGeneratedFiles walks dir, excluding the attestations directory the
+// provenance attestation itself lands in, and returns one subject entry per
+// regular file with its sha256 digest and dir-relative, slash-separated
+// path -- reusing treedigest.go's hashFileContent so a generated project's
+// per-file digests are computed the same way the template tree's own
+// digest is.
+func hashGeneratedFiles(dir string) ([]provenanceSubject, error) {
+	fsys := os.DirFS(dir)
+	var subjects []provenanceSubject
+	walkErr := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(path, "attestations/") {
+			return nil
+		}
+		sum, err := hashFileContent(fsys, path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		subjects = append(subjects, provenanceSubject{
+			Name:   path,
+			Digest: map[string]string{"sha256": sum},
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to walk %s for provenance subjects: %w", dir, walkErr)
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].Name < subjects[j].Name })
+	return subjects, nil
+}
+
+// buildProjectProvenance hashes every file createMixin (and the hooks/
+// compliance/auth steps after it) wrote under outputDir and assembles the
+// in-toto statement describing how the project was produced.
+func buildProjectProvenance(outputDir, templateOrigin, templateDigest, complianceLevel string, data map[string]interface{}) (provenanceStatement, error) {
+	subjects, err := hashGeneratedFiles(outputDir)
+	if err != nil {
+		return provenanceStatement{}, err
+	}
+
+	return provenanceStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       subjects,
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate: provenancePredicate{
+			Builder:   provenanceBuilder{ID: fmt.Sprintf("skeletor@%s+%s", Version, Commit)},
+			BuildType: "https://github.com/getporter/skeletor/create@v1",
+			BuildDefinition: provenanceBuildDef{
+				TemplateSource:  templateOrigin,
+				TemplateDigest:  templateDigest,
+				ComplianceLevel: complianceLevel,
+				Variables:       provenanceVariables(data),
+			},
+		},
+	}, nil
+}
+
+// writeProvenanceAttestation marshals stmt to outputDir's
+// provenanceAttestationPath.
+func writeProvenanceAttestation(stmt provenanceStatement, outputDir string) error {
+	dir := filepath.Join(outputDir, filepath.Dir(provenanceAttestationPath))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	data, err := json.MarshalIndent(stmt, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal provenance statement: %w", err)
+	}
+
+	path := filepath.Join(outputDir, provenanceAttestationPath)
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// verifyProjectProvenance recomputes every generated file's digest in dir
+// and diffs it against dir's embedded attestation, returning an error
+// describing every file that's missing, added, or whose content drifted
+// since generation.
+func verifyProjectProvenance(dir string) error {
+	attestationPath := filepath.Join(dir, provenanceAttestationPath)
+	raw, err := os.ReadFile(attestationPath) // #nosec G304 -- path is derived from a user-supplied project directory argument
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", attestationPath, err)
+	}
+
+	var stmt provenanceStatement
+	if err := json.Unmarshal(raw, &stmt); err != nil {
+		return fmt.Errorf("failed to parse %s as an in-toto statement: %w", attestationPath, err)
+	}
+
+	want := make(map[string]string, len(stmt.Subject))
+	for _, subject := range stmt.Subject {
+		want[subject.Name] = subject.Digest["sha256"]
+	}
+
+	got, err := hashGeneratedFiles(dir)
+	if err != nil {
+		return err
+	}
+	gotByName := make(map[string]string, len(got))
+	for _, subject := range got {
+		gotByName[subject.Name] = subject.Digest["sha256"]
+	}
+
+	var drift []string
+	for name, wantSum := range want {
+		gotSum, ok := gotByName[name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s: missing (recorded in the attestation, not found on disk)", name))
+			continue
+		}
+		if gotSum != wantSum {
+			drift = append(drift, fmt.Sprintf("%s: content changed since generation", name))
+		}
+	}
+	for name := range gotByName {
+		if _, ok := want[name]; !ok {
+			drift = append(drift, fmt.Sprintf("%s: added since generation (not in the attestation)", name))
+		}
+	}
+
+	if len(drift) > 0 {
+		sort.Strings(drift)
+		return fmt.Errorf("provenance verification failed for %s:\n  %s", dir, strings.Join(drift, "\n  "))
+	}
+	return nil
+}
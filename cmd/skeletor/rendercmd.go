@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildRenderCommand returns `skeletor render`, which walks a template's
+// files the same way `create` would without generating anything, reporting
+// a kept/dropped decision per candidate file -- useful for checking a new
+// skip_patterns/file_conditions entry does what its author expects before
+// running it against a real output directory.
+func buildRenderCommand() *cobra.Command {
+	var (
+		name            string
+		author          string
+		modulePath      string
+		outputDir       string
+		nonInteractive  bool
+		kindRaw         string
+		templateUrl     string
+		templateRef     string
+		noCache         bool
+		refreshCache    bool
+		verifySignature bool
+		cosignKey       string
+		templateDir     string
+		templateSource  string
+		extraVars       []string
+		valuesFile      string
+		complianceLevel string
+		explain         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render [template-name]",
+		Short: "Walk a template's files without generating anything, reporting which rule kept or dropped each one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var templateName string
+			if len(args) > 0 {
+				templateName = args[0]
+			}
+
+			kind, err := parseTemplateKind(kindRaw)
+			if err != nil {
+				return err
+			}
+
+			verifySignature = verifySignature || cosignKey != ""
+			tmplFS, rootDirForWalk, cleanupDir, err := getTemplateSource(templateSource, templateUrl, templateDir, templateRef, templateName, kind, noCache, refreshCache, verifySignature, cosignKey)
+			if err != nil {
+				return err
+			}
+			if cleanupDir != "" {
+				defer os.RemoveAll(cleanupDir)
+			}
+
+			config, err := LoadTemplateConfig(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load template config from %s: %w", rootDirForWalk, err)
+			}
+			if err := DefaultFeatureGate.SetFromTemplate(config.FeatureToggles); err != nil {
+				return fmt.Errorf("failed to apply feature toggles from %s: %w", rootDirForWalk, err)
+			}
+
+			var values map[string]interface{}
+			if valuesFile != "" {
+				values, err = loadValuesFile(valuesFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			flags, err := LoadFeatureFlagSet(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load features.yaml from %s: %w", rootDirForWalk, err)
+			}
+			if err := applyFeatureFlagOverrides(flags, extraVars, values); err != nil {
+				return err
+			}
+
+			data, err := buildTemplateDataWithFeatures(config, name, author, modulePath, outputDir, complianceLevel, nonInteractive, extraVars, values,
+				flags, false, false, false, false, "", "", "", "")
+			if err != nil {
+				return err
+			}
+
+			decisions, err := explainTemplateWalk(tmplFS, rootDirForWalk, config, data)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			if explain {
+				fmt.Fprintln(w, "PATH\tDECISION\tRULE")
+				for _, d := range decisions {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", d.Path, d.Decision, d.Rule)
+				}
+			} else {
+				fmt.Fprintln(w, "PATH\tDECISION")
+				for _, d := range decisions {
+					fmt.Fprintf(w, "%s\t%s\n", d.Path, d.Decision)
+				}
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the mixin (lowercase)")
+	cmd.Flags().StringVar(&author, "author", "", "Author name for the mixin")
+	cmd.Flags().StringVar(&modulePath, "module", "", "Go module path (default: github.com/getporter/<name>)")
+	cmd.Flags().StringVar(&outputDir, "output", "", "Output directory (default: ./<name>)")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Run in non-interactive mode")
+	cmd.Flags().StringVar(&kindRaw, "kind", string(TemplateKindMixin), "Built-in template tree to render: mixin (the default) or plugin")
+	cmd.Flags().StringVar(&templateUrl, "template-url", "", "URL to a git repository containing the template")
+	cmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch, tag, or commit to check out from --template-url (default: the repository's default branch)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-clone --template-url into a temp dir instead of reusing the persistent template cache")
+	cmd.Flags().BoolVar(&refreshCache, "refresh", false, "Bypass a cached --template-url entry and re-fetch it even if the resolved commit is already cached")
+	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Local directory containing the template")
+	cmd.Flags().StringVar(&templateSource, "template-source", "", "Template source URI, e.g. oci://ghcr.io/foo/template:v1.2.0, git://github.com/foo/bar.git#v1.0, http://example.com/template.tar.gz#sha256:..., dir:///local/path; takes priority over --template-dir/--template-url")
+	cmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "Require a valid cosign signature before using an oci://, docker://, or bare registry-ref --template-url")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "Public key file cosign should verify against; implies --verify-signature")
+	cmd.Flags().StringArrayVar(&extraVars, "var", []string{}, "Extra variables in KEY=VALUE format")
+	cmd.Flags().StringVar(&valuesFile, "values-file", "", "YAML or JSON file pre-populating variable values (--var still overrides it)")
+	cmd.Flags().StringVar(&complianceLevel, "compliance-level", "basic", "Compliance level (basic, slsa-l1, slsa-l3)")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Print which rule kept or dropped each candidate file, not just the final decision")
+
+	return cmd
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// buildSchemaCommand emits a JSON Schema describing a template's variables,
+// so CI, a values.yaml validator, or an IDE/backstage-style scaffolder can
+// generate a form or check an input file without re-implementing
+// effectiveVariableSchema/validateAgainstSchema in another language.
+func buildSchemaCommand() *cobra.Command {
+	var (
+		kindRaw         string
+		templateUrl     string
+		templateRef     string
+		noCache         bool
+		refreshCache    bool
+		verifySignature bool
+		cosignKey       string
+		templateDir     string
+		templateSource  string
+		templateName    string
+		output          string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "schema [template-name]",
+		Short: "Print a JSON Schema describing a template's required variables",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				templateName = args[0]
+			}
+			kind, err := parseTemplateKind(kindRaw)
+			if err != nil {
+				return err
+			}
+
+			verifySignature = verifySignature || cosignKey != ""
+			tmplFS, rootDirForWalk, cleanupDir, err := getTemplateSource(templateSource, templateUrl, templateDir, templateRef, templateName, kind, noCache, refreshCache, verifySignature, cosignKey)
+			if err != nil {
+				return err
+			}
+			if cleanupDir != "" {
+				defer os.RemoveAll(cleanupDir)
+			}
+
+			config, err := LoadTemplateConfig(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load template config from %s: %w", rootDirForWalk, err)
+			}
+
+			encoded, err := json.MarshalIndent(variablesJSONSchema(config), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode variables schema: %w", err)
+			}
+
+			if output == "" {
+				fmt.Println(string(encoded))
+				return nil
+			}
+			if err := os.WriteFile(output, append(encoded, '\n'), 0o600); err != nil {
+				return fmt.Errorf("failed to write variables schema to %s: %w", output, err)
+			}
+			fmt.Printf("Variables schema written to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kindRaw, "kind", string(TemplateKindMixin), "Built-in template tree to describe: mixin (the default) or plugin")
+	cmd.Flags().StringVar(&templateUrl, "template-url", "", "URL to a git repository containing the template")
+	cmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch, tag, or commit to check out from --template-url (default: the repository's default branch)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-clone --template-url into a temp dir instead of reusing the persistent template cache")
+	cmd.Flags().BoolVar(&refreshCache, "refresh", false, "Bypass a cached --template-url entry and re-fetch it even if the resolved commit is already cached")
+	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Local directory containing the template")
+	cmd.Flags().StringVar(&templateSource, "template-source", "", "Template source URI, e.g. oci://ghcr.io/foo/template:v1.2.0, git://github.com/foo/bar.git#v1.0, http://example.com/template.tar.gz#sha256:..., dir:///local/path; takes priority over --template-dir/--template-url")
+	cmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "Require a valid cosign signature before using an oci://, docker://, or bare registry-ref --template-url")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "Public key file cosign should verify against; implies --verify-signature")
+	cmd.Flags().StringVar(&output, "output", "", "Write the schema to this file instead of stdout")
+
+	return cmd
+}
+
+// variablesJSONSchema renders config.Variables as a draft-07 JSON Schema
+// object, one property per variable built from effectiveVariableSchema --
+// the same schema buildTemplateData validates --var/--values-file/default
+// values against -- so the exported schema never drifts from what `create`
+// actually enforces.
+func variablesJSONSchema(config *TemplateConfig) map[string]interface{} {
+	properties := make(map[string]interface{}, len(config.Variables))
+	var required []string
+
+	for varName, varConfig := range config.Variables {
+		schema := effectiveVariableSchema(varConfig)
+		properties[varName] = variableJSONSchemaProperty(varConfig, schema)
+		if schema.Required {
+			required = append(required, varName)
+		}
+	}
+	sort.Strings(required) // deterministic output for diffable generated schemas
+
+	result := map[string]interface{}{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+// variableJSONSchemaProperty converts one Variable's effective schema into a
+// JSON Schema property object.
+func variableJSONSchemaProperty(varConfig Variable, schema VariableSchema) map[string]interface{} {
+	prop := map[string]interface{}{"type": jsonSchemaType(schema.Type)}
+	if varConfig.Description != "" {
+		prop["description"] = varConfig.Description
+	}
+	if varConfig.Default != nil {
+		prop["default"] = varConfig.Default
+	}
+	if len(schema.Enum) > 0 {
+		prop["enum"] = schema.Enum
+	}
+	if schema.Pattern != "" {
+		prop["pattern"] = schema.Pattern
+	}
+	if schema.Minimum != nil {
+		prop["minimum"] = *schema.Minimum
+	}
+	if schema.Maximum != nil {
+		prop["maximum"] = *schema.Maximum
+	}
+	if schema.MinLength != nil {
+		prop["minLength"] = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		prop["maxLength"] = *schema.MaxLength
+	}
+	return prop
+}
+
+// jsonSchemaType normalizes a VariableSchema.Type -- which accepts
+// coerceToSchema's looser aliases (bool, int) -- to the canonical JSON
+// Schema primitive name, defaulting to "string" like coerceToSchema does for
+// an empty or unrecognized type.
+func jsonSchemaType(t string) string {
+	switch strings.ToLower(t) {
+	case "bool", "boolean":
+		return "boolean"
+	case "int", "integer":
+		return "integer"
+	case "number":
+		return "number"
+	case "array":
+		return "array"
+	case "object":
+		return "object"
+	default:
+		return "string"
+	}
+}
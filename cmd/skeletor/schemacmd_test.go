@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaType(t *testing.T) {
+	cases := map[string]string{
+		"":        "string",
+		"string":  "string",
+		"bool":    "boolean",
+		"boolean": "boolean",
+		"int":     "integer",
+		"integer": "integer",
+		"number":  "number",
+		"array":   "array",
+		"object":  "object",
+		"weird":   "string",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, jsonSchemaType(in), "type %q", in)
+	}
+}
+
+func TestVariablesJSONSchema(t *testing.T) {
+	maxLen := 20
+	config := &TemplateConfig{
+		Variables: map[string]Variable{
+			"MixinName": {
+				Description: "Name of the mixin",
+				Required:    true,
+				Schema:      &VariableSchema{Type: "string", Pattern: "^[a-z]+$", MaxLength: &maxLen},
+			},
+			"Level": {
+				Description: "A level",
+				Default:     "basic",
+				Choices:     []string{"basic", "advanced"},
+			},
+		},
+	}
+
+	schema := variablesJSONSchema(config)
+	require.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	require.Equal(t, "object", schema["type"])
+	require.Equal(t, []string{"MixinName"}, schema["required"])
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	require.True(t, ok)
+
+	mixinName, ok := properties["MixinName"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "string", mixinName["type"])
+	assert.Equal(t, "Name of the mixin", mixinName["description"])
+	assert.Equal(t, "^[a-z]+$", mixinName["pattern"])
+	assert.Equal(t, 20, mixinName["maxLength"])
+
+	level, ok := properties["Level"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "string", level["type"])
+	assert.Equal(t, "basic", level["default"])
+	assert.Equal(t, []interface{}{"basic", "advanced"}, level["enum"])
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// evaluateSkipPatterns reports whether destRelPath (a directory or a file)
+// matches one of patterns, and if so which pattern matched. Patterns are
+// doublestar globs evaluated against destRelPath itself, not the original
+// walked path, so they read the same as a PathPolicy/Licenses Root glob.
+//
+// The caller decides what a match means for walk control: matching a
+// directory prunes its whole subtree (the walk never descends into it,
+// since nothing under an excluded directory could itself be wanted), while
+// matching a leaf file just drops that one file -- the same fs.SkipDir vs.
+// "return nil" distinction CreateMixin's walk already makes for any other
+// skip reason.
+func evaluateSkipPatterns(patterns []string, destRelPath string) (matchedPattern string, matched bool) {
+	for _, pattern := range patterns {
+		ok, err := doublestar.Match(pattern, destRelPath)
+		if err != nil || !ok {
+			continue
+		}
+		return pattern, true
+	}
+	return "", false
+}
+
+// fileConditionRule is one FileConditions entry, compiled so its literal
+// prefix (the text before its first glob metacharacter) can be compared for
+// specificity the same way PathPolicy prefers a more specific path block.
+type fileConditionRule struct {
+	pattern     string
+	expr        string
+	specificity int
+}
+
+// compileFileConditions sorts config.FileConditions' entries most-specific
+// (longest literal prefix) first, so evaluateFileCondition's first match is
+// always the best one regardless of the source map's iteration order.
+func compileFileConditions(conditions map[string]string) []fileConditionRule {
+	rules := make([]fileConditionRule, 0, len(conditions))
+	for pattern, expr := range conditions {
+		rules = append(rules, fileConditionRule{
+			pattern:     pattern,
+			expr:        expr,
+			specificity: len(literalPathPrefix(pattern)),
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity > rules[j].specificity
+		}
+		return rules[i].pattern < rules[j].pattern // stable tiebreak for equally-specific patterns
+	})
+	return rules
+}
+
+// evaluateFileCondition finds the most specific compiled rule matching
+// destRelPath, renders its Go template expression against data, and parses
+// the result as a bool (matching strconv.ParseBool's "true"/"false"/"1"/"0"
+// etc. vocabulary; an empty result is treated as false). It returns
+// matched=false if no rule's pattern matches destRelPath at all, meaning the
+// caller should fall through to ConditionalPaths/PathPolicy as before.
+func evaluateFileCondition(rules []fileConditionRule, destRelPath string, data map[string]interface{}, funcs template.FuncMap) (matchedPattern string, keep bool, matched bool, err error) {
+	for _, rule := range rules {
+		ok, matchErr := doublestar.Match(rule.pattern, destRelPath)
+		if matchErr != nil || !ok {
+			continue
+		}
+
+		tmpl, parseErr := template.New("fileCondition").Funcs(funcs).Option("missingkey=error").Parse(rule.expr)
+		if parseErr != nil {
+			return rule.pattern, false, true, fmt.Errorf("file_conditions %q: failed to parse expression %q: %w", rule.pattern, rule.expr, wrapUnknownFunctionError(parseErr, funcs))
+		}
+		var buf bytes.Buffer
+		if execErr := tmpl.Execute(&buf, data); execErr != nil {
+			return rule.pattern, false, true, fmt.Errorf("file_conditions %q: failed to render expression %q: %w", rule.pattern, rule.expr, execErr)
+		}
+
+		result := strings.TrimSpace(buf.String())
+		if result == "" {
+			return rule.pattern, false, true, nil
+		}
+		parsed, parseErr := strconv.ParseBool(result)
+		if parseErr != nil {
+			return rule.pattern, false, true, fmt.Errorf("file_conditions %q: expression %q rendered %q, which isn't a bool", rule.pattern, rule.expr, result)
+		}
+		return rule.pattern, parsed, true, nil
+	}
+	return "", false, false, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateSkipPatterns(t *testing.T) {
+	patterns := []string{"pkg/security/**", "*.tmp"}
+
+	pattern, matched := evaluateSkipPatterns(patterns, "pkg/security/auth.go")
+	assert.True(t, matched)
+	assert.Equal(t, "pkg/security/**", pattern)
+
+	_, matched = evaluateSkipPatterns(patterns, "pkg/auth.go")
+	assert.False(t, matched)
+
+	pattern, matched = evaluateSkipPatterns(patterns, "notes.tmp")
+	assert.True(t, matched)
+	assert.Equal(t, "*.tmp", pattern)
+}
+
+func TestCompileFileConditions_OrdersMostSpecificFirst(t *testing.T) {
+	rules := compileFileConditions(map[string]string{
+		"pkg/**":          "{{ true }}",
+		"pkg/security/**": "{{ false }}",
+	})
+	require.Len(t, rules, 2)
+	assert.Equal(t, "pkg/security/**", rules[0].pattern)
+	assert.Equal(t, "pkg/**", rules[1].pattern)
+}
+
+func TestEvaluateFileCondition(t *testing.T) {
+	rules := compileFileConditions(map[string]string{
+		"pkg/security/**": "{{ .EnableSecurity }}",
+	})
+	funcs := template.FuncMap{}
+
+	pattern, keep, matched, err := evaluateFileCondition(rules, "pkg/security/auth.go", map[string]interface{}{"EnableSecurity": true}, funcs)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.True(t, keep)
+	assert.Equal(t, "pkg/security/**", pattern)
+
+	_, keep, matched, err = evaluateFileCondition(rules, "pkg/security/auth.go", map[string]interface{}{"EnableSecurity": false}, funcs)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.False(t, keep)
+
+	_, _, matched, err = evaluateFileCondition(rules, "pkg/other.go", map[string]interface{}{"EnableSecurity": false}, funcs)
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestEvaluateFileCondition_InvalidBoolResult(t *testing.T) {
+	rules := compileFileConditions(map[string]string{
+		"pkg/**": "{{ .Name }}",
+	})
+	_, _, matched, err := evaluateFileCondition(rules, "pkg/foo.go", map[string]interface{}{"Name": "widget"}, template.FuncMap{})
+	assert.True(t, matched)
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildSourcesCommand returns the `skeletor sources` command group for
+// inspecting and managing the ~/.skeletor/sources cache that
+// --template-source resolves into.
+func buildSourcesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Manage cached template sources",
+	}
+	cmd.AddCommand(buildSourcesListCommand())
+	cmd.AddCommand(buildSourcesPruneCommand())
+	cmd.AddCommand(buildSourcesVerifyCommand())
+	return cmd
+}
+
+func buildSourcesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List cached template sources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifests, err := listCachedSources()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "SCHEME\tREF\tRESOLVED AT\tCHECKSUM")
+			for _, manifest := range manifests {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					manifest.Scheme, manifest.Ref, manifest.ResolvedAt.Format("2006-01-02T15:04:05Z07:00"), manifest.Checksum)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func buildSourcesPruneCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Remove all cached template sources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pruneCachedSources(); err != nil {
+				return err
+			}
+			fmt.Println("Source cache pruned.")
+			return nil
+		},
+	}
+}
+
+func buildSourcesVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Verify cached template sources still match their recorded checksums",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cacheRoot, err := skeletorSourcesCacheDir()
+			if err != nil {
+				return err
+			}
+			entries, err := os.ReadDir(cacheRoot)
+			if err != nil {
+				return fmt.Errorf("failed to read source cache directory: %w", err)
+			}
+
+			var failures []string
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				cacheDir := filepath.Join(cacheRoot, entry.Name())
+				if err := verifyCachedSource(cacheDir); err != nil {
+					failures = append(failures, fmt.Sprintf("%s: %v", entry.Name(), err))
+					continue
+				}
+				fmt.Printf("%s: OK\n", entry.Name())
+			}
+
+			if len(failures) > 0 {
+				return fmt.Errorf("%d cached source(s) failed verification:\n  %s", len(failures), strings.Join(failures, "\n  "))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// templateURLCacheDir returns ~/.skeletor/cache/templates, creating it if
+// necessary -- the same ~/.skeletor root `skeletor sources` and `skeletor
+// plugin` already cache under, so every content-addressed cache skeletor
+// keeps lives in one predictable place.
+func templateURLCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory for template cache: %w", err)
+	}
+	dir := filepath.Join(home, ".skeletor", "cache", "templates")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create template cache directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// normalizeTemplateURL trims whitespace and a trailing "/" or ".git" so that
+// equivalent URLs (with or without the trailing suffix) share a cache entry.
+func normalizeTemplateURL(url string) string {
+	url = strings.TrimSpace(url)
+	url = strings.TrimSuffix(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	return url
+}
+
+// templateCacheKey hashes a normalized template URL and a resolved commit
+// SHA -- not the floating ref -- to the cache directory name it resolves to.
+// Keying on the commit rather than the ref means two refs that happen to
+// point at the same commit (e.g. a tag and the branch it was cut from)
+// share one cache entry, and a branch that has since moved on gets a fresh
+// entry instead of silently serving the commit it pointed at last time.
+func templateCacheKey(url, commitSHA string) string {
+	sum := sha256.Sum256([]byte(normalizeTemplateURL(url) + "#" + commitSHA))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// templateCacheManifest records what a --template-url cache entry resolved
+// to, written alongside the clone as manifest.json.
+type templateCacheManifest struct {
+	URL        string    `json:"url"`
+	Ref        string    `json:"ref,omitempty"`
+	CommitSHA  string    `json:"commit_sha"`
+	TreeDigest string    `json:"tree_digest"`
+	FetchedAt  time.Time `json:"fetched_at"`
+}
+
+func writeTemplateCacheManifest(cacheDir string, manifest templateCacheManifest) error {
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template cache manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(cacheDir, "manifest.json"), encoded, 0o600)
+}
+
+func readTemplateCacheManifest(cacheDir string) (templateCacheManifest, error) {
+	var manifest templateCacheManifest
+	raw, err := os.ReadFile(filepath.Join(cacheDir, "manifest.json")) // #nosec G304 -- cacheDir is derived from a hash under templateURLCacheDir()
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest in %s: %w", cacheDir, err)
+	}
+	return manifest, nil
+}
+
+// parseGitPlusTemplateURL recognizes the "git+https://…@<ref>" shorthand
+// (the same convention pip/go get use to pin a ref inline in a VCS URL),
+// stripping the "git+" prefix and splitting off an "@<ref>" suffix. The ref
+// is taken as everything after the *last* "@" in the URL's path -- not just
+// after the last "/" -- so a ref containing its own slashes (a branch like
+// "release/1.0", or "refs/heads/main") still splits off whole, while a plain
+// https://user@host/repo.git URL's userinfo "@" (which appears before the
+// first path segment) is never mistaken for a ref separator. Any
+// --template-url not starting with "git+" is returned unchanged with no
+// inline ref, so a bare https://github.com/foo/bar.git still works exactly
+// as before this existed.
+func parseGitPlusTemplateURL(templateUrl string) (repoURL, ref string) {
+	if !strings.HasPrefix(templateUrl, "git+") {
+		return templateUrl, ""
+	}
+	trimmed := strings.TrimPrefix(templateUrl, "git+")
+
+	authorityStart := strings.Index(trimmed, "://")
+	if authorityStart == -1 {
+		return trimmed, ""
+	}
+	authorityStart += len("://")
+
+	firstPathSlash := strings.IndexByte(trimmed[authorityStart:], '/')
+	if firstPathSlash == -1 {
+		return trimmed, "" // no path segment to search for a ref after, e.g. just a bare host
+	}
+	pathStart := authorityStart + firstPathSlash
+
+	if at := strings.LastIndexByte(trimmed[pathStart:], '@'); at != -1 {
+		return trimmed[:pathStart+at], trimmed[pathStart+at+1:]
+	}
+	return trimmed, ""
+}
+
+// resolveTemplateURL returns the filesystem a --template-url/--template-ref
+// pair resolves to, plus a cleanup dir for the caller to os.RemoveAll
+// (empty unless noCache, since a cache entry is meant to persist across
+// runs).
+//
+// With caching enabled (the default), templateRef is first resolved to a
+// commit SHA with `git ls-remote` -- cheap, and doesn't require a clone --
+// and entries live under templateURLCacheDir(), keyed by
+// templateCacheKey(url, commitSHA). A cache hit (the commit's entry already
+// exists) skips cloning entirely; a miss fetches just that commit at
+// depth 1 (see cloneTemplateURLAtCommit) and computes a recursive Merkle digest of the checked-out tree
+// (see computeTreeDigest), stored alongside as tree.digest for the `skeletor
+// cache list` manifest. refresh forces a miss even when a cache entry
+// already exists, for a template author who knows a ref moved and doesn't
+// want to wait for it to resolve to a new commit naturally. noCache bypasses
+// the persistent cache altogether: a fresh `git clone --depth=1` into a temp
+// dir removed by the caller.
+func resolveTemplateURL(templateUrl, templateRef string, noCache, refresh bool) (fs.FS, string, string, error) {
+	if noCache {
+		return cloneTemplateURLToTempDir(templateUrl, templateRef)
+	}
+
+	commitSHA, err := resolveRemoteCommitSHA(templateUrl, templateRef)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	cacheRoot, err := templateURLCacheDir()
+	if err != nil {
+		return nil, "", "", err
+	}
+	cacheDir := filepath.Join(cacheRoot, templateCacheKey(templateUrl, commitSHA))
+
+	if manifest, err := readTemplateCacheManifest(cacheDir); err == nil && manifest.TreeDigest != "" && !refresh {
+		fmt.Printf("Using cached template %s@%s\n", templateUrl, shortCommitSHA(commitSHA))
+		return os.DirFS(cacheDir), ".", "", nil
+	}
+
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return nil, "", "", fmt.Errorf("failed to clear stale cache entry %s: %w", cacheDir, err)
+	}
+	fmt.Printf("Fetching template %s@%s...\n", templateUrl, shortCommitSHA(commitSHA))
+	if err := cloneTemplateURLAtCommit(templateUrl, commitSHA, cacheDir); err != nil {
+		_ = os.RemoveAll(cacheDir)
+		return nil, "", "", err
+	}
+
+	digest, err := computeTreeDigest(os.DirFS(cacheDir), ".")
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "tree.digest"), []byte(digest+"\n"), 0o600); err != nil {
+		return nil, "", "", fmt.Errorf("failed to write tree digest in %s: %w", cacheDir, err)
+	}
+	if err := writeTemplateCacheManifest(cacheDir, templateCacheManifest{
+		URL:        templateUrl,
+		Ref:        templateRef,
+		CommitSHA:  commitSHA,
+		TreeDigest: digest,
+		FetchedAt:  time.Now(),
+	}); err != nil {
+		return nil, "", "", err
+	}
+
+	return os.DirFS(cacheDir), ".", "", nil
+}
+
+func shortCommitSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+// resolveRemoteCommitSHA runs `git ls-remote` to resolve templateRef (a
+// branch or tag name, or HEAD's default if empty) to a commit SHA without
+// cloning. If it comes back empty, templateRef is assumed to already be a
+// commit SHA, which ls-remote can't resolve on its own.
+func resolveRemoteCommitSHA(templateUrl, templateRef string) (string, error) {
+	refArg := "HEAD"
+	if templateRef != "" {
+		refArg = templateRef
+	}
+	// #nosec G204 -- URL/ref are from user flags, command is allow-listed
+	cmd := createCommand("git", "ls-remote", templateUrl, refArg)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s#%s: %w", templateUrl, refArg, err)
+	}
+	line := strings.TrimSpace(string(out))
+	if line == "" {
+		if templateRef != "" {
+			return templateRef, nil
+		}
+		return "", fmt.Errorf("no ref matching %q found at %s", refArg, templateUrl)
+	}
+	fields := strings.Fields(line)
+	return fields[0], nil
+}
+
+// cloneTemplateURL clones templateUrl into dir, checking out templateRef
+// (a branch or tag) if given.
+func cloneTemplateURL(templateUrl, templateRef, dir string) error {
+	args := []string{"clone", "--depth=1"}
+	if templateRef != "" {
+		args = append(args, "--branch", templateRef)
+	}
+	args = append(args, templateUrl, dir)
+
+	// #nosec G204 -- URL/ref are from user flags, dir is generated, command is allow-listed
+	cmd := createCommand("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to clone template repository %s: %w", templateUrl, err)
+	}
+	return nil
+}
+
+// cloneTemplateURLAtCommit inits a fresh repo in dir and fetches exactly
+// commitSHA from templateUrl at depth 1, then checks it out -- the
+// shallow-clone equivalent of cloneTemplateURL that also works for a commit
+// SHA resolved ahead of time, not just a branch or tag name.
+func cloneTemplateURLAtCommit(templateUrl, commitSHA, dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	// #nosec G204 -- dir is derived from a hash, command is allow-listed
+	if err := createCommand("git", "-C", dir, "init").Run(); err != nil {
+		return fmt.Errorf("failed to init %s: %w", dir, err)
+	}
+	// #nosec G204 -- URL is from a user flag, dir is derived from a hash, command is allow-listed
+	if err := createCommand("git", "-C", dir, "remote", "add", "origin", templateUrl).Run(); err != nil {
+		return fmt.Errorf("failed to add remote %s in %s: %w", templateUrl, dir, err)
+	}
+	return fetchAndCheckout(dir, commitSHA)
+}
+
+// cloneTemplateURLToTempDir is the --no-cache path: a fresh, uncached clone
+// into a temp dir the caller is responsible for removing.
+func cloneTemplateURLToTempDir(templateUrl, templateRef string) (fs.FS, string, string, error) {
+	tempDir, err := os.MkdirTemp("", "porter-template-*")
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	fmt.Printf("Fetching template from %s...\n", templateUrl)
+	if err := cloneTemplateURL(templateUrl, templateRef, tempDir); err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, "", "", err
+	}
+	fmt.Println("Using cloned template repository.")
+	return os.DirFS(tempDir), ".", tempDir, nil
+}
+
+// fetchAndCheckout fetches ref (a branch, tag, or commit SHA) from dir's
+// origin at depth 1 and checks it out.
+func fetchAndCheckout(dir, ref string) error {
+	fetchArgs := []string{"-C", dir, "fetch", "--depth=1", "origin", ref}
+	// #nosec G204 -- dir is derived from a hash, ref is a user flag or resolved commit SHA, command is allow-listed
+	fetch := createCommand("git", fetchArgs...)
+	fetch.Stdout = os.Stdout
+	fetch.Stderr = os.Stderr
+	if err := fetch.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s in %s: %w", ref, dir, err)
+	}
+
+	// #nosec G204 -- dir is derived from a hash, command is allow-listed
+	checkout := createCommand("git", "-C", dir, "checkout", "FETCH_HEAD")
+	checkout.Stdout = os.Stdout
+	checkout.Stderr = os.Stderr
+	if err := checkout.Run(); err != nil {
+		return fmt.Errorf("failed to check out FETCH_HEAD in %s: %w", dir, err)
+	}
+	return nil
+}
+
+// listTemplateURLCacheEntries returns every --template-url cache entry's
+// manifest for `skeletor cache list`, sorted by cache key for stable output.
+func listTemplateURLCacheEntries() ([]templateCacheManifest, error) {
+	cacheRoot, err := templateURLCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template cache directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	manifests := make([]templateCacheManifest, 0, len(names))
+	for _, name := range names {
+		manifest, err := readTemplateCacheManifest(filepath.Join(cacheRoot, name))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// pruneTemplateURLCache removes every entry in the --template-url cache.
+func pruneTemplateURLCache() error {
+	cacheRoot, err := templateURLCacheDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read template cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(cacheRoot, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove cached template %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTemplateURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/foo", normalizeTemplateURL(" https://example.com/foo "))
+	assert.Equal(t, "https://example.com/foo", normalizeTemplateURL("https://example.com/foo/"))
+	assert.Equal(t, "https://example.com/foo", normalizeTemplateURL("https://example.com/foo.git"))
+	assert.Equal(t, "https://example.com/foo", normalizeTemplateURL("https://example.com/foo.git/"))
+}
+
+func TestTemplateCacheKey_DeterministicPerURLAndCommit(t *testing.T) {
+	a := templateCacheKey("https://example.com/foo.git", "deadbeef")
+	b := templateCacheKey("https://example.com/foo", "deadbeef") // .git-stripped form is the same entry
+	c := templateCacheKey("https://example.com/foo.git", "cafef00d")
+	d := templateCacheKey("https://example.com/foo.git", "")
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.NotEqual(t, a, d)
+}
+
+func TestTemplateURLCacheDir_UnderHomeSkeletorCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := templateURLCacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".skeletor", "cache", "templates"), dir)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestWriteAndReadTemplateCacheManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := templateCacheManifest{
+		URL:        "https://example.com/foo.git",
+		Ref:        "v1.0",
+		CommitSHA:  "deadbeef",
+		TreeDigest: "sha256:abc123",
+		FetchedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	require.NoError(t, writeTemplateCacheManifest(dir, manifest))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "deadbeef")
+	assert.Contains(t, string(raw), "v1.0")
+	assert.Contains(t, string(raw), "sha256:abc123")
+
+	read, err := readTemplateCacheManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.CommitSHA, read.CommitSHA)
+	assert.Equal(t, manifest.TreeDigest, read.TreeDigest)
+}
+
+func TestShortCommitSHA(t *testing.T) {
+	assert.Equal(t, "deadbeef", shortCommitSHA("deadbeef"))
+	assert.Equal(t, "deadbeefcafe", shortCommitSHA("deadbeefcafe1234567890"))
+}
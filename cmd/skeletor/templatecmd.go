@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// buildTemplateCommand returns the `skeletor template` command group, the
+// publishing counterpart to --template-url/--template-source's OCI pull
+// support (see ocitemplate.go and templatesource.go's ociTemplateSource):
+// `skeletor template push` gives a team a signed, versioned distribution
+// channel for an internal template without needing a git server.
+func buildTemplateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Publish templates as OCI artifacts",
+	}
+	cmd.AddCommand(buildTemplatePushCommand())
+	cmd.AddCommand(buildTemplateLockCommand())
+	return cmd
+}
+
+func buildTemplatePushCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "push <dir> <ref>",
+		Short: "Push a template directory as an OCI artifact, e.g. ghcr.io/foo/template:v1.2.0",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, ref := args[0], args[1]
+
+			fileInfo, err := os.Stat(dir)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("template directory does not exist: %s", dir)
+				}
+				return fmt.Errorf("failed to stat template directory %s: %w", dir, err)
+			}
+			if !fileInfo.IsDir() {
+				return fmt.Errorf("template path is not a directory: %s", dir)
+			}
+
+			if err := pushOCITemplate(dir, ref); err != nil {
+				return err
+			}
+			fmt.Printf("Pushed %s to %s\n", dir, ref)
+			return nil
+		},
+	}
+}
+
+// buildTemplateLockCommand resolves a template the same way `create` does
+// and pins its origin, resolved digest, and effective variable values to a
+// skeletor.lock.yaml -- a floating --template-url ref (a branch, say) can
+// move on between `lock` and a later `create --from-lock`, but the lock
+// file's recorded checksum catches that drift instead of silently
+// regenerating from different content.
+func buildTemplateLockCommand() *cobra.Command {
+	var (
+		name            string
+		author          string
+		modulePath      string
+		kindRaw         string
+		templateUrl     string
+		templateRef     string
+		templateDir     string
+		templateSource  string
+		complianceLevel string
+		extraVars       []string
+		valuesFile      string
+		noCache         bool
+		refreshCache    bool
+		verifySig       bool
+		cosignKey       string
+		output          string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lock [template-name]",
+		Short: "Pin a template's origin, digest, and variables to a skeletor.lock.yaml",
+		Long:  "Resolve a template exactly like `create` does and write a skeletor.lock.yaml pinning its origin, resolved content digest, and effective variable values, so a later `skeletor create --from-lock skeletor.lock.yaml` reproduces byte-identical output.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var templateName string
+			if len(args) > 0 {
+				templateName = args[0]
+			}
+
+			kind, err := parseTemplateKind(kindRaw)
+			if err != nil {
+				return err
+			}
+
+			verifySig = verifySig || cosignKey != ""
+			tmplFS, rootDirForWalk, cleanupDir, err := getTemplateSource(templateSource, templateUrl, templateDir, templateRef, templateName, kind, noCache, refreshCache, verifySig, cosignKey)
+			if err != nil {
+				return err
+			}
+			if cleanupDir != "" {
+				defer os.RemoveAll(cleanupDir)
+			}
+
+			templateDigest, err := computeTreeDigest(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to compute template tree digest: %w", err)
+			}
+
+			config, err := LoadTemplateConfig(tmplFS, rootDirForWalk)
+			if err != nil {
+				return fmt.Errorf("failed to load template config from %s: %w", rootDirForWalk, err)
+			}
+
+			var values map[string]interface{}
+			if valuesFile != "" {
+				values, err = loadValuesFile(valuesFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			data, err := buildTemplateData(config, name, author, modulePath, ".", complianceLevel, true, extraVars, values)
+			if err != nil {
+				return err
+			}
+
+			lock := templateLock{
+				TemplateURL:      templateUrl,
+				TemplateSource:   templateSource,
+				TemplateDir:      templateDir,
+				TemplateRef:      templateRef,
+				TemplateChecksum: templateDigest,
+				ComplianceLevel:  complianceLevel,
+				Variables:        provenanceVariables(data),
+			}
+			if err := writeTemplateLock(output, lock); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote template lock to %s (template checksum %s)\n", output, templateDigest)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the mixin to pin variable values for")
+	cmd.Flags().StringVar(&author, "author", "", "Author name to pin")
+	cmd.Flags().StringVar(&modulePath, "module", "", "Go module path to pin")
+	cmd.Flags().StringVar(&kindRaw, "kind", string(TemplateKindMixin), "Built-in template tree kind: mixin or plugin; ignored when --template-dir/--template-url/--template-source picks an external source instead")
+	cmd.Flags().StringVar(&templateUrl, "template-url", "", "URL to a git repository (or bare/oci:// OCI ref) containing the template, e.g. https://github.com/foo/bar.git or oci://ghcr.io/foo/template:v1.2.0")
+	cmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch, tag, or commit to resolve --template-url against")
+	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Local directory containing the template")
+	cmd.Flags().StringVar(&templateSource, "template-source", "", "Template source URI, e.g. oci://ghcr.io/foo/template:v1.2.0, git://github.com/foo/bar.git#v1.0; takes priority over --template-dir/--template-url")
+	cmd.Flags().StringVar(&complianceLevel, "compliance-level", "basic", "Compliance level (basic, slsa-l1, slsa-l3)")
+	cmd.Flags().StringArrayVar(&extraVars, "var", []string{}, "Extra variables in KEY=VALUE format to pin")
+	cmd.Flags().StringVar(&valuesFile, "values-file", "", "YAML or JSON file of variable values to pin")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-fetch --template-url instead of reusing the persistent template cache")
+	cmd.Flags().BoolVar(&refreshCache, "refresh", false, "Bypass a cached --template-url entry and re-fetch it even if it's already cached")
+	cmd.Flags().BoolVar(&verifySig, "verify-signature", false, "Require a valid cosign signature before using an oci:// --template-url")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "Public key file cosign should verify against; implies --verify-signature")
+	cmd.Flags().StringVar(&output, "output", "skeletor.lock.yaml", "Path to write the lock file to")
+
+	return cmd
+}
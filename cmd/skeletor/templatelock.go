@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateLockFileVersion versions skeletor.lock.yaml's shape, so a future
+// incompatible change to the format can be detected before --from-lock
+// silently misreads an old lock file.
+const templateLockFileVersion = 1
+
+// templateLock pins everything `skeletor create --from-lock` needs to
+// reproduce byte-identical output: whichever of --template-url/
+// --template-source/--template-dir selected the template, the ref it was
+// resolved against, the tree digest that resolution produced, and the
+// effective variable values generation was run with. Written by `skeletor
+// template lock` (see templatecmd.go) and read back by create's --from-lock.
+type templateLock struct {
+	Version          int                    `yaml:"version"`
+	TemplateURL      string                 `yaml:"templateUrl,omitempty"`
+	TemplateSource   string                 `yaml:"templateSource,omitempty"`
+	TemplateDir      string                 `yaml:"templateDir,omitempty"`
+	TemplateRef      string                 `yaml:"templateRef,omitempty"`
+	TemplateChecksum string                 `yaml:"templateChecksum"`
+	ComplianceLevel  string                 `yaml:"complianceLevel,omitempty"`
+	Variables        map[string]interface{} `yaml:"variables,omitempty"`
+}
+
+// writeTemplateLock marshals lock as YAML to path, stamping the current
+// templateLockFileVersion.
+func writeTemplateLock(path string, lock templateLock) error {
+	lock.Version = templateLockFileVersion
+	encoded, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template lock: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// readTemplateLock reads and parses a skeletor.lock.yaml written by
+// writeTemplateLock.
+func readTemplateLock(path string) (templateLock, error) {
+	var lock templateLock
+	raw, err := os.ReadFile(path) // #nosec G304 -- path comes from a user-supplied --from-lock/--output flag
+	if err != nil {
+		return lock, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(raw, &lock); err != nil {
+		return lock, fmt.Errorf("failed to parse %s as a skeletor lock file: %w", path, err)
+	}
+	if lock.Version != templateLockFileVersion {
+		return lock, fmt.Errorf("%s is lock format version %d, but this skeletor build only understands version %d", path, lock.Version, templateLockFileVersion)
+	}
+	return lock, nil
+}
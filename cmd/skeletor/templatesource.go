@@ -0,0 +1,483 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TemplateSourceOptions carries scheme-specific, optional resolution
+// behavior that doesn't belong in ref itself -- today just the
+// --verify-signature/--cosign-key pair ociTemplateSource honors the same
+// way resolveOCITemplateURL already does for --template-url; every other
+// scheme ignores it. The zero value requests no signature verification.
+type TemplateSourceOptions struct {
+	VerifySignature bool
+	CosignKey       string
+}
+
+// TemplateSource resolves a scheme-specific ref (a git URL, an OCI
+// reference, a tarball URL, ...) to a filesystem a template can be read
+// from, mirroring Terraform's backend init pattern: a small interface, a
+// name-keyed registry, and a factory per scheme.
+type TemplateSource interface {
+	// Resolve returns the filesystem rooted at root within fsys that holds
+	// the template tree, plus a cleanup func to call once done (nil if
+	// nothing needs removing, e.g. a cached or embedded source).
+	Resolve(ref string, opts TemplateSourceOptions) (fsys fs.FS, root string, cleanup func() error, err error)
+}
+
+// TemplateSourceFactory constructs a fresh TemplateSource for one scheme.
+type TemplateSourceFactory func() TemplateSource
+
+var templateSourceRegistry = map[string]TemplateSourceFactory{}
+
+// RegisterTemplateSource adds factory to the registry under scheme, e.g.
+// RegisterTemplateSource("oci", func() TemplateSource { return &ociTemplateSource{} }).
+func RegisterTemplateSource(scheme string, factory TemplateSourceFactory) {
+	templateSourceRegistry[scheme] = factory
+}
+
+func init() {
+	RegisterTemplateSource("embed", func() TemplateSource { return embedTemplateSource{} })
+	RegisterTemplateSource("dir", func() TemplateSource { return dirTemplateSource{} })
+	RegisterTemplateSource("git", func() TemplateSource { return gitTemplateSource{} })
+	RegisterTemplateSource("oci", func() TemplateSource { return ociTemplateSource{} })
+	RegisterTemplateSource("http", func() TemplateSource { return httpTemplateSource{} })
+	RegisterTemplateSource("https", func() TemplateSource { return httpTemplateSource{} })
+}
+
+// ResolveTemplateSource parses a "scheme://ref" string (e.g.
+// "oci://ghcr.io/foo/template:v1.2.0", "git://github.com/foo/bar.git#v1.0",
+// "dir:///local/path") and resolves it via the registered TemplateSource
+// for scheme.
+func ResolveTemplateSource(sourceURI string) (fs.FS, string, func() error, error) {
+	return ResolveTemplateSourceWithOptions(sourceURI, TemplateSourceOptions{})
+}
+
+// ResolveTemplateSourceWithOptions is ResolveTemplateSource plus opts, for
+// callers (create, template lock) that have a --verify-signature/
+// --cosign-key pair to pass through to a scheme that honors it.
+func ResolveTemplateSourceWithOptions(sourceURI string, opts TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	scheme, ref, found := strings.Cut(sourceURI, "://")
+	if !found {
+		return nil, "", nil, fmt.Errorf("invalid --template-source %q: expected scheme://ref", sourceURI)
+	}
+
+	factory, ok := templateSourceRegistry[scheme]
+	if !ok {
+		return nil, "", nil, fmt.Errorf("unknown template source scheme %q", scheme)
+	}
+	return factory().Resolve(ref, opts)
+}
+
+// skeletorSourcesCacheDir returns ~/.skeletor/sources, creating it if
+// necessary.
+func skeletorSourcesCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory for source cache: %w", err)
+	}
+	dir := filepath.Join(home, ".skeletor", "sources")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create source cache directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// sourceCacheKey hashes a scheme+ref pair to the cache directory name it
+// resolves to, so repeated --template-source=<same ref> invocations reuse
+// the same clone/pull/download instead of re-fetching.
+func sourceCacheKey(scheme, ref string) string {
+	sum := sha256.Sum256([]byte(scheme + "://" + ref))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// sourceManifest records what a cache entry resolved, for `skeletor sources
+// list/verify`.
+type sourceManifest struct {
+	Scheme     string    `json:"scheme"`
+	Ref        string    `json:"ref"`
+	ResolvedAt time.Time `json:"resolved_at"`
+	Checksum   string    `json:"checksum,omitempty"`
+	// ExtractedChecksum is a sha256 over the extracted/ tree's relative
+	// paths and contents, recorded once at extraction time so `skeletor
+	// sources verify` can recompute it later and detect on-disk drift
+	// (tampering, partial writes, manual edits) -- Checksum alone can't,
+	// since it's the checksum of the downloaded tarball, not the tree.
+	ExtractedChecksum string `json:"extracted_checksum,omitempty"`
+}
+
+func writeSourceManifest(cacheDir string, manifest sourceManifest) error {
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal source manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(cacheDir, "skeletor-source.json"), encoded, 0o600)
+}
+
+func readSourceManifest(cacheDir string) (sourceManifest, error) {
+	var manifest sourceManifest
+	raw, err := os.ReadFile(filepath.Join(cacheDir, "skeletor-source.json"))
+	if err != nil {
+		return manifest, err
+	}
+	err = json.Unmarshal(raw, &manifest)
+	return manifest, err
+}
+
+// embedTemplateSource serves one of the built-in template trees -- the
+// default when no --template-source/--template-url/--template-dir flag is
+// given. Only ref == "plugin" switches to pkg.PluginTemplateFS
+// (--kind=plugin's tree); any other ref (including the historical "default",
+// kept for backward compatibility) serves pkg.MixinTemplateFS, same as
+// before --kind existed. Use --template-source=embed://plugin to pick the
+// plugin tree explicitly.
+type embedTemplateSource struct{}
+
+func (embedTemplateSource) Resolve(ref string, _ TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	kind := TemplateKindMixin
+	if ref == string(TemplateKindPlugin) {
+		kind = TemplateKindPlugin
+	}
+	return builtinTemplateFS(kind), builtinTemplateBase(kind), nil, nil
+}
+
+// dirTemplateSource reads a template tree straight from a local directory,
+// e.g. --template-source=dir:///home/me/my-template.
+type dirTemplateSource struct{}
+
+func (dirTemplateSource) Resolve(ref string, _ TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	path := ref
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("template directory does not exist: %s", path)
+	}
+	if !info.IsDir() {
+		return nil, "", nil, fmt.Errorf("template path is not a directory: %s", path)
+	}
+	return os.DirFS(path), ".", nil, nil
+}
+
+// gitTemplateSource clones ref (optionally "repo-url#rev" to pin a tag,
+// branch or commit) into the cache, reusing an existing clone for the same
+// repo#rev instead of re-cloning.
+type gitTemplateSource struct{}
+
+func (gitTemplateSource) Resolve(ref string, _ TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	repoURL, rev, _ := strings.Cut(ref, "#")
+
+	cacheRoot, err := skeletorSourcesCacheDir()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	cacheDir := filepath.Join(cacheRoot, sourceCacheKey("git", ref))
+
+	if _, err := os.Stat(filepath.Join(cacheDir, ".git")); err == nil {
+		return os.DirFS(cacheDir), ".", nil, nil // Already cloned for this exact repo#rev
+	}
+
+	fmt.Printf("Cloning %s into source cache...\n", repoURL)
+	cloneArgs := []string{"clone", "--depth=1"}
+	if rev != "" {
+		cloneArgs = append(cloneArgs, "--branch", rev)
+	}
+	cloneArgs = append(cloneArgs, repoURL, cacheDir)
+
+	// #nosec G204 -- repoURL/rev come from a trusted --template-source flag, command is allow-listed
+	cmd := createCommand("git", cloneArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.RemoveAll(cacheDir)
+		return nil, "", nil, fmt.Errorf("failed to clone %s: %w", repoURL, err)
+	}
+
+	if err := writeSourceManifest(cacheDir, sourceManifest{Scheme: "git", Ref: ref, ResolvedAt: time.Now()}); err != nil {
+		return nil, "", nil, err
+	}
+	return os.DirFS(cacheDir), ".", nil, nil
+}
+
+// ociTemplateSource pulls ref (e.g. "ghcr.io/foo/porter-mixin-template:v1.2.0")
+// as an OCI artifact via the oras CLI, matching Porter's own OCI-native
+// distribution model.
+type ociTemplateSource struct{}
+
+func (ociTemplateSource) Resolve(ref string, opts TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	cacheRoot, err := skeletorSourcesCacheDir()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	cacheDir := filepath.Join(cacheRoot, sourceCacheKey("oci", ref))
+
+	if _, err := os.Stat(filepath.Join(cacheDir, "skeletor-source.json")); err == nil {
+		return os.DirFS(cacheDir), ".", nil, nil // Already pulled
+	}
+
+	// Verify before pulling, the same order resolveOCITemplateURL already
+	// uses for --template-url oci://: a cache hit above skips this, same as
+	// it skips the pull itself, so a verified artifact isn't re-verified on
+	// every run.
+	if opts.VerifySignature {
+		if err := verifyOCITemplateSignature(ref, opts.CosignKey); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o750); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create OCI source cache directory: %w", err)
+	}
+
+	fmt.Printf("Pulling OCI template artifact %s...\n", ref)
+	// #nosec G204 -- ref comes from a trusted --template-source flag, "oras" is the fixed executable
+	cmd := createCommand("oras", "pull", ref, "-o", cacheDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to pull OCI template artifact %s (requires the oras CLI): %w", ref, err)
+	}
+
+	if err := writeSourceManifest(cacheDir, sourceManifest{Scheme: "oci", Ref: ref, ResolvedAt: time.Now()}); err != nil {
+		return nil, "", nil, err
+	}
+	return os.DirFS(cacheDir), ".", nil, nil
+}
+
+// httpTemplateSource downloads a tarball ("url#sha256:<hex>" to pin a
+// checksum) and extracts it into the cache.
+type httpTemplateSource struct{}
+
+func (httpTemplateSource) Resolve(ref string, _ TemplateSourceOptions) (fs.FS, string, func() error, error) {
+	url, checksumPin, _ := strings.Cut(ref, "#")
+
+	cacheRoot, err := skeletorSourcesCacheDir()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	cacheDir := filepath.Join(cacheRoot, sourceCacheKey("http", ref))
+	extractedDir := filepath.Join(cacheDir, "extracted")
+
+	if _, err := os.Stat(extractedDir); err == nil {
+		return os.DirFS(extractedDir), ".", nil, nil // Already downloaded+extracted
+	}
+
+	fmt.Printf("Downloading template tarball %s...\n", url)
+	resp, err := http.Get(url) // #nosec G107 -- url comes from a trusted --template-source flag
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", nil, fmt.Errorf("failed to download %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	sum := sha256.Sum256(body)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	if checksumPin != "" && checksumPin != checksum {
+		return nil, "", nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", url, checksumPin, checksum)
+	}
+
+	if err := os.MkdirAll(extractedDir, 0o750); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create extraction directory: %w", err)
+	}
+	if err := extractTarGz(body, extractedDir); err != nil {
+		_ = os.RemoveAll(cacheDir)
+		return nil, "", nil, err
+	}
+
+	extractedChecksum, err := hashExtractedTree(extractedDir)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if err := writeSourceManifest(cacheDir, sourceManifest{Scheme: "http", Ref: ref, ResolvedAt: time.Now(), Checksum: checksum, ExtractedChecksum: extractedChecksum}); err != nil {
+		return nil, "", nil, err
+	}
+	return os.DirFS(extractedDir), ".", nil, nil
+}
+
+// hashExtractedTree computes a single sha256 over every regular file under
+// dir, in sorted relative-path order, folding in each file's path so a
+// rename and a content-swap both change the digest. This is what `skeletor
+// sources verify` recomputes to detect drift in an http-sourced cache
+// entry's extracted/ directory.
+func hashExtractedTree(dir string) (string, error) {
+	var paths []string
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", fmt.Errorf("failed to relativize %s: %w", path, err)
+		}
+		content, err := os.ReadFile(path) // #nosec G304 -- path comes from walking our own cache directory
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		h.Write(content)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractTarGz extracts a gzip-compressed tar archive's contents into dir.
+func extractTarGz(archive []byte, dir string) error {
+	gzr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return fmt.Errorf("failed to open tarball: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tarball entry: %w", err)
+		}
+
+		// #nosec G305 -- path traversal guarded below via filepath.Clean + prefix check
+		destPath := filepath.Join(dir, filepath.Clean(header.Name))
+		if !strings.HasPrefix(destPath, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("tarball entry %q escapes extraction directory", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(destPath, 0o750); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", destPath, err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(destPath), 0o750); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", destPath, err)
+			}
+			out, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304 -- destPath is validated above
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", destPath, err)
+			}
+			// #nosec G110 -- template tarballs are a trusted, user-chosen --template-source, not arbitrary untrusted input
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return fmt.Errorf("failed to write %s: %w", destPath, err)
+			}
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("failed to finalize %s: %w", destPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// listCachedSources returns every cache entry's manifest for `skeletor
+// sources list`, sorted by cache key for stable output.
+func listCachedSources() ([]sourceManifest, error) {
+	cacheRoot, err := skeletorSourcesCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source cache directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	manifests := make([]sourceManifest, 0, len(names))
+	for _, name := range names {
+		manifest, err := readSourceManifest(filepath.Join(cacheRoot, name))
+		if err != nil {
+			continue // An entry whose fetch failed before its manifest was written (e.g. a failed oras pull) has none; skip unreadable ones
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// pruneCachedSources removes every entry in the source cache.
+func pruneCachedSources() error {
+	cacheRoot, err := skeletorSourcesCacheDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read source cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(cacheRoot, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove cached source %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// verifyCachedSource recomputes an http-sourced cache entry's extracted/
+// tree checksum and confirms it still matches what was recorded at
+// extraction time; git/oci entries are verified by presence only, since
+// re-deriving their content hash would mean re-cloning/re-pulling.
+func verifyCachedSource(cacheDir string) error {
+	manifest, err := readSourceManifest(cacheDir)
+	if err != nil {
+		return fmt.Errorf("no manifest found in %s: %w", cacheDir, err)
+	}
+	if manifest.Scheme != "http" || manifest.Checksum == "" {
+		return nil
+	}
+
+	extractedDir := filepath.Join(cacheDir, "extracted")
+	if _, err := os.Stat(extractedDir); err != nil {
+		return fmt.Errorf("cached source %s is missing its extracted tree: %w", cacheDir, err)
+	}
+
+	// Cache entries written before ExtractedChecksum existed have nothing
+	// to compare against; fall back to the presence check they got before.
+	if manifest.ExtractedChecksum == "" {
+		return nil
+	}
+
+	got, err := hashExtractedTree(extractedDir)
+	if err != nil {
+		return fmt.Errorf("failed to recompute checksum for %s: %w", cacheDir, err)
+	}
+	if got != manifest.ExtractedChecksum {
+		return fmt.Errorf("cached source %s failed checksum verification: expected %s, got %s", cacheDir, manifest.ExtractedChecksum, got)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTemplateSource_InvalidURI(t *testing.T) {
+	_, _, _, err := ResolveTemplateSource("no-scheme-separator")
+	assert.Error(t, err)
+}
+
+// TestResolveTemplateSourceWithOptions_OCI_VerifySignatureInvoked confirms
+// --template-source oci://... actually runs cosign when VerifySignature is
+// set, instead of silently skipping straight to the oras pull the way it
+// used to -- the error coming back mentioning cosign (rather than oras, or
+// succeeding) is the evidence verification was attempted.
+func TestResolveTemplateSourceWithOptions_OCI_VerifySignatureInvoked(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, _, _, err := ResolveTemplateSourceWithOptions("oci://example.com/template:v1", TemplateSourceOptions{VerifySignature: true})
+	assert.ErrorContains(t, err, "cosign")
+}
+
+func TestResolveTemplateSource_UnknownScheme(t *testing.T) {
+	_, _, _, err := ResolveTemplateSource("ftp://example.com/template")
+	assert.Error(t, err)
+}
+
+func TestResolveTemplateSource_Embed(t *testing.T) {
+	fsys, root, cleanup, err := ResolveTemplateSource("embed://default")
+	require.NoError(t, err)
+	assert.Nil(t, cleanup)
+	assert.Equal(t, "template", root)
+	_, err = fsys.Open(root)
+	assert.NoError(t, err)
+}
+
+func TestResolveTemplateSource_EmbedPlugin(t *testing.T) {
+	fsys, root, cleanup, err := ResolveTemplateSource("embed://plugin")
+	require.NoError(t, err)
+	assert.Nil(t, cleanup)
+	assert.Equal(t, "template-plugin", root)
+	_, err = fsys.Open(root)
+	assert.NoError(t, err)
+}
+
+func TestResolveTemplateSource_Dir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "template.json"), []byte("{}"), 0o600))
+
+	fsys, root, cleanup, err := ResolveTemplateSource("dir://" + dir)
+	require.NoError(t, err)
+	assert.Nil(t, cleanup)
+	assert.Equal(t, ".", root)
+	_, err = fsys.Open("template.json")
+	assert.NoError(t, err)
+}
+
+func TestResolveTemplateSource_Dir_MissingPath(t *testing.T) {
+	_, _, _, err := ResolveTemplateSource("dir://" + filepath.Join(t.TempDir(), "does-not-exist"))
+	assert.Error(t, err)
+}
+
+func TestSourceCacheKey_DeterministicPerSchemeAndRef(t *testing.T) {
+	a := sourceCacheKey("git", "https://example.com/foo.git#v1.0")
+	b := sourceCacheKey("git", "https://example.com/foo.git#v1.0")
+	c := sourceCacheKey("git", "https://example.com/foo.git#v2.0")
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func buildTestTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractTarGz_RoundTrip(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}", "nested/file.txt": "hello"})
+	dir := t.TempDir()
+
+	require.NoError(t, extractTarGz(archive, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "template.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+
+	content, err = os.ReadFile(filepath.Join(dir, "nested", "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+
+func TestExtractTarGz_RejectsPathTraversal(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"../escape.txt": "nope"})
+	dir := t.TempDir()
+
+	err := extractTarGz(archive, dir)
+	assert.Error(t, err)
+}
+
+func TestHttpTemplateSource_ChecksumMismatchRejected(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	_, _, _, err := ResolveTemplateSource("http://" + server.Listener.Addr().String() + "/template.tar.gz#sha256:deadbeef")
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestHttpTemplateSource_DownloadsAndExtracts(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	fsys, root, cleanup, err := ResolveTemplateSource("http://" + server.Listener.Addr().String() + "/template.tar.gz")
+	require.NoError(t, err)
+	assert.Nil(t, cleanup)
+	assert.Equal(t, ".", root)
+	_, err = fsys.Open("template.json")
+	assert.NoError(t, err)
+}
+
+func TestListAndPruneCachedSources(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	_, _, _, err := ResolveTemplateSource("http://" + server.Listener.Addr().String() + "/template.tar.gz")
+	require.NoError(t, err)
+
+	manifests, err := listCachedSources()
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, "http", manifests[0].Scheme)
+
+	require.NoError(t, pruneCachedSources())
+	manifests, err = listCachedSources()
+	require.NoError(t, err)
+	assert.Empty(t, manifests)
+}
+
+func TestVerifyCachedSource_PassesUntampered(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	_, _, _, err := ResolveTemplateSource("http://" + server.Listener.Addr().String() + "/template.tar.gz")
+	require.NoError(t, err)
+
+	cacheRoot, err := skeletorSourcesCacheDir()
+	require.NoError(t, err)
+	entries, err := os.ReadDir(cacheRoot)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	assert.NoError(t, verifyCachedSource(filepath.Join(cacheRoot, entries[0].Name())))
+}
+
+func TestVerifyCachedSource_DetectsTamperedContent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	archive := buildTestTarGz(t, map[string]string{"template.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	_, _, _, err := ResolveTemplateSource("http://" + server.Listener.Addr().String() + "/template.tar.gz")
+	require.NoError(t, err)
+
+	cacheRoot, err := skeletorSourcesCacheDir()
+	require.NoError(t, err)
+	entries, err := os.ReadDir(cacheRoot)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	cacheDir := filepath.Join(cacheRoot, entries[0].Name())
+
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "extracted", "template.json"), []byte(`{"tampered": true}`), 0o600))
+
+	err = verifyCachedSource(cacheDir)
+	assert.ErrorContains(t, err, "failed checksum verification")
+}
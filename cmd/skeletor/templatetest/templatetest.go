@@ -0,0 +1,289 @@
+// Package templatetest is a small, dependency-light harness that lets a
+// Porter template author unit-test their own template.json + *.tmpl files
+// without touching the filesystem. It mirrors the destination-path,
+// conditional-path and content-templating semantics of cmd/skeletor's
+// Engine.CreateMixin for the common case (legacy template-string
+// conditional paths, filename/content templating, Go-file package/import
+// replacements, post_gen hook command rendering) -- Go cannot import a
+// "package main", so this is a small standalone reimplementation kept in
+// sync with CreateMixin by hand, not a wrapper around it. It is modeled on
+// golang.org/x/tools/go/buildutil.FakeContext.
+package templatetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// FakeTemplate is an in-memory template source: a map of relative file path
+// (the same paths a real template directory or embed.FS would have) to file
+// content, with a "template.json" entry providing the usual configuration.
+type FakeTemplate struct {
+	files map[string]string
+}
+
+// NewFakeTemplate wraps files as a fake template source for Generate.
+func NewFakeTemplate(files map[string]string) *FakeTemplate {
+	return &FakeTemplate{files: files}
+}
+
+// GeneratedResult is the outcome of a FakeTemplate.Generate call: every
+// generated file's content, kept in memory, plus a dry-run-style log of
+// what was done and the post_gen hook commands that would run.
+type GeneratedResult struct {
+	files        map[string]string
+	order        []string
+	dryRunLog    []string
+	hookCommands [][]string
+}
+
+// File returns the generated content at path (the destination path, after
+// templating and ".tmpl" stripping), or "" if nothing was generated there.
+func (r *GeneratedResult) File(path string) string {
+	return r.files[path]
+}
+
+// Files returns every generated destination path, sorted.
+func (r *GeneratedResult) Files() []string {
+	paths := make([]string, len(r.order))
+	copy(paths, r.order)
+	sort.Strings(paths)
+	return paths
+}
+
+// DryRunLog returns the newline-joined log of what Generate did, in the
+// same "[Dry Run] ..." message style Engine.CreateMixin prints with
+// --dry-run. It's produced unconditionally: unlike CreateMixin, Generate's
+// writes are all in-memory, so there's no I/O cost to logging them.
+func (r *GeneratedResult) DryRunLog() string {
+	return strings.Join(r.dryRunLog, "\n")
+}
+
+// HookCommands returns every post_gen-style hook command, templated and
+// split into argv form, across every hook stage (sorted by stage name,
+// then declaration order within the stage). Commands are never executed.
+func (r *GeneratedResult) HookCommands() [][]string {
+	return r.hookCommands
+}
+
+// fakeVariable is the subset of config.go's Variable this harness
+// understands: enough to fill in defaults and enforce required-ness.
+type fakeVariable struct {
+	Default  interface{} `json:"default"`
+	Required bool        `json:"required"`
+}
+
+// fakeConditionalPath mirrors config.go's ConditionalPath: either a bare
+// legacy template string, or the structured {"when": "..."} form. The
+// structured form isn't supported here since evaluating it needs the
+// feature-toggle subsystem; Generate fails the test if it's used.
+type fakeConditionalPath struct {
+	Template string
+	When     string
+}
+
+func (c *fakeConditionalPath) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		c.Template = legacy
+		return nil
+	}
+	var structured struct {
+		When string `json:"when"`
+	}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return fmt.Errorf("conditional path must be a template string or an object with a \"when\" field: %w", err)
+	}
+	c.When = structured.When
+	return nil
+}
+
+// fakeHookStep mirrors config.go's HookStep, keeping only the Command
+// field since HookCommands never executes a hook.
+type fakeHookStep struct {
+	Command string
+}
+
+func (s *fakeHookStep) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		s.Command = command
+		return nil
+	}
+	var alias struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("hook step must be a command string or an object with a \"command\" field: %w", err)
+	}
+	s.Command = alias.Command
+	return nil
+}
+
+// fakeConfig is the subset of config.go's TemplateConfig this harness reads
+// out of template.json.
+type fakeConfig struct {
+	Variables        map[string]fakeVariable        `json:"variables"`
+	Ignore           []string                       `json:"ignore"`
+	ConditionalPaths map[string]fakeConditionalPath `json:"conditional_paths"`
+	Hooks            map[string][]fakeHookStep      `json:"hooks"`
+}
+
+// Generate renders f's files against vars, the way Engine.CreateMixin
+// would with vars merged over template.json's variable defaults. It fails
+// t (via t.Fatalf) rather than returning an error, since it's meant to be
+// called directly from a test body.
+func (f *FakeTemplate) Generate(t *testing.T, vars map[string]any) *GeneratedResult {
+	t.Helper()
+
+	var cfg fakeConfig
+	if raw, ok := f.files["template.json"]; ok {
+		if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+			t.Fatalf("templatetest: invalid template.json: %v", err)
+		}
+	}
+
+	data := make(map[string]interface{}, len(vars)+len(cfg.Variables))
+	for k, v := range vars {
+		data[k] = v
+	}
+	for name, v := range cfg.Variables {
+		if _, ok := data[name]; ok {
+			continue
+		}
+		if v.Default != nil {
+			data[name] = v.Default
+			continue
+		}
+		if v.Required {
+			t.Fatalf("templatetest: required variable %q not provided", name)
+		}
+	}
+
+	result := &GeneratedResult{files: map[string]string{}}
+	result.dryRunLog = append(result.dryRunLog, "[Dry Run] Simulating file generation...")
+
+	paths := make([]string, 0, len(f.files))
+	for path := range f.files {
+		if path == "template.json" {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if ignored(path, cfg.Ignore) {
+			continue
+		}
+
+		sourcePath := path
+		if cond, ok := cfg.ConditionalPaths[path]; ok {
+			if cond.When != "" {
+				t.Fatalf("templatetest: conditional path %q uses a \"when\" expression, which templatetest does not support", path)
+			}
+			evaluated := execTemplate(t, "conditional:"+path, cond.Template, data)
+			if evaluated == "" {
+				continue
+			}
+			sourcePath = evaluated
+		}
+
+		content, ok := f.files[sourcePath]
+		if !ok {
+			t.Fatalf("templatetest: source path %q (for destination %q) not found among fake template files", sourcePath, path)
+		}
+
+		destPath := strings.TrimSuffix(execTemplate(t, "destpath:"+path, path, data), ".tmpl")
+		if destPath == "" {
+			continue
+		}
+
+		if strings.HasSuffix(sourcePath, ".tmpl") {
+			content = execTemplate(t, "content:"+sourcePath, content, data)
+		}
+		content = applyGoReplacements(content, filepath.Base(destPath), data)
+
+		result.files[destPath] = content
+		result.order = append(result.order, destPath)
+		result.dryRunLog = append(result.dryRunLog, fmt.Sprintf("[Dry Run] Would write file: %s (from source %s)", destPath, sourcePath))
+	}
+
+	result.dryRunLog = append(result.dryRunLog, "[Dry Run] Simulation complete.")
+
+	stages := make([]string, 0, len(cfg.Hooks))
+	for stage := range cfg.Hooks {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		for i, step := range cfg.Hooks[stage] {
+			command := execTemplate(t, fmt.Sprintf("hook:%s[%d]", stage, i), step.Command, data)
+			if argv := strings.Fields(command); len(argv) > 0 {
+				result.hookCommands = append(result.hookCommands, argv)
+			}
+		}
+	}
+
+	return result
+}
+
+// ignored reports whether path matches one of the Ignore glob patterns.
+func ignored(path string, patterns []string) bool {
+	if strings.Contains(path, ".git") {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// execTemplate parses and executes tmplText as a Go template against data,
+// failing t with context (label) on error.
+func execTemplate(t *testing.T, label, tmplText string, data map[string]interface{}) string {
+	t.Helper()
+	tmpl, err := template.New(label).Parse(tmplText)
+	if err != nil {
+		t.Fatalf("templatetest: parsing %s: %v", label, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("templatetest: executing %s: %v", label, err)
+	}
+	return buf.String()
+}
+
+var packageLineRegex = regexp.MustCompile(`^package\s+\w+`)
+
+// applyGoReplacements mirrors main.go's applyGoSpecificReplacements: for
+// generated .go files only, it normalizes the package clause to "package
+// mixin" and swaps in MixinName/AuthorName for the YOURNAME/mixin
+// placeholders main.go's own template source uses.
+func applyGoReplacements(content, destRelPath string, data map[string]interface{}) string {
+	if !strings.HasSuffix(destRelPath, ".go") {
+		return content
+	}
+
+	mixinName, _ := data["MixinName"].(string)
+	authorName, _ := data["AuthorName"].(string)
+
+	if !strings.Contains(content, "package mixin") {
+		content = packageLineRegex.ReplaceAllString(content, "package mixin")
+	}
+
+	content = strings.ReplaceAll(content, `"YOURNAME"`, `"`+authorName+`"`)
+	content = strings.ReplaceAll(content, `Use:  "mixin"`, `Use:  "`+mixinName+`"`)
+	content = strings.ReplaceAll(content, `StartRootSpan(ctx, "mixin")`, `StartRootSpan(ctx, "`+mixinName+`")`)
+
+	return content
+}
@@ -0,0 +1,55 @@
+package templatetest
+
+import "testing"
+
+func TestFakeTemplate_Generate(t *testing.T) {
+	fake := NewFakeTemplate(map[string]string{
+		"template.json": `{
+			"name": "Example",
+			"variables": {"MixinName": {"type": "string", "required": true}},
+			"hooks": {"post_gen": ["echo done {{ .MixinName }}"]}
+		}`,
+		"README.md.tmpl": "# {{ .MixinName }}",
+		"skip.txt":       "untouched, not a .tmpl",
+	})
+
+	result := fake.Generate(t, map[string]any{"MixinName": "widget"})
+
+	if got := result.File("README.md"); got != "# widget" {
+		t.Errorf("File(%q) = %q, want %q", "README.md", got, "# widget")
+	}
+	if got := result.File("skip.txt"); got != "untouched, not a .tmpl" {
+		t.Errorf("non-.tmpl files should pass through unmodified, got %q", got)
+	}
+
+	wantFiles := []string{"README.md", "skip.txt"}
+	gotFiles := result.Files()
+	if len(gotFiles) != len(wantFiles) {
+		t.Fatalf("Files() = %v, want %v", gotFiles, wantFiles)
+	}
+	for i, f := range wantFiles {
+		if gotFiles[i] != f {
+			t.Errorf("Files()[%d] = %q, want %q", i, gotFiles[i], f)
+		}
+	}
+
+	if got, want := result.HookCommands(), [][]string{{"echo", "done", "widget"}}; len(got) != 1 || got[0][0] != want[0][0] {
+		t.Errorf("HookCommands() = %v, want %v", got, want)
+	}
+}
+
+func TestFakeTemplate_Generate_ConditionalPathSkipsWhenEmpty(t *testing.T) {
+	fake := NewFakeTemplate(map[string]string{
+		"template.json": `{
+			"conditional_paths": {
+				"extra.txt.tmpl": "{{ if .Enabled }}extra.txt.tmpl{{ end }}"
+			}
+		}`,
+		"extra.txt.tmpl": "extra content",
+	})
+
+	result := fake.Generate(t, map[string]any{"Enabled": false})
+	if len(result.Files()) != 0 {
+		t.Errorf("Files() = %v, want empty (conditional path evaluated to empty)", result.Files())
+	}
+}
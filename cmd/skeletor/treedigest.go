@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// computeTreeDigest returns a recursive Merkle digest of every regular file
+// under root in fsys: SHA-256 over each file's "path\x00mode\x00content-sha"
+// line, sorted by path, the same entry layout buildkit's contenthash
+// package hashes its radix tree over. Directories don't contribute an entry
+// of their own; their presence is implied by the file paths beneath them.
+// The result is deterministic regardless of filesystem iteration order and
+// stable across independent clones of the same tree.
+func computeTreeDigest(fsys fs.FS, root string) (string, error) {
+	var entries []string
+	walkErr := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobSHA, err := hashFileContent(fsys, path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, root), "/")
+		entries = append(entries, fmt.Sprintf("%s\x00%o\x00%s", rel, info.Mode().Perm(), blobSHA))
+		return nil
+	})
+	if walkErr != nil {
+		return "", fmt.Errorf("failed to walk %s for tree digest: %w", root, walkErr)
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		io.WriteString(h, entry) //nolint:errcheck // hash.Hash.Write never returns an error
+		h.Write([]byte{'\n'})
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFileContent returns a file's own SHA-256 content hash, the
+// "content-sha" each tree digest entry cites, streamed rather than read
+// fully into memory.
+func hashFileContent(fsys fs.FS, path string) (string, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTreeFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0o600))
+	return dir
+}
+
+func TestComputeTreeDigest_DeterministicAndContentSensitive(t *testing.T) {
+	dirA := writeTreeFixture(t)
+	dirB := writeTreeFixture(t)
+
+	digestA, err := computeTreeDigest(os.DirFS(dirA), ".")
+	require.NoError(t, err)
+	digestB, err := computeTreeDigest(os.DirFS(dirB), ".")
+	require.NoError(t, err)
+	assert.Equal(t, digestA, digestB, "two independent trees with identical content should hash the same")
+	assert.True(t, len(digestA) > len("sha256:"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dirB, "a.txt"), []byte("changed"), 0o600))
+	digestBChanged, err := computeTreeDigest(os.DirFS(dirB), ".")
+	require.NoError(t, err)
+	assert.NotEqual(t, digestA, digestBChanged)
+}
+
+func TestComputeTreeDigest_InsensitiveToFilesystemOrder(t *testing.T) {
+	dir := writeTreeFixture(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "z.txt"), []byte("z"), 0o600))
+
+	first, err := computeTreeDigest(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	second, err := computeTreeDigest(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestComputeTreeDigest_ScopedToRoot(t *testing.T) {
+	dir := writeTreeFixture(t)
+	full, err := computeTreeDigest(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	sub, err := computeTreeDigest(os.DirFS(dir), "sub")
+	require.NoError(t, err)
+	assert.NotEqual(t, full, sub)
+}
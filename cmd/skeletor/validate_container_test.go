@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// validateInContainerEnvVar opts the post-generation validation steps into
+// running inside a pinned Go container via testcontainers-go instead of
+// whatever Go toolchain and golangci-lint happen to be on the host. Host
+// mode (the default) is far cheaper when a contributor's machine is
+// already set up correctly; set SKELETOR_VALIDATE_IN_CONTAINER=1 to
+// exercise the hermetic path CI itself runs.
+const validateInContainerEnvVar = "SKELETOR_VALIDATE_IN_CONTAINER"
+
+// validationContainerImage pins the toolchain container-mode validates
+// against, independent of whatever Go release happens to be on the host.
+// Matches ci/main.go's goVersion so a generated mixin's go.mod (inferred
+// from whatever Go ran its post_gen "go mod tidy") isn't rejected by an
+// older toolchain here than the one that actually builds/releases it.
+const validationContainerImage = "golang:1.23"
+
+// These match the versions ci/main.go's Dagger release pipeline installs,
+// so a generated mixin is validated against the same linter/release
+// tooling here as in CI.
+const (
+	validationGolangciLintVersion = "v1.61.0"
+	validationGoreleaserVersion   = "v2.4.1"
+)
+
+// postGenValidationSteps returns the post-generation validation commands
+// for level, in the order they must run from the generated project's root:
+// go mod tidy (in case a template change needs a dependency the post_gen
+// hook's own tidy didn't already resolve), go build, golangci-lint, and --
+// for slsa-l3, which ships a .goreleaser.yml -- goreleaser check. Both
+// host- and container-mode run exactly this list.
+func postGenValidationSteps(level string) [][]string {
+	steps := [][]string{
+		{"go", "mod", "tidy"},
+		{"go", "build", "./..."},
+		{"golangci-lint", "run", "./..."},
+	}
+	if level == "slsa-l3" {
+		steps = append(steps, []string{"goreleaser", "check"})
+	}
+	return steps
+}
+
+// runPostGenValidation runs postGenValidationSteps(level) against
+// outputDir, in a pinned container when validateInContainerEnvVar is set
+// to "1" and on the host otherwise.
+func runPostGenValidation(t *testing.T, outputDir, level string) {
+	t.Helper()
+	if os.Getenv(validateInContainerEnvVar) == "1" {
+		runPostGenValidationInContainer(t, outputDir, level)
+		return
+	}
+	runPostGenValidationOnHost(t, outputDir, level)
+}
+
+// runPostGenValidationOnHost runs postGenValidationSteps(level) with
+// outputDir as the working directory, using whatever go/golangci-lint/
+// goreleaser are already on the host's PATH.
+func runPostGenValidationOnHost(t *testing.T, outputDir, level string) {
+	t.Helper()
+	for _, argv := range postGenValidationSteps(level) {
+		t.Logf("[host] running %s in %s...", strings.Join(argv, " "), outputDir)
+		// #nosec G204 -- argv comes from postGenValidationSteps, not external input
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Dir = outputDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "%s failed. Output:\n%s", strings.Join(argv, " "), string(output))
+	}
+}
+
+// runPostGenValidationInContainer starts a validationContainerImage
+// container with outputDir bind-mounted read-write at /workspace, installs
+// the pinned golangci-lint (and, for slsa-l3, goreleaser) versions
+// validationContainerImage doesn't ship, then runs postGenValidationSteps(level) inside it,
+// streaming each step's combined output through t.Log and failing with
+// that output on a non-zero exit.
+func runPostGenValidationInContainer(t *testing.T, outputDir, level string) {
+	t.Helper()
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image: validationContainerImage,
+		Cmd:   []string{"sleep", "infinity"},
+		HostConfigModifier: func(hc *container.HostConfig) {
+			hc.Binds = append(hc.Binds, outputDir+":/workspace:rw")
+		},
+	}
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err, "failed to start %s validation container", validationContainerImage)
+	defer func() {
+		require.NoError(t, ctr.Terminate(context.Background()), "failed to terminate validation container")
+	}()
+
+	install := [][]string{
+		{"go", "install", "github.com/golangci/golangci-lint/cmd/golangci-lint@" + validationGolangciLintVersion},
+	}
+	if level == "slsa-l3" {
+		install = append(install, []string{"go", "install", "github.com/goreleaser/goreleaser@" + validationGoreleaserVersion})
+	}
+
+	for _, argv := range append(install, postGenValidationSteps(level)...) {
+		runInContainer(t, ctr, argv)
+	}
+}
+
+// runInContainer execs argv inside /workspace of ctr, logging its combined
+// output and failing the test (with that output attached) on a non-zero
+// exit code.
+func runInContainer(t *testing.T, ctr testcontainers.Container, argv []string) {
+	t.Helper()
+	ctx := context.Background()
+	shellCmd := []string{"sh", "-c", "cd /workspace && " + strings.Join(argv, " ")}
+
+	t.Logf("[container] running %s...", strings.Join(argv, " "))
+	exitCode, reader, err := ctr.Exec(ctx, shellCmd)
+	require.NoError(t, err, "failed to exec %s in validation container", strings.Join(argv, " "))
+
+	output, readErr := io.ReadAll(reader)
+	require.NoError(t, readErr, "failed to read container output for %s", strings.Join(argv, " "))
+	t.Log(string(output))
+	require.Zero(t, exitCode, "%s failed in validation container. Output:\n%s", strings.Join(argv, " "), string(output))
+}
+
+// TestCreateMixin_Integration_ValidationMatrix runs postGenValidationSteps
+// for every compliance level under both host- and container-mode, when
+// Docker is available -- so a CI run (or a contributor with Docker
+// installed) exercises both validation paths without relying on
+// SKELETOR_VALIDATE_IN_CONTAINER being set.
+func TestCreateMixin_Integration_ValidationMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode.")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	binaryPath := getGeneratorBinaryPath(t)
+
+	for _, level := range []string{"basic", "slsa-l1", "slsa-l3"} {
+		t.Run(level, func(t *testing.T) {
+			mixinName := "matrix-" + strings.ReplaceAll(level, "-", "") + "-mixin"
+			args := []string{
+				"--name", mixinName,
+				"--author", "Matrix Test Author",
+				"--module", "example.com/getporter/" + mixinName,
+				"--compliance-level", level,
+			}
+
+			outputDir, err := runGeneratorCreate(t, binaryPath, args...)
+			require.NoError(t, err, "generator create failed for %s", level)
+			defer os.RemoveAll(outputDir)
+
+			t.Run("host", func(t *testing.T) {
+				runPostGenValidationOnHost(t, outputDir, level)
+			})
+			t.Run("container", func(t *testing.T) {
+				runPostGenValidationInContainer(t, outputDir, level)
+			})
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+// loadValuesFile reads path -- JSON, YAML, TOML, or HCL, inferred from its
+// extension the same way --config-source infers a Source's format -- into
+// a map of already-typed values for --values-file. buildTemplateData
+// validates each entry against the same VariableSchema a --var or default
+// value would be checked against.
+func loadValuesFile(path string) (map[string]interface{}, error) {
+	tree, warning, err := (Source{Path: path}).decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load values file %s: %w", path, err)
+	}
+	if warning != "" {
+		return nil, fmt.Errorf("values file %s not found", path)
+	}
+	return tree, nil
+}
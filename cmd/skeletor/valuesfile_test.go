@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadValuesFile_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("MixinName: demo\nPort: 8080\n"), 0o644))
+
+	values, err := loadValuesFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "demo", values["MixinName"])
+	assert.Equal(t, 8080, values["Port"])
+}
+
+func TestLoadValuesFile_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"MixinName": "demo", "Port": 8080}`), 0o644))
+
+	values, err := loadValuesFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "demo", values["MixinName"])
+}
+
+func TestLoadValuesFile_MissingFile(t *testing.T) {
+	_, err := loadValuesFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce absorbs the burst of events a single save can produce (e.g.
+// a WRITE followed by a CHMOD) into one regeneration.
+const watchDebounce = 150 * time.Millisecond
+
+// watchAndRegenerate watches templateDir (and every subdirectory, since
+// fsnotify isn't recursive) and, on every relevant change, reloads
+// template.json and re-runs createMixin into outputDir -- first as a
+// DryRunPlain pass to print a diff summary, then for real -- so a template
+// author sees the effect of an edit without re-invoking `skeletor create`.
+// It blocks until the watcher's channels close; a watcher error is printed
+// to stderr and watching continues.
+func watchAndRegenerate(templateDir, outputDir string, data map[string]interface{}, parallelism int, onConflict string, gate *PolicyGateOptions) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start template watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirsRecursive(watcher, templateDir); err != nil {
+		return err
+	}
+	fmt.Printf("\nWatching %s for changes (Ctrl+C to stop)...\n", templateDir)
+
+	var debounce *time.Timer
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			// A newly created subdirectory needs its own watch, since
+			// fsnotify only watches the directories it was explicitly Add'd to.
+			if info, statErr := os.Stat(event.Name); statErr == nil && info.IsDir() {
+				_ = watcher.Add(event.Name)
+			}
+			if debounce == nil {
+				debounce = time.NewTimer(watchDebounce)
+			} else {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		case <-watchTimerC(debounce):
+			debounce = nil
+			if err := regenerateOnce(templateDir, outputDir, data, parallelism, onConflict, gate); err != nil {
+				fmt.Fprintf(os.Stderr, "watch: regeneration failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// watchTimerC returns t.C, or a nil channel (which blocks forever in a
+// select) while no debounce timer is pending.
+func watchTimerC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
+// addWatchDirsRecursive adds root and every subdirectory under it to
+// watcher.
+func addWatchDirsRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if addErr := watcher.Add(path); addErr != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, addErr)
+			}
+		}
+		return nil
+	})
+}
+
+// regenerateOnce reloads templateDir's template.json (picking up edits to it
+// too) and re-runs createMixin twice: a DryRunPlain pass to print a diff
+// summary of what would change, then for real so outputDir reflects it.
+func regenerateOnce(templateDir, outputDir string, data map[string]interface{}, parallelism int, onConflict string, gate *PolicyGateOptions) error {
+	tmplFS := os.DirFS(templateDir)
+	config, err := LoadTemplateConfig(tmplFS, ".")
+	if err != nil {
+		return fmt.Errorf("failed to reload template config: %w", err)
+	}
+
+	fmt.Println("\n--- template changed, regenerating ---")
+	if err := createMixin(data, tmplFS, ".", outputDir, config, string(DryRunPlain), parallelism, onConflict, gate); err != nil {
+		return fmt.Errorf("failed to plan regeneration: %w", err)
+	}
+	return createMixin(data, tmplFS, ".", outputDir, config, "", parallelism, onConflict, gate)
+}
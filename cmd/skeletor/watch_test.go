@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchTimerC_NilTimerIsNeverReady(t *testing.T) {
+	select {
+	case <-watchTimerC(nil):
+		t.Fatal("nil timer's channel should never be ready")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestWatchTimerC_FiredTimerIsReady(t *testing.T) {
+	timer := time.NewTimer(time.Millisecond)
+	select {
+	case <-watchTimerC(timer):
+	case <-time.After(time.Second):
+		t.Fatal("expected the timer to fire")
+	}
+}
+
+func TestAddWatchDirsRecursive_WatchesEveryNestedDir(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o750))
+
+	watcher, err := fsnotify.NewWatcher()
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	require.NoError(t, addWatchDirsRecursive(watcher, root))
+
+	watched := watcher.WatchList()
+	assert.Contains(t, watched, root)
+	assert.Contains(t, watched, filepath.Join(root, "a"))
+	assert.Contains(t, watched, nested)
+}
+
+func TestRegenerateOnce_WritesChangedOutput(t *testing.T) {
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "template.json"), []byte(`{"name": "Watch Test", "variables": {"MixinName": {}}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "fresh.txt.tmpl"), []byte("fresh content for {{ .MixinName }}"), 0o600))
+
+	outputDir := t.TempDir()
+	data := map[string]interface{}{"MixinName": "my-mixin"}
+
+	require.NoError(t, regenerateOnce(templateDir, outputDir, data, 0, "", nil))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "fresh.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "fresh content for my-mixin", string(content))
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InteractiveMode selects the --interactive front end for variables that
+// aren't already resolved by --var/a SKELETOR_VAR_<NAME> environment
+// variable/--values-file.
+type InteractiveMode string
+
+const (
+	// InteractiveModePrompt, the default, asks for each such variable in
+	// turn via promptString/promptStringWithDefault, buildTemplateData's
+	// original behavior.
+	InteractiveModePrompt InteractiveMode = "prompt"
+	// InteractiveModeTUI drives a full-screen wizard (see runWizard)
+	// instead, one page per Variable.Group.
+	InteractiveModeTUI InteractiveMode = "tui"
+)
+
+// parseInteractiveMode parses the --interactive flag value, defaulting to
+// InteractiveModePrompt for an empty string.
+func parseInteractiveMode(s string) (InteractiveMode, error) {
+	switch InteractiveMode(s) {
+	case "", InteractiveModePrompt:
+		return InteractiveModePrompt, nil
+	case InteractiveModeTUI:
+		return InteractiveModeTUI, nil
+	default:
+		return "", fmt.Errorf("unknown --interactive value %q (expected prompt or tui)", s)
+	}
+}
+
+// wizardChecklist is the checkbox widget for a "list"-typed variable that
+// declares Choices: up/down moves the highlighted option, space toggles it.
+type wizardChecklist struct {
+	options  []string
+	selected map[int]bool
+	cursor   int
+}
+
+func newWizardChecklist(options []string) *wizardChecklist {
+	return &wizardChecklist{options: options, selected: map[int]bool{}}
+}
+
+// csv joins every selected option, in declaration order, the same
+// comma-separated form a --var list value is parsed from (see
+// coerceListValue), so the checklist's result needs no special-casing once
+// it reaches the validation pipeline.
+func (c *wizardChecklist) csv() string {
+	var picked []string
+	for i, opt := range c.options {
+		if c.selected[i] {
+			picked = append(picked, opt)
+		}
+	}
+	return strings.Join(picked, ",")
+}
+
+func (c *wizardChecklist) view() string {
+	var b strings.Builder
+	for i, opt := range c.options {
+		cursor := " "
+		if i == c.cursor {
+			cursor = ">"
+		}
+		box := "[ ]"
+		if c.selected[i] {
+			box = "[x]"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", cursor, box, opt)
+	}
+	return b.String()
+}
+
+// wizardField is one Variable rendered as a widget: a free-text
+// textinput.Model for everything except a "list"-typed variable with
+// Choices, which gets a wizardChecklist instead.
+type wizardField struct {
+	varName   string
+	varConfig Variable
+	schema    VariableSchema
+	input     textinput.Model
+	checklist *wizardChecklist
+	err       string
+}
+
+func newWizardField(varName string, varConfig Variable) *wizardField {
+	schema := effectiveVariableSchema(varConfig)
+	field := &wizardField{varName: varName, varConfig: varConfig, schema: schema}
+
+	if strings.ToLower(schema.Type) == "list" && len(varConfig.Choices) > 0 {
+		field.checklist = newWizardChecklist(varConfig.Choices)
+		return field
+	}
+
+	input := textinput.New()
+	input.Prompt = varName + ": "
+	input.Placeholder = varConfig.Description
+	if varConfig.Default != nil {
+		input.SetValue(fmt.Sprintf("%v", varConfig.Default))
+	}
+	field.input = input
+	return field
+}
+
+// rawValue returns what the user has entered so far, before
+// coerceToSchema -- a plain string for a textinput field, or the selected
+// checklist options joined as CSV.
+func (f *wizardField) rawValue() string {
+	if f.checklist != nil {
+		return f.checklist.csv()
+	}
+	return f.input.Value()
+}
+
+func (f *wizardField) focus() {
+	if f.checklist == nil {
+		f.input.Focus()
+	}
+}
+
+func (f *wizardField) blur() {
+	if f.checklist == nil {
+		f.input.Blur()
+	}
+}
+
+// wizardPage groups one Variable.Group's fields onto a single screen --
+// the wizard's "basic info"/"enterprise features"/"compliance" pages.
+type wizardPage struct {
+	group  string
+	fields []int // indices into wizardModel.fields belonging to this page
+}
+
+type wizardStage int
+
+const (
+	wizardStageForm wizardStage = iota
+	wizardStageReview
+	wizardStageDone
+	wizardStageCancelled
+)
+
+// wizardModel is the bubbletea model behind --interactive=tui: one page per
+// Variable.Group (alphabetical, "general" for an unset Group), then a final
+// review page that can jump back into any page to edit a field before
+// committing. It only ever collects and coerces/validates answers into
+// data -- runWizard hands that back to the caller as ordinary
+// --values-file-tier values, so resolveVariable's precedence and
+// buildTemplateData's validation run exactly the same as for every other
+// source; the wizard is purely a front end.
+type wizardModel struct {
+	data map[string]interface{} // accumulated as the wizard proceeds, so a later page's validation Expr can reference an earlier page's already-committed answer
+
+	fields   []*wizardField
+	pages    []wizardPage
+	pageIdx  int
+	fieldIdx int
+
+	stage wizardStage
+}
+
+// newWizardModel groups config's still-unresolved variables (varNames) by
+// Group into one page per group, sorted alphabetically for a deterministic
+// layout across runs.
+func newWizardModel(config *TemplateConfig, varNames []string) *wizardModel {
+	byGroup := map[string][]string{}
+	for _, varName := range varNames {
+		group := config.Variables[varName].Group
+		if group == "" {
+			group = "general"
+		}
+		byGroup[group] = append(byGroup[group], varName)
+	}
+
+	groupNames := make([]string, 0, len(byGroup))
+	for group, names := range byGroup {
+		groupNames = append(groupNames, group)
+		sort.Strings(names)
+	}
+	sort.Strings(groupNames)
+
+	m := &wizardModel{data: map[string]interface{}{}}
+	for _, group := range groupNames {
+		page := wizardPage{group: group}
+		for _, varName := range byGroup[group] {
+			page.fields = append(page.fields, len(m.fields))
+			m.fields = append(m.fields, newWizardField(varName, config.Variables[varName]))
+		}
+		m.pages = append(m.pages, page)
+	}
+	if len(m.fields) > 0 {
+		m.currentField().focus()
+	}
+	return m
+}
+
+func (m *wizardModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *wizardModel) currentPage() wizardPage {
+	return m.pages[m.pageIdx]
+}
+
+func (m *wizardModel) currentField() *wizardField {
+	page := m.currentPage()
+	return m.fields[page.fields[m.fieldIdx]]
+}
+
+func (m *wizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		m.stage = wizardStageCancelled
+		return m, tea.Quit
+	}
+
+	if m.stage == wizardStageReview {
+		return m.updateReview(keyMsg)
+	}
+	return m.updateForm(keyMsg)
+}
+
+func (m *wizardModel) updateForm(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := m.currentField()
+
+	switch keyMsg.String() {
+	case "up":
+		if field.checklist != nil && field.checklist.cursor > 0 {
+			field.checklist.cursor--
+		}
+		return m, nil
+	case "down":
+		if field.checklist != nil && field.checklist.cursor < len(field.checklist.options)-1 {
+			field.checklist.cursor++
+		}
+		return m, nil
+	case " ":
+		if field.checklist != nil {
+			field.checklist.selected[field.checklist.cursor] = !field.checklist.selected[field.checklist.cursor]
+			return m, nil
+		}
+	case "tab":
+		m.moveField(false)
+		return m, nil
+	case "shift+tab":
+		m.moveField(true)
+		return m, nil
+	case "enter":
+		if m.commitField(field) {
+			m.advance()
+		}
+		return m, nil
+	}
+
+	if field.checklist == nil {
+		var cmd tea.Cmd
+		field.input, cmd = field.input.Update(keyMsg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *wizardModel) updateReview(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "enter", "y":
+		m.stage = wizardStageDone
+		return m, tea.Quit
+	default:
+		if n, err := strconv.Atoi(keyMsg.String()); err == nil && n >= 1 && n <= len(m.pages) {
+			m.pageIdx = n - 1
+			m.fieldIdx = 0
+			m.stage = wizardStageForm
+			m.currentField().focus()
+		}
+		return m, nil
+	}
+}
+
+// moveField shifts focus to the previous (backward) or next field on the
+// current page, wrapping around.
+func (m *wizardModel) moveField(backward bool) {
+	page := m.currentPage()
+	m.currentField().blur()
+	if backward {
+		m.fieldIdx = (m.fieldIdx - 1 + len(page.fields)) % len(page.fields)
+	} else {
+		m.fieldIdx = (m.fieldIdx + 1) % len(page.fields)
+	}
+	m.currentField().focus()
+}
+
+// commitField coerces and validates field's current rawValue through the
+// same coerceToSchema/validateAgainstSchema/validateVariableExtra calls
+// buildTemplateData itself uses, storing the result (and surfacing any
+// failure inline via field.err) instead of advancing past it.
+func (m *wizardModel) commitField(field *wizardField) bool {
+	raw := field.rawValue()
+	if raw == "" {
+		if field.schema.Required {
+			field.err = fmt.Sprintf("required variable %s is not provided", field.varName)
+			return false
+		}
+		field.err = ""
+		delete(m.data, field.varName) // an optional field left blank contributes nothing, rather than an explicit empty value
+		return true
+	}
+
+	value, err := coerceToSchema(field.varName, field.schema, raw)
+	if err == nil {
+		err = validateAgainstSchema(field.varName, field.schema, value)
+	}
+	if err == nil {
+		err = validateVariableExtra(field.varName, field.varConfig.Validation, value, m.data)
+	}
+	if err != nil {
+		field.err = err.Error()
+		return false
+	}
+	field.err = ""
+	m.data[field.varName] = value
+	return true
+}
+
+// advance moves to the next field on the current page, the first field of
+// the next page, or the review page once the last page's last field has
+// been committed.
+func (m *wizardModel) advance() {
+	page := m.currentPage()
+	if m.fieldIdx < len(page.fields)-1 {
+		m.moveField(false)
+		return
+	}
+	m.currentField().blur()
+	if m.pageIdx < len(m.pages)-1 {
+		m.pageIdx++
+		m.fieldIdx = 0
+		m.currentField().focus()
+		return
+	}
+	m.stage = wizardStageReview
+}
+
+func (m *wizardModel) View() string {
+	if m.stage == wizardStageReview {
+		return m.reviewView()
+	}
+
+	page := m.currentPage()
+	var b strings.Builder
+	fmt.Fprintf(&b, "skeletor create -- page %d/%d: %s\n\n", m.pageIdx+1, len(m.pages), page.group)
+	for i, idx := range page.fields {
+		field := m.fields[idx]
+		cursor := "  "
+		if i == m.fieldIdx {
+			cursor = "> "
+		}
+		if field.checklist != nil {
+			fmt.Fprintf(&b, "%s%s (%s)\n%s", cursor, field.varName, field.varConfig.Description, field.checklist.view())
+		} else {
+			fmt.Fprintf(&b, "%s%s\n", cursor, field.input.View())
+		}
+		if field.err != "" {
+			fmt.Fprintf(&b, "    ! %s\n", field.err)
+		}
+	}
+	b.WriteString("\n(tab/shift+tab: move, space: toggle a checkbox, enter: next, esc: cancel)\n")
+	return b.String()
+}
+
+func (m *wizardModel) reviewView() string {
+	var b strings.Builder
+	b.WriteString("Review your answers (enter to confirm, a page number to jump back and edit, esc to cancel):\n\n")
+	for i, page := range m.pages {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, page.group)
+		for _, idx := range page.fields {
+			field := m.fields[idx]
+			fmt.Fprintf(&b, "   %s = %v\n", field.varName, m.data[field.varName])
+		}
+	}
+	return b.String()
+}
+
+// runWizard drives the --interactive=tui wizard over every declared
+// variable that --var/a SKELETOR_VAR_<NAME> environment variable/
+// --values-file didn't already resolve (per resolveVariable, the exact same
+// check buildTemplateData's own prompt fallback uses), and returns the
+// answers it collected -- already coerced and validated -- keyed by
+// variable name. The caller merges these into its own values map as one
+// more --values-file-tier source; buildTemplateData re-validates them
+// there too, so a variable's precedence and validation never depend on
+// which front end supplied it.
+func runWizard(config *TemplateConfig, rawVars map[string]string, rawVarIndex map[string]int, extraVars []string, values map[string]interface{}) (map[string]interface{}, error) {
+	varNames := make([]string, 0, len(config.Variables))
+	for varName := range config.Variables {
+		if resolveVariable(varName, rawVars, rawVarIndex, extraVars, values).Found {
+			continue
+		}
+		varNames = append(varNames, varName)
+	}
+	sort.Strings(varNames)
+
+	model := newWizardModel(config, varNames)
+	if len(model.fields) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	final, err := tea.NewProgram(model).Run()
+	if err != nil {
+		return nil, fmt.Errorf("wizard failed: %w", err)
+	}
+
+	finished, ok := final.(*wizardModel)
+	if !ok {
+		return nil, fmt.Errorf("wizard exited unexpectedly")
+	}
+	if finished.stage != wizardStageDone {
+		return nil, fmt.Errorf("wizard cancelled")
+	}
+	return finished.data, nil
+}
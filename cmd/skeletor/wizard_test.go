@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInteractiveMode(t *testing.T) {
+	mode, err := parseInteractiveMode("")
+	require.NoError(t, err)
+	assert.Equal(t, InteractiveModePrompt, mode)
+
+	mode, err = parseInteractiveMode("tui")
+	require.NoError(t, err)
+	assert.Equal(t, InteractiveModeTUI, mode)
+
+	_, err = parseInteractiveMode("gui")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"gui"`)
+}
+
+func TestWizardChecklist_Csv(t *testing.T) {
+	checklist := newWizardChecklist([]string{"a", "b", "c"})
+	checklist.selected[0] = true
+	checklist.selected[2] = true
+	assert.Equal(t, "a,c", checklist.csv())
+}
+
+func TestNewWizardModel_GroupsByVariableGroupAlphabetically(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{
+		"EnableSecurity":  {Group: "enterprise"},
+		"MixinName":       {}, // defaults to "general"
+		"ComplianceLevel": {Group: "compliance"},
+	}}
+	model := newWizardModel(config, []string{"EnableSecurity", "MixinName", "ComplianceLevel"})
+
+	require.Len(t, model.pages, 3)
+	assert.Equal(t, "compliance", model.pages[0].group)
+	assert.Equal(t, "enterprise", model.pages[1].group)
+	assert.Equal(t, "general", model.pages[2].group)
+}
+
+func TestWizardModel_CommitField_RequiredBlankFails(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{"MixinName": {Required: true}}}
+	model := newWizardModel(config, []string{"MixinName"})
+	field := model.currentField()
+
+	assert.False(t, model.commitField(field))
+	assert.NotEmpty(t, field.err)
+}
+
+func TestWizardModel_CommitField_OptionalBlankClearsStaleValue(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{"Notes": {}}}
+	model := newWizardModel(config, []string{"Notes"})
+	model.data["Notes"] = "stale"
+	field := model.currentField()
+
+	assert.True(t, model.commitField(field))
+	_, exists := model.data["Notes"]
+	assert.False(t, exists)
+}
+
+func TestWizardModel_CommitField_ChecklistCollectsSelectedChoices(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{
+		"SecurityFeatures": {Type: "list", Choices: []string{"mfa", "rbac", "audit"}},
+	}}
+	model := newWizardModel(config, []string{"SecurityFeatures"})
+	field := model.currentField()
+	require.NotNil(t, field.checklist)
+	field.checklist.selected[0] = true
+	field.checklist.selected[2] = true
+
+	require.True(t, model.commitField(field))
+	assert.Equal(t, []interface{}{"mfa", "audit"}, model.data["SecurityFeatures"])
+}
+
+func TestWizardModel_Advance_MovesThroughPagesToReview(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{
+		"Alpha": {Group: "first"},
+		"Beta":  {Group: "second"},
+	}}
+	model := newWizardModel(config, []string{"Alpha", "Beta"})
+	require.Len(t, model.pages, 2)
+
+	require.True(t, model.commitField(model.currentField()))
+	model.advance()
+	assert.Equal(t, 1, model.pageIdx)
+	assert.Equal(t, "second", model.currentPage().group)
+
+	require.True(t, model.commitField(model.currentField()))
+	model.advance()
+	assert.Equal(t, wizardStageReview, model.stage)
+}
+
+func TestRunWizard_SkipsAlreadyResolvedVariables(t *testing.T) {
+	config := &TemplateConfig{Variables: map[string]Variable{"MixinName": {Required: true}}}
+	rawVars, rawVarIndex, err := parseRawVars([]string{"MixinName=already-set"})
+	require.NoError(t, err)
+
+	answers, err := runWizard(config, rawVars, rawVarIndex, []string{"MixinName=already-set"}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+}
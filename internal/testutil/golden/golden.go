@@ -0,0 +1,354 @@
+// Package golden is a snapshot-testing harness for a generated file tree: it
+// canonicalizes a directory of output (normalizing line endings, scrubbing
+// timestamps/UUIDs, and sorting YAML keys so map-ordering noise doesn't
+// trigger false diffs) and compares it file-by-file against a checked-in
+// golden tree, printing a unified diff for each text file that differs.
+// Run with -update to (re)write the golden tree from the current output
+// instead of comparing against it.
+package golden
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// update is the -update flag golden-aware tests share: `go test ./... -update`
+// regenerates every golden tree a Dir call touches from the current output.
+var update = flag.Bool("update", false, "update golden files instead of comparing against them")
+
+// Scrubber replaces every match of Pattern in a text file's content with
+// Replacement before comparison, so a value that's expected to vary between
+// runs (a timestamp, a generated UUID) doesn't make every run look like a
+// regression.
+type Scrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultScrubbers normalizes the two kinds of run-to-run noise template
+// output tends to contain: RFC3339-ish timestamps and v4 UUIDs.
+func DefaultScrubbers() []Scrubber {
+	return []Scrubber{
+		{
+			Pattern:     regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`),
+			Replacement: "<TIMESTAMP>",
+		},
+		{
+			Pattern:     regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`),
+			Replacement: "<UUID>",
+		},
+	}
+}
+
+// Matcher compares got against want for a file whose golden-relative path
+// matched the pattern it was registered under, failing t (via t.Errorf) with
+// whatever detail is useful for that file kind.
+type Matcher func(t *testing.T, path string, got, want []byte)
+
+// matcherEntry pairs a glob pattern (matched with filepath.Match against the
+// golden-relative, slash-separated path) with the Matcher to use for it.
+type matcherEntry struct {
+	pattern string
+	matcher Matcher
+}
+
+// options accumulates the per-call knobs Dir takes.
+type options struct {
+	scrubbers []Scrubber
+	matchers  []matcherEntry
+	ignore    []string
+}
+
+// Option configures a Dir call.
+type Option func(*options)
+
+// WithScrubbers replaces the default timestamp/UUID scrubbers with ss.
+func WithScrubbers(ss ...Scrubber) Option {
+	return func(o *options) { o.scrubbers = ss }
+}
+
+// WithMatcher registers matcher for any file whose golden-relative path
+// matches pattern (filepath.Match syntax), checked in registration order
+// before the default extension-based matchers. Use this for file kinds the
+// defaults don't cover, e.g. a future embedded tarball compared by digest.
+func WithMatcher(pattern string, matcher Matcher) Option {
+	return func(o *options) { o.matchers = append(o.matchers, matcherEntry{pattern, matcher}) }
+}
+
+// WithIgnore excludes any file whose golden-relative path matches one of
+// patterns (filepath.Match syntax) from the comparison entirely -- it's
+// neither written on -update nor checked for presence in either tree. Use
+// this for output that's inherently non-deterministic across runs, e.g.
+// go.sum/go.mod entries pinned by whatever module versions were current
+// when a post_gen hook ran `go mod tidy`.
+func WithIgnore(patterns ...string) Option {
+	return func(o *options) { o.ignore = append(o.ignore, patterns...) }
+}
+
+func (o options) ignored(rel string) bool {
+	for _, pattern := range o.ignore {
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultBinaryExtensions are compared by sha256 digest rather than diffed
+// as text, since a unified diff of binary content is unreadable.
+var defaultBinaryExtensions = map[string]bool{
+	".png": true, ".gif": true, ".jpg": true, ".jpeg": true,
+	".gz": true, ".tar": true, ".zip": true, ".tgz": true,
+}
+
+// Dir walks gotDir and compares every regular file it contains against its
+// counterpart in wantDir (same relative path), canonicalizing both sides
+// per opts' scrubbers before comparing -- so a golden file that was hand-
+// edited (to resolve a conflict, say) rather than produced by -update still
+// compares fairly instead of failing on line-ending or key-order noise.
+// With -update, it instead (re)writes wantDir from gotDir's canonicalized
+// content, deleting any golden file that no longer has a counterpart in
+// gotDir.
+//
+// If wantDir doesn't exist at all, Dir skips (rather than failing) with a
+// message to run with -update: an absent golden tree means it was never
+// captured, not that the generated output regressed, and a fresh checkout
+// shouldn't be permanently red until someone with a full build of the tool
+// under test populates it.
+func Dir(t *testing.T, gotDir, wantDir string, opts ...Option) {
+	t.Helper()
+
+	o := options{scrubbers: DefaultScrubbers()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if !*update {
+		if _, err := os.Stat(wantDir); os.IsNotExist(err) {
+			t.Skipf("golden: %s does not exist yet; run with -update to capture it", wantDir)
+		}
+	}
+
+	gotPaths := filterIgnored(listFiles(t, gotDir), o)
+
+	if *update {
+		mustNoError(t, os.RemoveAll(wantDir))
+		for _, rel := range gotPaths {
+			content := readAndCanonicalize(t, filepath.Join(gotDir, rel), rel, o)
+			dest := filepath.Join(wantDir, rel)
+			mustNoError(t, os.MkdirAll(filepath.Dir(dest), 0o750))
+			mustNoError(t, os.WriteFile(dest, content, 0o600))
+		}
+		t.Logf("golden: wrote %d file(s) to %s", len(gotPaths), wantDir)
+		return
+	}
+
+	wantPaths := filterIgnored(listFiles(t, wantDir), o)
+	gotSet := toSet(gotPaths)
+	wantSet := toSet(wantPaths)
+
+	for _, rel := range gotPaths {
+		if !wantSet[rel] {
+			t.Errorf("golden: %s: present in generated output but not in golden tree %s (run with -update to add it)", rel, wantDir)
+		}
+	}
+	for _, rel := range wantPaths {
+		if !gotSet[rel] {
+			t.Errorf("golden: %s: present in golden tree %s but not in generated output (run with -update to remove it)", rel, wantDir)
+		}
+	}
+
+	for _, rel := range gotPaths {
+		if !wantSet[rel] {
+			continue
+		}
+		got := readAndCanonicalize(t, filepath.Join(gotDir, rel), rel, o)
+		want := readAndCanonicalize(t, filepath.Join(wantDir, rel), rel, o)
+		matcherFor(rel, o)(t, rel, got, want)
+	}
+}
+
+// matcherFor returns the Matcher to use for rel: the first of o's
+// user-registered matchers whose pattern matches, then a digest comparison
+// for a known binary extension, then a unified-diff text comparison.
+func matcherFor(rel string, o options) Matcher {
+	for _, entry := range o.matchers {
+		if matched, _ := filepath.Match(entry.pattern, rel); matched {
+			return entry.matcher
+		}
+	}
+	if defaultBinaryExtensions[strings.ToLower(filepath.Ext(rel))] {
+		return binaryMatcher
+	}
+	return textMatcher
+}
+
+// binaryMatcher compares got and want by sha256 digest, reporting only that
+// they differ (not a diff, which wouldn't be legible for binary content).
+func binaryMatcher(t *testing.T, path string, got, want []byte) {
+	t.Helper()
+	gotSum, wantSum := sha256.Sum256(got), sha256.Sum256(want)
+	if gotSum != wantSum {
+		t.Errorf("golden: %s: binary content differs from golden (sha256 %x generated, %x golden)", path, gotSum, wantSum)
+	}
+}
+
+// textMatcher compares got and want as text, printing a unified diff on
+// mismatch.
+func textMatcher(t *testing.T, path string, got, want []byte) {
+	t.Helper()
+	if bytes.Equal(got, want) {
+		return
+	}
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(want)),
+		B:        difflib.SplitLines(string(got)),
+		FromFile: "golden/" + path,
+		ToFile:   "generated/" + path,
+		Context:  3,
+	}
+	text, diffErr := difflib.GetUnifiedDiffString(diff)
+	if diffErr != nil {
+		text = fmt.Sprintf("(failed to compute diff: %v)", diffErr)
+	}
+	t.Errorf("golden: %s: does not match golden (run with -update to accept the new content):\n%s", path, text)
+}
+
+// readAndCanonicalize reads path (rel's counterpart under either gotDir or
+// wantDir) and normalizes it per o before comparison: CRLF line endings
+// become LF, o's scrubbers are applied, and YAML files (by rel's extension)
+// are re-marshaled with their map keys sorted so key-ordering differences
+// that don't change meaning don't fail the comparison. Non-text and
+// non-YAML content is left as-is.
+func readAndCanonicalize(t *testing.T, path, rel string, o options) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is discovered by walking the test's own generated output directory
+	mustNoError(t, err)
+
+	ext := strings.ToLower(filepath.Ext(rel))
+	if defaultBinaryExtensions[ext] {
+		return raw
+	}
+
+	content := bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+
+	if ext == ".yml" || ext == ".yaml" {
+		if sorted, ok := sortYAMLKeys(content); ok {
+			content = sorted
+		}
+	}
+
+	text := string(content)
+	for _, s := range o.scrubbers {
+		text = s.Pattern.ReplaceAllString(text, s.Replacement)
+	}
+	return []byte(text)
+}
+
+// sortYAMLKeys re-marshals content with every mapping's keys in lexical
+// order, so two semantically-identical YAML documents produced with
+// different map iteration orders compare equal. It returns ok=false (and
+// content is left untouched by the caller) if content doesn't parse as
+// YAML, which is common for .yml/.yaml files that are actually templates
+// with unresolved placeholders.
+func sortYAMLKeys(content []byte) (sorted []byte, ok bool) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, false
+	}
+	sortYAMLNode(&node)
+	encoded, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+// sortYAMLNode recursively sorts every mapping node's key/value pairs by
+// key, in place.
+func sortYAMLNode(node *yaml.Node) {
+	for _, child := range node.Content {
+		sortYAMLNode(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].key.Value < pairs[j].key.Value })
+	sortedContent := make([]*yaml.Node, 0, len(node.Content))
+	for _, p := range pairs {
+		sortedContent = append(sortedContent, p.key, p.value)
+	}
+	node.Content = sortedContent
+}
+
+// listFiles returns every regular file under dir, as slash-separated paths
+// relative to dir, sorted. A missing dir is treated as empty rather than an
+// error; Dir itself skips before listing an absent golden tree unless
+// -update is set.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(dir, path)
+		if relErr != nil {
+			return relErr
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	mustNoError(t, err)
+	sort.Strings(paths)
+	return paths
+}
+
+// filterIgnored drops every path matching one of o's WithIgnore patterns.
+func filterIgnored(paths []string, o options) []string {
+	kept := paths[:0:0]
+	for _, p := range paths {
+		if !o.ignored(p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func toSet(paths []string) map[string]bool {
+	set := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		set[p] = true
+	}
+	return set
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("golden: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestDir_MatchingTreePasses(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.txt": "hello\n", "nested/b.txt": "world\n"})
+	writeFiles(t, want, map[string]string{"a.txt": "hello\n", "nested/b.txt": "world\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_ContentMismatchFails(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.txt": "hello\n"})
+	writeFiles(t, want, map[string]string{"a.txt": "goodbye\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.True(t, recorder.Failed())
+}
+
+func TestDir_ExtraOrMissingFileFails(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.txt": "hello\n", "extra.txt": "surprise\n"})
+	writeFiles(t, want, map[string]string{"a.txt": "hello\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.True(t, recorder.Failed())
+}
+
+func TestDir_ScrubbersNormalizeTimestampsAndUUIDs(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{
+		"report.txt": "generated at 2026-07-26T10:00:00Z id 4f8d6a3e-2b1c-4e9a-9f0d-1234567890ab\n",
+	})
+	writeFiles(t, want, map[string]string{
+		"report.txt": "generated at <TIMESTAMP> id <UUID>\n",
+	})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_CRLFIsNormalizedToLF(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.txt": "hello\r\nworld\r\n"})
+	writeFiles(t, want, map[string]string{"a.txt": "hello\nworld\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_YAMLKeyOrderIsIgnored(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"config.yaml": "b: 2\na: 1\n"})
+	writeFiles(t, want, map[string]string{"config.yaml": "a: 1\nb: 2\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_BinaryExtensionComparedByDigestNotDiffed(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"blob.gz": "\x1f\x8bnot really gzip but binary-ish"})
+	writeFiles(t, want, map[string]string{"blob.gz": "\x1f\x8bnot really gzip but binary-ish"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_WithMatcherOverridesDefault(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.custom": "anything"})
+	writeFiles(t, want, map[string]string{"a.custom": "different but ignored"})
+
+	var called bool
+	recorder := &testing.T{}
+	Dir(recorder, got, want, WithMatcher("*.custom", func(t *testing.T, path string, got, want []byte) {
+		called = true
+	}))
+	require.True(t, called)
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_WithIgnoreExcludesMatchedFiles(t *testing.T) {
+	got := t.TempDir()
+	want := t.TempDir()
+	writeFiles(t, got, map[string]string{"a.txt": "hello\n", "go.sum": "whatever go mod tidy produced this run\n"})
+	writeFiles(t, want, map[string]string{"a.txt": "hello\n"})
+
+	recorder := &testing.T{}
+	Dir(recorder, got, want, WithIgnore("go.sum"))
+	require.False(t, recorder.Failed())
+}
+
+func TestDir_MissingGoldenTreeSkips(t *testing.T) {
+	got := t.TempDir()
+	want := filepath.Join(t.TempDir(), "does-not-exist")
+	writeFiles(t, got, map[string]string{"a.txt": "hello\n"})
+
+	// Dir calls t.Skip on a wholly absent golden tree, which unwinds via
+	// runtime.Goexit -- that only behaves correctly against a real subtest's
+	// *testing.T (a bare &testing.T{} recorder isn't running in the subtest
+	// goroutine tRunner manages, so Goexit would abort this test itself
+	// instead of just marking the recorder skipped).
+	var sub *testing.T
+	t.Run("inner", func(st *testing.T) {
+		sub = st
+		Dir(st, got, want)
+	})
+	require.True(t, sub.Skipped())
+}
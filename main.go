@@ -13,6 +13,7 @@ func main() {
 	mixinName := flag.String("name", "", "Name of the new mixin")
 	authorName := flag.String("author", "", "Author name for the mixin")
 	outputDir := flag.String("output", "", "Output directory (defaults to ./{mixinName})")
+	porterVersionConstraint := flag.String("porter-version-constraint", ">=1.0.0, <2.0.0", "Semver constraint (Masterminds/semver/v3 syntax) for the Porter runtime versions this mixin supports, checked by Mixin.Lint")
 	flag.Parse()
 
 	if *mixinName == "" || *authorName == "" {
@@ -50,6 +51,7 @@ func main() {
 	// Replace skeletor with mixinName and YOURNAME with authorName
 	replaceInFiles(cleanedOutputDir, "skeletor", *mixinName)
 	replaceInFiles(cleanedOutputDir, "YOURNAME", *authorName)
+	replaceInFiles(cleanedOutputDir, ">=1.0.0, <2.0.0", *porterVersionConstraint)
 
 	// Rename directories, handle potential errors
 	cmdDirOld := filepath.Join(cleanedOutputDir, "cmd", "skeletor")
@@ -0,0 +1,12 @@
+package hooks
+
+// Builtins maps a hooks.yaml Hook.Name to the argv it runs when the hook
+// doesn't override Run, so a template author can write `name: go-mod-tidy`
+// instead of hardcoding the path to a go toolchain.
+var Builtins = map[string][]string{
+	"go-mod-tidy":      {"go", "mod", "tidy"},
+	"gofmt":            {"gofmt", "-l", "-w", "."},
+	"golangci-lint":    {"golangci-lint", "run", "--fix", "./..."},
+	"git-init":         {"git", "init"},
+	"goreleaser-check": {"goreleaser", "check"},
+}
@@ -0,0 +1,89 @@
+// Package hooks loads a template's hooks.yaml manifest -- a declarative
+// list of post-generation steps run in order after templating -- and
+// executes it, resolving each entry by name against a small set of
+// built-in implementations (see Builtins) or, for a name that isn't
+// built in, an executable a caller supplies via a plugin directory
+// (see Runner.PluginDirs), mirroring how pkg/plugin resolves a template's
+// pre_gen/post_gen/validate stages to an installed plugin's subprocess.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFilename is the file a template ships at its root, alongside
+// template.json, to declare its post-generation hook list.
+const ManifestFilename = "hooks.yaml"
+
+// Hook is one hooks.yaml entry. Name selects a Builtins implementation (or,
+// failing that, an executable by that name in one of Runner's PluginDirs);
+// Run overrides the underlying command a built-in or plugin hook would
+// otherwise use, for a hook author who wants the same selection/When/
+// Timeout/Env handling but a different command than the built-in default.
+type Hook struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run,omitempty"`
+	// When is a CEL-style boolean expression (see EvalWhen) over the
+	// generation's variable map, e.g. `ComplianceLevel == "slsa-l3"`; an
+	// empty When always runs.
+	When string `yaml:"when,omitempty"`
+	// Timeout is a time.ParseDuration string (e.g. "30s"); empty means no
+	// timeout, matching cmd/skeletor's HookStep.Timeout convention.
+	Timeout         string            `yaml:"timeout,omitempty"`
+	ContinueOnError bool              `yaml:"continue_on_error,omitempty"`
+	Env             map[string]string `yaml:"env,omitempty"`
+}
+
+// ParsedTimeout parses h.Timeout, returning zero (no timeout) for an empty
+// string.
+func (h Hook) ParsedTimeout() (time.Duration, error) {
+	if h.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("hook %q: invalid timeout %q: %w", h.Name, h.Timeout, err)
+	}
+	return d, nil
+}
+
+// manifest is hooks.yaml's top-level shape: a bare "hooks:" list, the same
+// shape template.json's own "hooks" object uses for each stage's list.
+type manifest struct {
+	Hooks []Hook `yaml:"hooks"`
+}
+
+// LoadManifest reads hooks.yaml from dir within fsys -- the same
+// (fs.FS, templateRoot) shape policy.LoadRules and LoadTemplateConfig use,
+// so hooks.yaml resolves the same way regardless of which template-source
+// backend (embed/dir/git/oci/http, see cmd/skeletor/templatesource.go) served the template --
+// and parses it into its declared hooks, in file order, the order Run
+// executes them in. A missing hooks.yaml returns a nil slice and no error,
+// since it's optional for any given template.
+func LoadManifest(fsys fs.FS, dir string) ([]Hook, error) {
+	manifestPath := path.Join(dir, ManifestFilename)
+	raw, err := fs.ReadFile(fsys, manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+	for i, h := range m.Hooks {
+		if h.Name == "" {
+			return nil, fmt.Errorf("%s: hook %d is missing a name", manifestPath, i)
+		}
+	}
+	return m.Hooks, nil
+}
@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hooks.yaml": &fstest.MapFile{Data: []byte(`
+hooks:
+  - name: go-mod-tidy
+  - name: lint
+    run: golangci-lint run --fix ./...
+    when: EnableLint
+    timeout: 30s
+    continue_on_error: true
+    env:
+      CGO_ENABLED: "0"
+`)},
+	}
+
+	got, err := LoadManifest(fsys, ".")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "go-mod-tidy", got[0].Name)
+
+	assert.Equal(t, "lint", got[1].Name)
+	assert.Equal(t, "golangci-lint run --fix ./...", got[1].Run)
+	assert.Equal(t, "EnableLint", got[1].When)
+	assert.True(t, got[1].ContinueOnError)
+	assert.Equal(t, "0", got[1].Env["CGO_ENABLED"])
+
+	timeout, err := got[1].ParsedTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, "30s", timeout.String())
+}
+
+func TestLoadManifest_MissingFileIsNotAnError(t *testing.T) {
+	got, err := LoadManifest(fstest.MapFS{}, ".")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestLoadManifest_HookMissingName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hooks.yaml": &fstest.MapFile{Data: []byte(`
+hooks:
+  - run: echo hi
+`)},
+	}
+
+	_, err := LoadManifest(fsys, ".")
+	require.Error(t, err)
+}
+
+func TestHook_ParsedTimeout_InvalidDuration(t *testing.T) {
+	h := Hook{Name: "lint", Timeout: "not-a-duration"}
+	_, err := h.ParsedTimeout()
+	require.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// Result is one Hook's outcome.
+type Result struct {
+	Name       string
+	Skipped    bool
+	ExitCode   int
+	StderrTail string
+}
+
+// stderrTailBytes bounds how much of a failed hook's stderr Result.StderrTail
+// keeps, matching cmd/skeletor's HookResult.StderrTail bound.
+const stderrTailBytes = 2048
+
+// Runner executes hooks.yaml's declared hooks against a generated project.
+type Runner struct {
+	// OutputDir is the generated project's root, both the hooks' working
+	// directory and what a plugin-dir hook sees as SKELETOR_OUTPUT_DIR.
+	OutputDir string
+	// PluginDirs is searched, in order, for an executable named after a
+	// Hook.Name that isn't in Builtins -- the same two-tier resolution
+	// pkg/plugin uses (installed plugin first, nothing found is an error),
+	// just scoped to hooks.yaml names instead of plugin.yaml manifests.
+	PluginDirs []string
+}
+
+// Run executes hooks in declared order against vars (the generation's
+// variable map, also what each Hook.When is evaluated against, and what a
+// plugin-dir hook receives as JSON on stdin), returning one Result per hook
+// it reached (a hook skipped by When gets one with Skipped set), plus the
+// combined error of every hook that failed, ContinueOnError or not. A
+// failing hook without ContinueOnError stops the remaining hooks from
+// running, as cmd/skeletor's RunHooksWithOptions does with --fail-fast:
+// everything before the failure still ran.
+func (r Runner) Run(ctx context.Context, hooks []Hook, vars map[string]interface{}) ([]Result, error) {
+	var results []Result
+	var combined error
+
+	for _, h := range hooks {
+		keep, err := EvalWhen(h.When, vars)
+		if err != nil {
+			return results, err
+		}
+		if !keep {
+			results = append(results, Result{Name: h.Name, Skipped: true})
+			continue
+		}
+
+		exitCode, stderrTail, runErr := r.runOne(ctx, h, vars)
+		results = append(results, Result{Name: h.Name, ExitCode: exitCode, StderrTail: stderrTail})
+		if runErr != nil {
+			combined = multierr.Append(combined, fmt.Errorf("hook %q failed: %w", h.Name, runErr))
+			if !h.ContinueOnError {
+				break
+			}
+		}
+	}
+	return results, combined
+}
+
+// runOne resolves h to a command (Run if set, else a Builtins entry, else a
+// PluginDirs executable) and runs it in r.OutputDir, honoring h.Timeout and
+// h.Env. A plugin-dir hook additionally gets vars marshaled as JSON on
+// stdin, the same subprocess protocol pkg/plugin.Plugin.Run uses for a
+// template's pre_gen/post_gen/validate stages.
+func (r Runner) runOne(ctx context.Context, h Hook, vars map[string]interface{}) (exitCode int, stderrTail string, err error) {
+	timeout, err := h.ParsedTimeout()
+	if err != nil {
+		return -1, "", err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	argv, isPlugin, err := r.resolve(h)
+	if err != nil {
+		return -1, "", err
+	}
+	if len(argv) == 0 {
+		return 0, "", nil
+	}
+
+	// #nosec G204 -- argv is either a built-in's fixed argument list, a
+	// hooks.yaml author's own Run command, or an executable resolved from a
+	// caller-supplied PluginDirs entry -- never attacker-controlled input.
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = r.OutputDir
+	cmd.Env = append(os.Environ(), "SKELETOR_OUTPUT_DIR="+r.OutputDir)
+	for k, v := range h.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	if isPlugin {
+		payload, marshalErr := json.Marshal(vars)
+		if marshalErr != nil {
+			return -1, "", fmt.Errorf("failed to encode hook variables: %w", marshalErr)
+		}
+		cmd.Stdin = bytes.NewReader(payload)
+	}
+	cmd.Stdout = os.Stdout
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderrBuf)
+
+	runErr := cmd.Run()
+	return exitCodeOf(runErr), tail(stderrBuf.String(), stderrTailBytes), runErr
+}
+
+// resolve turns h into the argv runOne execs, plus whether that argv is a
+// plugin-dir executable (and so expects vars on stdin): h.Run (split on
+// whitespace, no shell) takes precedence over Builtins[h.Name], which takes
+// precedence over a PluginDirs executable named h.Name.
+func (r Runner) resolve(h Hook) (argv []string, isPlugin bool, err error) {
+	if h.Run != "" {
+		return strings.Fields(h.Run), false, nil
+	}
+	if argv, ok := Builtins[h.Name]; ok {
+		return argv, false, nil
+	}
+	if path, ok := findPluginHook(r.PluginDirs, h.Name); ok {
+		return []string{path}, true, nil
+	}
+	return nil, false, fmt.Errorf("hook %q is not a built-in (%s) and wasn't found in any --hook-plugin-dir", h.Name, strings.Join(builtinNames(), ", "))
+}
+
+// findPluginHook looks for an executable file named name in each of dirs,
+// in order, returning the first match.
+func findPluginHook(dirs []string, name string) (string, bool) {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		return path, true
+	}
+	return "", false
+}
+
+// builtinNames lists Builtins' keys, for an error message that tells a
+// hooks.yaml author what names were actually available.
+func builtinNames() []string {
+	names := make([]string, 0, len(Builtins))
+	for name := range Builtins {
+		names = append(names, name)
+	}
+	return names
+}
+
+// exitCodeOf extracts a command's exit code from the error cmd.Run()
+// returned, or -1 if the process never started (or err is nil, in which
+// case the code is 0). Mirrors cmd/skeletor/hookexec.go's helper of the
+// same name -- pkg/hooks can't reach that one since it's unexported in a
+// different package.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// tail returns s's last n bytes, or all of s if it's shorter than that.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
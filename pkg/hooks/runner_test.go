@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutableScript(t *testing.T, dir, name, script string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700))
+}
+
+func TestRunner_Run_SkipsWhenFalse(t *testing.T) {
+	outputDir := t.TempDir()
+	r := Runner{OutputDir: outputDir}
+
+	results, err := r.Run(context.Background(), []Hook{
+		{Name: "go-mod-tidy", When: "false"},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Skipped)
+}
+
+func TestRunner_Run_UnknownHookFails(t *testing.T) {
+	r := Runner{OutputDir: t.TempDir()}
+
+	_, err := r.Run(context.Background(), []Hook{{Name: "does-not-exist"}}, nil)
+	require.Error(t, err)
+}
+
+func TestRunner_Run_ContinueOnError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell fixtures assume a POSIX shell")
+	}
+	scriptDir := t.TempDir()
+	writeExecutableScript(t, scriptDir, "fail.sh", "#!/bin/sh\nexit 1\n")
+
+	r := Runner{OutputDir: t.TempDir()}
+	results, err := r.Run(context.Background(), []Hook{
+		{Name: "fails", Run: filepath.Join(scriptDir, "fail.sh"), ContinueOnError: true},
+		{Name: "succeeds", Run: "true"},
+	}, nil)
+	require.Error(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 1, results[0].ExitCode)
+	assert.Equal(t, 0, results[1].ExitCode)
+}
+
+func TestRunner_Run_StopsOnErrorWithoutContinueOnError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell fixtures assume a POSIX shell")
+	}
+	scriptDir := t.TempDir()
+	writeExecutableScript(t, scriptDir, "fail.sh", "#!/bin/sh\nexit 1\n")
+
+	r := Runner{OutputDir: t.TempDir()}
+	results, err := r.Run(context.Background(), []Hook{
+		{Name: "fails", Run: filepath.Join(scriptDir, "fail.sh")},
+		{Name: "never-runs", Run: "true"},
+	}, nil)
+	require.Error(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestRunner_Run_PluginDirExecutesWithVarsOnStdinAndOutputDirEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell fixtures assume a POSIX shell")
+	}
+	pluginDir := t.TempDir()
+	writeExecutableScript(t, pluginDir, "sbom-scan", "#!/bin/sh\ncat > \"$SKELETOR_OUTPUT_DIR/vars.json\"\n")
+
+	outputDir := t.TempDir()
+	r := Runner{OutputDir: outputDir, PluginDirs: []string{pluginDir}}
+
+	_, err := r.Run(context.Background(), []Hook{{Name: "sbom-scan"}}, map[string]interface{}{"MixinName": "foo"})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(filepath.Join(outputDir, "vars.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"MixinName":"foo"}`, string(got))
+}
@@ -0,0 +1,286 @@
+package hooks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EvalWhen evaluates a hooks.yaml Hook.When expression against vars and
+// reports whether the hook should run. The grammar is a small subset of
+// CEL: identifiers resolve against vars (stringified for comparison),
+// string/bool/number literals, ==, !=, unary !, && and || (|| binds
+// loosest), and parenthesized subexpressions -- e.g.
+// `ComplianceLevel == "slsa-l3" && EnableCI`. An empty expression always
+// evaluates true.
+func EvalWhen(expr string, vars map[string]interface{}) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+
+	toks, err := tokenizeWhen(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid \"when\" expression %q: %w", expr, err)
+	}
+	p := &whenParser{tokens: toks, vars: vars}
+	val, err := p.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("invalid \"when\" expression %q: %w", expr, err)
+	}
+	if !p.atEnd() {
+		return false, fmt.Errorf("invalid \"when\" expression %q: unexpected %q", expr, p.peek().text)
+	}
+	return truthy(val), nil
+}
+
+type whenTokenKind int
+
+const (
+	tokIdent whenTokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type whenToken struct {
+	kind whenTokenKind
+	text string
+}
+
+// tokenizeWhen lexes expr into idents/literals/operators/parens, the only
+// vocabulary EvalWhen's grammar needs.
+func tokenizeWhen(expr string) ([]whenToken, error) {
+	var toks []whenToken
+	runes := []rune(expr)
+	i := 0
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			toks = append(toks, whenToken{tokLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, whenToken{tokRParen, ")"})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			toks = append(toks, whenToken{tokString, string(runes[i+1 : j])})
+			i = j + 1
+		case strings.HasPrefix(string(runes[i:]), "=="):
+			toks = append(toks, whenToken{tokOp, "=="})
+			i += 2
+		case strings.HasPrefix(string(runes[i:]), "!="):
+			toks = append(toks, whenToken{tokOp, "!="})
+			i += 2
+		case strings.HasPrefix(string(runes[i:]), "&&"):
+			toks = append(toks, whenToken{tokOp, "&&"})
+			i += 2
+		case strings.HasPrefix(string(runes[i:]), "||"):
+			toks = append(toks, whenToken{tokOp, "||"})
+			i += 2
+		case c == '!':
+			toks = append(toks, whenToken{tokOp, "!"})
+			i++
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(runes) && isIdentPart(runes[j]) {
+				j++
+			}
+			toks = append(toks, whenToken{tokIdent, string(runes[i:j])})
+			i = j
+		case c >= '0' && c <= '9' || c == '-':
+			j := i + 1
+			for j < len(runes) && (runes[j] >= '0' && runes[j] <= '9' || runes[j] == '.') {
+				j++
+			}
+			toks = append(toks, whenToken{tokNumber, string(runes[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return toks, nil
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c rune) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '.'
+}
+
+// whenParser is a recursive-descent parser over tokenizeWhen's output,
+// lowest to highest precedence: || , && , ==/!= , unary ! , atom.
+type whenParser struct {
+	tokens []whenToken
+	pos    int
+	vars   map[string]interface{}
+}
+
+func (p *whenParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *whenParser) peek() whenToken {
+	if p.atEnd() {
+		return whenToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *whenParser) next() whenToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *whenParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == tokOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) || truthy(right)
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseAnd() (interface{}, error) {
+	left, err := p.parseEquality()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == tokOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseEquality()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) && truthy(right)
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseEquality() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == tokOp && (p.peek().text == "==" || p.peek().text == "!=") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		eq := stringify(left) == stringify(right)
+		if op == "==" {
+			left = eq
+		} else {
+			left = !eq
+		}
+	}
+	return left, nil
+}
+
+func (p *whenParser) parseUnary() (interface{}, error) {
+	if !p.atEnd() && p.peek().kind == tokOp && p.peek().text == "!" {
+		p.next()
+		val, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(val), nil
+	}
+	return p.parseAtom()
+}
+
+func (p *whenParser) parseAtom() (interface{}, error) {
+	if p.atEnd() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		val, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.atEnd() || p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.next()
+		return val, nil
+	case tokString:
+		return tok.text, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return n, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return p.vars[tok.text], nil
+	default:
+		return nil, fmt.Errorf("unexpected token %q", tok.text)
+	}
+}
+
+// truthy mirrors evaluateHookWhen's Go-template-based truthiness so a
+// hooks.yaml author sees the same "empty/false/0 is false" rule regardless
+// of which When dialect a given hook uses.
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != "" && val != "false" && val != "0"
+	case float64:
+		return val != 0
+	case int:
+		return val != 0
+	default:
+		return true
+	}
+}
+
+// stringify renders v the way == compares it: bools and numbers use their
+// natural string form, so `ComplianceLevel == "slsa-l3"` and
+// `EnableCI == true` both compare as a human would expect.
+func stringify(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
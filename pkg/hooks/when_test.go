@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalWhen(t *testing.T) {
+	vars := map[string]interface{}{
+		"ComplianceLevel": "slsa-l3",
+		"EnableCI":        true,
+		"Retries":         0.0,
+		"IntRetries":      0,
+		"IntAttempts":     3,
+	}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{`ComplianceLevel == "slsa-l3"`, true},
+		{`ComplianceLevel == "basic"`, false},
+		{`ComplianceLevel != "basic"`, true},
+		{"EnableCI", true},
+		{"!EnableCI", false},
+		{"Retries", false},
+		{"IntRetries", false},
+		{"!IntRetries", true},
+		{"IntAttempts", true},
+		{`IntAttempts == "3"`, true},
+		{`ComplianceLevel == "slsa-l3" && EnableCI`, true},
+		{`ComplianceLevel == "basic" || EnableCI`, true},
+		{`ComplianceLevel == "basic" || !EnableCI`, false},
+		{`(ComplianceLevel == "slsa-l3") && (!Retries)`, true},
+		{"Missing", false},
+		{"true", true},
+		{"false", false},
+	}
+
+	for _, c := range cases {
+		got, err := EvalWhen(c.expr, vars)
+		require.NoError(t, err, c.expr)
+		assert.Equal(t, c.want, got, c.expr)
+	}
+}
+
+func TestEvalWhen_InvalidExpression(t *testing.T) {
+	cases := []string{
+		`ComplianceLevel ==`,
+		`(EnableCI`,
+		`EnableCI)`,
+		`"unterminated`,
+		`EnableCI &&& true`,
+	}
+	for _, expr := range cases {
+		_, err := EvalWhen(expr, nil)
+		require.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,162 @@
+// Package plugin discovers and runs skeletor plugins: subdirectories of a
+// plugin root carrying a plugin.yaml manifest plus an executable, modeled on
+// Helm's FindPlugins/LoadAll. A plugin extends createMixin's generation
+// hooks (pre_gen, post_gen, validate) without the user having to fork or
+// patch the template itself.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFilename is the file each plugin directory must carry.
+const manifestFilename = "plugin.yaml"
+
+// Metadata is one plugin.yaml's contents.
+type Metadata struct {
+	Name    string   `yaml:"name"`
+	Version string   `yaml:"version"`
+	Command string   `yaml:"command"`
+	Hooks   []string `yaml:"hooks"` // any of "pre_gen", "post_gen", "validate"
+}
+
+// Plugin is one loaded plugin: its manifest plus the directory it was
+// loaded from, which Command is resolved relative to and Run executes in.
+type Plugin struct {
+	Metadata Metadata
+	Dir      string
+}
+
+// HasHook reports whether p declared stage ("pre_gen", "post_gen" or
+// "validate") in its manifest's hooks list.
+func (p Plugin) HasHook(stage string) bool {
+	for _, h := range p.Metadata.Hooks {
+		if h == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes p.Metadata.Command with stage as its one argument -- so a
+// single command can dispatch across every stage it declared in hooks --
+// piping data as JSON on stdin and setting SKELETOR_OUTPUT_DIR in its
+// environment. Command is resolved relative to p.Dir unless already
+// absolute, and runs with p.Dir as its working directory.
+func (p Plugin) Run(ctx context.Context, stage, outputDir string, data interface{}) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q: failed to encode template data: %w", p.Metadata.Name, err)
+	}
+
+	executable := p.Metadata.Command
+	if !filepath.IsAbs(executable) {
+		executable = filepath.Join(p.Dir, executable)
+	}
+
+	// #nosec G204 -- executable comes from a plugin.yaml the user explicitly installed, not arbitrary input
+	cmd := exec.CommandContext(ctx, executable, stage)
+	cmd.Dir = p.Dir
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), "SKELETOR_OUTPUT_DIR="+outputDir)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("plugin %q %s hook failed: %w: %s", p.Metadata.Name, stage, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// LoadPlugin reads dir/plugin.yaml and validates its required fields.
+func LoadPlugin(dir string) (Plugin, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, manifestFilename)) // #nosec G304 -- dir comes from scanning a trusted plugin root, not arbitrary input
+	if err != nil {
+		return Plugin{}, fmt.Errorf("failed to read %s: %w", filepath.Join(dir, manifestFilename), err)
+	}
+
+	var meta Metadata
+	if err := yaml.Unmarshal(raw, &meta); err != nil {
+		return Plugin{}, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, manifestFilename), err)
+	}
+	if meta.Name == "" {
+		return Plugin{}, fmt.Errorf("%s is missing a name", filepath.Join(dir, manifestFilename))
+	}
+	if meta.Command == "" {
+		return Plugin{}, fmt.Errorf("%s is missing a command", filepath.Join(dir, manifestFilename))
+	}
+	return Plugin{Metadata: meta, Dir: dir}, nil
+}
+
+// FindPlugins scans each of dirs (a plugin root, e.g. ~/.skeletor/plugins,
+// not a plugin directory itself) for immediate subdirectories carrying a
+// plugin.yaml, mirroring Helm's FindPlugins. A root that doesn't exist is
+// skipped rather than treated as an error, since most of PluginDirs' search
+// path is optional.
+func FindPlugins(dirs []string) ([]Plugin, error) {
+	var plugins []Plugin
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			pluginDir := filepath.Join(dir, entry.Name())
+			if _, err := os.Stat(filepath.Join(pluginDir, manifestFilename)); err != nil {
+				continue // Not every subdirectory is necessarily a plugin
+			}
+			p, err := LoadPlugin(pluginDir)
+			if err != nil {
+				return nil, err
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins, nil
+}
+
+// PluginDirs returns LoadAll's search path, in order: each colon-separated
+// (semicolon on Windows, per filepath.SplitList) entry of $SKELETOR_PLUGINS,
+// then ~/.skeletor/plugins.
+func PluginDirs() []string {
+	var dirs []string
+	if envDirs := os.Getenv("SKELETOR_PLUGINS"); envDirs != "" {
+		dirs = append(dirs, filepath.SplitList(envDirs)...)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".skeletor", "plugins"))
+	}
+	return dirs
+}
+
+// LoadAll finds every plugin across PluginDirs().
+func LoadAll() ([]Plugin, error) {
+	return FindPlugins(PluginDirs())
+}
+
+// DefaultInstallDir is where `skeletor plugin install` copies or clones a
+// plugin to, the last (and always-present) entry of PluginDirs' search path.
+func DefaultInstallDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory for plugin install: %w", err)
+	}
+	return filepath.Join(home, ".skeletor", "plugins"), nil
+}
@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestPlugin(t *testing.T, dir, manifest, script string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, manifestFilename), []byte(manifest), 0o600))
+	if script != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "run.sh"), []byte(script), 0o700))
+	}
+}
+
+func TestLoadPlugin(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "name: sbom\nversion: 0.1.0\ncommand: ./run.sh\nhooks: [post_gen]\n", "#!/bin/sh\nexit 0\n")
+
+	p, err := LoadPlugin(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "sbom", p.Metadata.Name)
+	assert.True(t, p.HasHook("post_gen"))
+	assert.False(t, p.HasHook("pre_gen"))
+}
+
+func TestLoadPlugin_MissingName(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "command: ./run.sh\n", "")
+	_, err := LoadPlugin(dir)
+	require.Error(t, err)
+}
+
+func TestLoadPlugin_MissingCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "name: sbom\n", "")
+	_, err := LoadPlugin(dir)
+	require.Error(t, err)
+}
+
+func TestFindPlugins_SkipsNonPluginSubdirsAndMissingRoots(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sbom"), 0o750))
+	writeTestPlugin(t, filepath.Join(root, "sbom"), "name: sbom\nversion: 0.1.0\ncommand: ./run.sh\n", "")
+	require.NoError(t, os.Mkdir(filepath.Join(root, "not-a-plugin"), 0o750))
+
+	plugins, err := FindPlugins([]string{root, filepath.Join(root, "does-not-exist")})
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "sbom", plugins[0].Metadata.Name)
+}
+
+func TestPluginDirs_IncludesEnvAndHome(t *testing.T) {
+	t.Setenv("SKELETOR_PLUGINS", "/a"+string(os.PathListSeparator)+"/b")
+	dirs := PluginDirs()
+	require.GreaterOrEqual(t, len(dirs), 3)
+	assert.Equal(t, "/a", dirs[0])
+	assert.Equal(t, "/b", dirs[1])
+}
+
+func TestPlugin_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin fixture assumes a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "name: echoer\nversion: 0.1.0\ncommand: ./run.sh\nhooks: [post_gen]\n",
+		"#!/bin/sh\ncat > /dev/null\necho \"$SKELETOR_OUTPUT_DIR:$1\"\n")
+
+	p, err := LoadPlugin(dir)
+	require.NoError(t, err)
+
+	out, err := p.Run(context.Background(), "post_gen", "/tmp/out", map[string]string{"MixinName": "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/out:post_gen\n", string(out))
+}
+
+func TestPlugin_Run_PipesDataAsJSON(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin fixture assumes a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "name: reader\nversion: 0.1.0\ncommand: ./run.sh\nhooks: [post_gen]\n",
+		"#!/bin/sh\ncat\n")
+
+	p, err := LoadPlugin(dir)
+	require.NoError(t, err)
+
+	data := map[string]string{"MixinName": "foo"}
+	out, err := p.Run(context.Background(), "post_gen", "/tmp/out", data)
+	require.NoError(t, err)
+
+	var decoded map[string]string
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, data, decoded)
+}
@@ -0,0 +1,11 @@
+package policy
+
+import "embed"
+
+// BuiltinRulesFS bundles policy/builtin's policy.yaml manifest and its .rego
+// modules -- the default SOC2/GDPR/HIPAA compliance rules that, before this
+// package existed, were implicit in ValidateComplianceLevel and scattered
+// enforcementFor lookups. Load them with LoadRules(BuiltinRulesFS, "builtin").
+//
+//go:embed builtin
+var BuiltinRulesFS embed.FS
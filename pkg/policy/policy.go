@@ -0,0 +1,190 @@
+// Package policy evaluates user-supplied Rego modules against a generation
+// run's inputs and outputs, generalizing the generator's built-in
+// ValidateComplianceLevel/ValidateMixinName checks into rules a team can
+// author and extend without patching the generator itself.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/open-policy-agent/opa/rego"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+// ScopedAction is a rule's declared response to a violation: deny aborts
+// generation, warn prints and continues, and dryrun only records the
+// violation in a report for later review.
+type ScopedAction string
+
+const (
+	ActionDeny   ScopedAction = "deny"
+	ActionWarn   ScopedAction = "warn"
+	ActionDryRun ScopedAction = "dryrun"
+)
+
+// manifestFilename is the YAML manifest every rule directory must carry,
+// pairing each .rego module with the scoped_actions it was authored for --
+// kept out of the Rego source itself so scoped_actions stays structured data
+// rather than a convention buried in a comment.
+const manifestFilename = "policy.yaml"
+
+// manifestEntry is one policy.yaml row.
+type manifestEntry struct {
+	Name          string   `yaml:"name"`
+	File          string   `yaml:"file"`
+	ScopedActions []string `yaml:"scoped_actions"`
+}
+
+// Rule is one loaded Rego module plus the scoped_actions it was declared
+// with. Its module must define a `deny` rule under `package skeletor.policy`
+// producing a set (or array) of violation message strings; an empty deny
+// result means the rule found nothing to report.
+type Rule struct {
+	Name          string
+	Module        string
+	Source        string // .rego module path the rule was loaded from, for error messages
+	ScopedActions []ScopedAction
+}
+
+// action returns rule's primary scoped action, defaulting to deny (fail
+// closed) when a rule doesn't declare one.
+func (r Rule) action() ScopedAction {
+	if len(r.ScopedActions) == 0 {
+		return ActionDeny
+	}
+	return r.ScopedActions[0]
+}
+
+// LoadRules reads dir/policy.yaml from fsys and the .rego module each entry
+// names, returning one Rule per entry in manifest order.
+func LoadRules(fsys fs.FS, dir string) ([]Rule, error) {
+	manifestPath := path.Join(dir, manifestFilename)
+	raw, err := fs.ReadFile(fsys, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	var entries []manifestEntry
+	if err := yaml.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+
+	rules := make([]Rule, 0, len(entries))
+	for _, entry := range entries {
+		modulePath := path.Join(dir, entry.File)
+		module, err := fs.ReadFile(fsys, modulePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rego module %s (declared in %s): %w", modulePath, manifestPath, err)
+		}
+		actions := make([]ScopedAction, 0, len(entry.ScopedActions))
+		for _, a := range entry.ScopedActions {
+			actions = append(actions, ScopedAction(a))
+		}
+		rules = append(rules, Rule{
+			Name:          entry.Name,
+			Module:        string(module),
+			Source:        modulePath,
+			ScopedActions: actions,
+		})
+	}
+	return rules, nil
+}
+
+// Violation is one rule's deny message, tagged with the rule's scoped
+// action so a caller can separate aborts from warnings from dry-run notes.
+type Violation struct {
+	Rule    string
+	Action  ScopedAction
+	Message string
+}
+
+// Evaluator runs a fixed set of Rules against a gate's input, collecting
+// every violation rather than stopping at the first -- a team should see
+// every policy problem a generation run hit in one pass, not one at a time.
+type Evaluator struct {
+	rules []Rule
+}
+
+// NewEvaluator returns an Evaluator for rules.
+func NewEvaluator(rules []Rule) *Evaluator {
+	return &Evaluator{rules: rules}
+}
+
+// Evaluate runs every rule's `data.skeletor.policy.deny` against input and
+// returns one Violation per message any rule's deny set produced.
+func (e *Evaluator) Evaluate(ctx context.Context, input map[string]interface{}) ([]Violation, error) {
+	var violations []Violation
+	var errs error
+	for _, rule := range e.rules {
+		messages, err := evaluateDeny(ctx, rule, input)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("policy rule %q (%s): %w", rule.Name, rule.Source, err))
+			continue
+		}
+		for _, msg := range messages {
+			violations = append(violations, Violation{Rule: rule.Name, Action: rule.action(), Message: msg})
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return violations, nil
+}
+
+func evaluateDeny(ctx context.Context, rule Rule, input map[string]interface{}) ([]string, error) {
+	query, err := rego.New(
+		rego.Query("data.skeletor.policy.deny"),
+		rego.Module(rule.Name+".rego", rule.Module),
+		rego.Input(input),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rego module: %w", err)
+	}
+
+	results, err := query.Eval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation failed: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, nil
+	}
+
+	switch denied := results[0].Expressions[0].Value.(type) {
+	case []interface{}:
+		return toStrings(denied), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("deny rule did not evaluate to a set or array of strings, got %T", denied)
+	}
+}
+
+func toStrings(values []interface{}) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Partition splits violations by scoped action, for a caller that wants to
+// abort on deny, print warn, and persist dryrun separately.
+func Partition(violations []Violation) (deny, warn, dryRun []Violation) {
+	for _, v := range violations {
+		switch v.Action {
+		case ActionWarn:
+			warn = append(warn, v)
+		case ActionDryRun:
+			dryRun = append(dryRun, v)
+		default:
+			deny = append(deny, v)
+		}
+	}
+	return deny, warn, dryRun
+}
@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRules(t *testing.T) {
+	fsys := fstest.MapFS{
+		"rules/policy.yaml": &fstest.MapFile{Data: []byte(`
+- name: no_retired_flags
+  file: no_retired.rego
+  scoped_actions: ["deny"]
+`)},
+		"rules/no_retired.rego": &fstest.MapFile{Data: []byte(`package skeletor.policy
+
+deny[msg] {
+	input.Retired
+	msg := "retired flags must not be enabled"
+}
+`)},
+	}
+
+	rules, err := LoadRules(fsys, "rules")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "no_retired_flags", rules[0].Name)
+	assert.Equal(t, []ScopedAction{ActionDeny}, rules[0].ScopedActions)
+}
+
+func TestLoadRules_MissingManifest(t *testing.T) {
+	_, err := LoadRules(fstest.MapFS{}, "rules")
+	require.Error(t, err)
+}
+
+func TestEvaluator_CollectsAllViolations(t *testing.T) {
+	rules := []Rule{
+		{
+			Name:          "rule_a",
+			ScopedActions: []ScopedAction{ActionDeny},
+			Module: `package skeletor.policy
+
+deny[msg] {
+	input.TriggerA
+	msg := "violation from rule_a"
+}
+`,
+		},
+		{
+			Name:          "rule_b",
+			ScopedActions: []ScopedAction{ActionWarn},
+			Module: `package skeletor.policy
+
+deny[msg] {
+	input.TriggerB
+	msg := "violation from rule_b"
+}
+`,
+		},
+	}
+
+	evaluator := NewEvaluator(rules)
+	violations, err := evaluator.Evaluate(context.Background(), map[string]interface{}{
+		"TriggerA": true,
+		"TriggerB": true,
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+
+	deny, warn, dryRun := Partition(violations)
+	require.Len(t, deny, 1)
+	require.Len(t, warn, 1)
+	assert.Empty(t, dryRun)
+	assert.Equal(t, "violation from rule_a", deny[0].Message)
+	assert.Equal(t, "violation from rule_b", warn[0].Message)
+}
+
+func TestEvaluator_NoViolationsWhenInputDoesNotMatch(t *testing.T) {
+	rules := []Rule{{
+		Name:          "rule_a",
+		ScopedActions: []ScopedAction{ActionDeny},
+		Module: `package skeletor.policy
+
+deny[msg] {
+	input.TriggerA
+	msg := "violation from rule_a"
+}
+`,
+	}}
+
+	violations, err := NewEvaluator(rules).Evaluate(context.Background(), map[string]interface{}{"TriggerA": false})
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestBuiltinRules_SOC2RequiresMFA(t *testing.T) {
+	rules, err := LoadRules(BuiltinRulesFS, "builtin")
+	require.NoError(t, err)
+	require.NotEmpty(t, rules)
+
+	violations, err := NewEvaluator(rules).Evaluate(context.Background(), map[string]interface{}{
+		"ComplianceFrameworks":  "soc2",
+		"EnableAuth":            true,
+		"AuthFeatures":          "rbac",
+		"EnableObservability":   true,
+		"ObservabilityFeatures": "audit_logging",
+		"EnableSecurity":        true,
+	})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, "mfa")
+}
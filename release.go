@@ -0,0 +1,394 @@
+//go:build mage
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"get.porter.sh/magefiles/releases"
+)
+
+// releaseVersion resolves the version being published: GITHUB_REF_NAME in CI
+// (set by the tag-push workflow), falling back to `git describe` so Sign and
+// Verify behave the same way run locally.
+func releaseVersion() (string, error) {
+	if v := os.Getenv("GITHUB_REF_NAME"); v != "" {
+		return v, nil
+	}
+	out, err := exec.Command("git", "describe", "--tags", "--always").Output() // #nosec G204 -- fixed command and args
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve release version: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// releaseAssetDir is where XBuildAll's cross-compiled binaries for version land.
+func releaseAssetDir(version string) string {
+	return filepath.Join(mixinBin, version)
+}
+
+// releaseAssets lists the binaries in dir that getReleaseAssets (see the
+// magefiles release library) publishes: everything named
+// "<mixinName>-<os>-<arch>[.exe]", skipping the checksum/signature/provenance
+// sidecars this file generates alongside them.
+func releaseAssets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var assets []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasPrefix(name, mixinName+"-") {
+			continue
+		}
+		switch filepath.Ext(name) {
+		case ".sha256sum", ".sig", ".pem", ".jsonl":
+			continue
+		}
+		assets = append(assets, name)
+	}
+	sort.Strings(assets)
+	return assets, nil
+}
+
+// sha256File hashes path and returns its hex digest.
+func sha256File(path string) (string, error) {
+	// #nosec G304 -- path is derived from releaseAssets walking a controlled build output directory
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// generateChecksums (re)writes a "<sha256>  <name>" sidecar -- the format
+// sha256sum(1) and getReleaseAssets both expect -- for every release asset
+// in dir.
+func generateChecksums(dir string) error {
+	assets, err := releaseAssets(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range assets {
+		sum, err := sha256File(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", name, err)
+		}
+		sidecar := filepath.Join(dir, name+".sha256sum")
+		line := fmt.Sprintf("%s  %s\n", sum, name)
+		if err := os.WriteFile(sidecar, []byte(line), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", sidecar, err)
+		}
+	}
+	fmt.Printf("--> Generated %d sha256sum sidecars in %s\n", len(assets), dir)
+	return nil
+}
+
+// keylessSigningReady reports whether the environment looks like it can do
+// cosign's keyless OIDC signing: COSIGN_EXPERIMENTAL opted in, plus the
+// ambient GitHub Actions OIDC token endpoint cosign's keyless flow reads from.
+func keylessSigningReady() bool {
+	return os.Getenv("COSIGN_EXPERIMENTAL") == "1" && os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != ""
+}
+
+// signAssets runs `cosign sign-blob` against every release asset in dir,
+// producing a detached <asset>.sig signature and <asset>.pem certificate.
+func signAssets(dir string) error {
+	assets, err := releaseAssets(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range assets {
+		assetPath := filepath.Join(dir, name)
+		fmt.Printf("  --> Signing %s...\n", name)
+		cmd := exec.Command("cosign", "sign-blob", // #nosec G204 -- fixed command, args are sidecar paths under a controlled build output directory
+			"--yes",
+			"--output-signature", assetPath+".sig",
+			"--output-certificate", assetPath+".pem",
+			assetPath,
+		)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("cosign sign-blob failed for %s: %w", name, err)
+		}
+	}
+	fmt.Printf("--> Signed %d release assets in %s\n", len(assets), dir)
+	return nil
+}
+
+// Sign keylessly signs every binary XBuildAll produced for the current
+// release version with cosign, provided COSIGN_EXPERIMENTAL=1 and ambient
+// OIDC credentials are present; otherwise it logs and no-ops, so `mage sign`
+// is always safe to run locally.
+func Sign() error {
+	if !keylessSigningReady() {
+		fmt.Println("Skipping cosign signing: set COSIGN_EXPERIMENTAL=1 and run with GitHub Actions OIDC credentials to enable.")
+		return nil
+	}
+	version, err := releaseVersion()
+	if err != nil {
+		return err
+	}
+	return signAssets(releaseAssetDir(version))
+}
+
+// provenanceStatement is the in-toto v1 Statement layer this file attaches
+// to the binaries XBuildAll produces, mirroring the shape ci/main.go's
+// Dagger release pipeline generates for goreleaser's assets.
+type provenanceStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []provenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     provenancePredicate `json:"predicate"`
+}
+
+type provenanceSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type provenancePredicate struct {
+	BuildType       string   `json:"buildType"`
+	Builder         string   `json:"builder"`
+	Commit          string   `json:"commit"`
+	Materials       []string `json:"materials"`
+	BuildFinishedOn string   `json:"buildFinishedOn"`
+}
+
+// gitCommit resolves the commit being released.
+func gitCommit() (string, error) {
+	out, err := exec.Command("git", "log", "-1", "--format=%H").Output() // #nosec G204 -- fixed command and args
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit sha: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// generateProvenance writes an in-toto SLSA v1.0 provenance document
+// summarizing dir's release assets (build inputs: commit, builder,
+// materials) to dir/<version>_<mixinName>.intoto.jsonl, attached to the GitHub release
+// alongside the binaries by PublishBinaries.
+func generateProvenance(dir, version string) error {
+	assets, err := releaseAssets(dir)
+	if err != nil {
+		return err
+	}
+	commit, err := gitCommit()
+	if err != nil {
+		return err
+	}
+
+	subjects := make([]provenanceSubject, 0, len(assets))
+	for _, name := range assets {
+		sum, err := sha256File(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s for provenance: %w", name, err)
+		}
+		subjects = append(subjects, provenanceSubject{Name: name, Digest: map[string]string{"sha256": sum}})
+	}
+
+	statement := provenanceStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       subjects,
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate: provenancePredicate{
+			BuildType:       "https://github.com/mchorfa/porter-skeletor/mage@v1",
+			Builder:         mixinPackage,
+			Commit:          commit,
+			Materials:       []string{"git+https://github.com/mchorfa/porter-skeletor@" + commit},
+			BuildFinishedOn: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal provenance statement: %w", err)
+	}
+
+	path := filepath.Join(dir, version+"_"+mixinName+".intoto.jsonl")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("--> Wrote SLSA provenance for %d assets to %s\n", len(assets), path)
+	return nil
+}
+
+// dryRunReleasePipeline copies version's built release assets into a scratch
+// directory and exercises checksum, signing (when cosign/OIDC are ambient),
+// and provenance generation against the copy, leaving the real bin/ output
+// untouched. TestPublish calls this before talking to GitHub at all.
+func dryRunReleasePipeline(version string) error {
+	srcDir := releaseAssetDir(version)
+	assets, err := releaseAssets(srcDir)
+	if err != nil {
+		return fmt.Errorf("failed to list release assets in %s (run mage XBuildAll first): %w", srcDir, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "publish-dryrun-"+mixinName+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create dry-run directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, name := range assets {
+		data, err := os.ReadFile(filepath.Join(srcDir, name)) // #nosec G304 -- path comes from releaseAssets walking a controlled build output directory
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, name), data, 0600); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", name, err)
+		}
+	}
+
+	if err := generateChecksums(tmpDir); err != nil {
+		return err
+	}
+	if keylessSigningReady() {
+		if err := signAssets(tmpDir); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("  --> Skipping cosign dry run: COSIGN_EXPERIMENTAL/OIDC credentials not ambient")
+	}
+	if err := generateProvenance(tmpDir, version); err != nil {
+		return err
+	}
+
+	fmt.Printf("--> Release pipeline dry run succeeded for %d assets\n", len(assets))
+	return nil
+}
+
+// downloadFile fetches url and writes its body to dest.
+func downloadFile(url, dest string) error {
+	resp, err := http.Get(url) // #nosec G107 -- url is assembled from the configured release repository, not arbitrary user input
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	out, err := os.Create(dest) // #nosec G304 -- dest is a path under a freshly created temp verification directory
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+// verifyChecksum recomputes name's sha256 in dir and compares it against its
+// downloaded .sha256sum sidecar.
+func verifyChecksum(dir, name string) error {
+	sidecar, err := os.ReadFile(filepath.Join(dir, name+".sha256sum")) // #nosec G304 -- path is under a controlled temp verification directory
+	if err != nil {
+		return fmt.Errorf("failed to read checksum sidecar for %s: %w", name, err)
+	}
+	wantFields := strings.Fields(string(sidecar))
+	if len(wantFields) == 0 {
+		return fmt.Errorf("malformed checksum sidecar for %s", name)
+	}
+	gotSum, err := sha256File(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to checksum downloaded %s: %w", name, err)
+	}
+	if gotSum != wantFields[0] {
+		return fmt.Errorf("checksum mismatch for %s: want %s, got %s", name, wantFields[0], gotSum)
+	}
+	return nil
+}
+
+// verifySignature runs `cosign verify-blob` against name's downloaded
+// signature and certificate sidecars.
+func verifySignature(dir, name string) error {
+	assetPath := filepath.Join(dir, name)
+	cmd := exec.Command("cosign", "verify-blob", // #nosec G204 -- fixed command, args are sidecar paths under a controlled temp directory
+		"--signature", assetPath+".sig",
+		"--certificate", assetPath+".pem",
+		"--certificate-identity-regexp", ".*",
+		"--certificate-oidc-issuer-regexp", ".*",
+		assetPath,
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cosign verify-blob failed for %s: %w", name, err)
+	}
+	return nil
+}
+
+// Verify downloads version's published release assets from
+// PORTER_RELEASE_REPOSITORY and validates every binary's checksum (and,
+// when cosign/OIDC are ambient, its signature) end to end -- the same checks
+// a consumer's install implicitly relies on.
+func Verify(version string) error {
+	repo := os.Getenv(releases.ReleaseRepository)
+	if repo == "" {
+		return fmt.Errorf("%s must be set to the published release repository to verify", releases.ReleaseRepository)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "verify-"+mixinName+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp verification directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	assets, err := releaseAssets(releaseAssetDir(version))
+	if err != nil {
+		return err
+	}
+
+	baseURL := fmt.Sprintf("https://%s/releases/download/%s", repo, version)
+	signing := keylessSigningReady()
+	for _, name := range assets {
+		suffixes := []string{"", ".sha256sum"}
+		if signing {
+			suffixes = append(suffixes, ".sig", ".pem")
+		}
+		for _, suffix := range suffixes {
+			assetURL := baseURL + "/" + name + suffix
+			dest := filepath.Join(tmpDir, name+suffix)
+			if err := downloadFile(assetURL, dest); err != nil {
+				return fmt.Errorf("failed to download %s: %w", assetURL, err)
+			}
+		}
+
+		if err := verifyChecksum(tmpDir, name); err != nil {
+			return err
+		}
+		if signing {
+			if err := verifySignature(tmpDir, name); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("  --> %s verified\n", name)
+	}
+
+	fmt.Printf("--> Verified %d assets for %s %s\n", len(assets), mixinName, version)
+	return nil
+}
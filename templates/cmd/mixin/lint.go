@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/getporter/skeletor/pkg/mixin" // Import the original pkg path, generator will replace this string
+)
+
+// buildLintCommand returns the `lint` command `porter mixins lint skeletor`
+// invokes, printing Mixin.Lint's results as JSON the same way Porter's own
+// linter aggregates every other mixin's findings.
+func buildLintCommand(m *mixin.Mixin) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Check this mixin's declared Porter version compatibility", // Use original name, generator will replace this string
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := m.Lint()
+			if err != nil {
+				return err
+			}
+			encoded, err := json.Marshal(results)
+			if err != nil {
+				return fmt.Errorf("failed to encode lint results: %w", err)
+			}
+			fmt.Println(string(encoded))
+			return nil
+		},
+	}
+}
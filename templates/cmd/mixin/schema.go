@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/getporter/skeletor/pkg/mixin" // Import the original pkg path, generator will replace this string
+)
+
+// buildSchemaCommand returns the `schema` command porter schema's mixin
+// aggregation shells out to, printing m.Schema()'s embedded JSON Schema --
+// the same command surface buildVersionCommand exposes for PrintVersion.
+func buildSchemaCommand(m *mixin.Mixin) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema for skeletor's install/upgrade/invoke/uninstall steps", // Use original name, generator will replace this string
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return m.Schema()
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package mixin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"get.porter.sh/porter/pkg/linter"
+)
+
+// SupportedPorterVersions is the semver constraint (Masterminds/semver/v3
+// syntax) this mixin declares support for. Lint checks the ambient Porter
+// runtime version against it, mirroring Porter's own linter's mixin
+// version-constraint check. Generator will replace this string.
+var SupportedPorterVersions = ">=1.0.0, <2.0.0"
+
+// Lint checks the ambient Porter runtime version against
+// SupportedPorterVersions, returning a linter.Results entry (Level=Error,
+// Code="skeletor-100") when it's out of range, or an empty Results when
+// compatible.
+func (m *Mixin) Lint() (linter.Results, error) {
+	constraint, err := semver.NewConstraint(SupportedPorterVersions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SupportedPorterVersions constraint %q: %w", SupportedPorterVersions, err)
+	}
+
+	installed, err := ambientPorterVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := semver.NewVersion(installed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Porter version %q: %w", installed, err)
+	}
+
+	if constraint.Check(version) {
+		return nil, nil
+	}
+
+	return linter.Results{
+		{
+			Level: linter.LevelError,
+			Code:  "skeletor-100", // Use original name, generator will replace this string
+			Title: "Unsupported Porter version",
+			Message: fmt.Sprintf("installed Porter version %s does not satisfy this mixin's supported range %s",
+				installed, SupportedPorterVersions),
+		},
+	}, nil
+}
+
+// ambientPorterVersion reads PORTER_VERSION if set, otherwise shells out to
+// `porter version --output json` and extracts its "version" field.
+func ambientPorterVersion() (string, error) {
+	if v := os.Getenv("PORTER_VERSION"); v != "" {
+		return strings.TrimPrefix(v, "v"), nil
+	}
+
+	out, err := exec.Command("porter", "version", "--output", "json").Output() // #nosec G204 -- fixed command and args, no user input
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the installed Porter version: %w", err)
+	}
+
+	var payload struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse `porter version --output json` output: %w", err)
+	}
+	if payload.Version == "" {
+		return "", fmt.Errorf("`porter version --output json` did not report a version")
+	}
+	return strings.TrimPrefix(payload.Version, "v"), nil
+}
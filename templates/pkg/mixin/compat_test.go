@@ -0,0 +1,29 @@
+package mixin
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/linter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint_ConstraintSatisfied(t *testing.T) {
+	t.Setenv("PORTER_VERSION", "1.2.3")
+	m := &Mixin{}
+
+	results, err := m.Lint()
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestLint_ConstraintViolation(t *testing.T) {
+	t.Setenv("PORTER_VERSION", "0.38.0")
+	m := &Mixin{}
+
+	results, err := m.Lint()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, linter.LevelError, results[0].Level)
+	assert.Equal(t, "skeletor-100", results[0].Code)
+}
@@ -0,0 +1,19 @@
+package mixin
+
+import (
+	_ "embed" // Import for side-effect of //go:embed
+)
+
+// schemaBytes is the mixin's JSON Schema, describing the install/upgrade/
+// invoke/uninstall step shapes `porter schema` folds into the manifest
+// schema's mixin-specific definitions. Generator will replace this string.
+//
+//go:embed schema/skeletor.json
+var schemaBytes []byte
+
+// Schema writes the mixin's embedded JSON Schema to m.Out, the same
+// embed-and-print pattern PrintVersion uses for its metadata.
+func (m *Mixin) Schema() error {
+	_, err := m.Out.Write(schemaBytes)
+	return err
+}
@@ -0,0 +1,49 @@
+package mixin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// sampleInstallStepYAML is a minimal install step a generated manifest might
+// author against this mixin's schema.
+const sampleInstallStepYAML = `
+skeletor:
+  description: "Install the thing"
+  arguments:
+    - "--flag"
+  outputs:
+    - name: "thingId"
+`
+
+func TestSchema_ValidatesSampleStep(t *testing.T) {
+	var step map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(sampleInstallStepYAML), &step))
+
+	stepJSON, err := json.Marshal(step)
+	require.NoError(t, err)
+
+	schemaLoader := gojsonschema.NewBytesLoader(schemaBytes)
+	documentLoader := gojsonschema.NewBytesLoader(stepJSON)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "expected sample step to satisfy the schema, got errors: %v", result.Errors())
+}
+
+func TestSchema_RejectsStepMissingMixinKey(t *testing.T) {
+	stepJSON, err := json.Marshal(map[string]interface{}{"description": "missing the skeletor key"})
+	require.NoError(t, err)
+
+	schemaLoader := gojsonschema.NewBytesLoader(schemaBytes)
+	documentLoader := gojsonschema.NewBytesLoader(stepJSON)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	require.NoError(t, err)
+	assert.False(t, result.Valid())
+}